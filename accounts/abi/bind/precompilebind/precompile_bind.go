@@ -33,22 +33,44 @@ package precompilebind
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/shubhamdubey02/subnet-evm/accounts/abi"
 	"github.com/shubhamdubey02/subnet-evm/accounts/abi/bind"
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
 )
 
-var errNoAnonymousEvent = errors.New("event type must not be anonymous")
+var (
+	errNoAnonymousEvent   = errors.New("event type must not be anonymous")
+	errInvalidUpgradeName = errors.New("upgrade name must be an alphanumeric identifier")
+	errUnknownUpgrade     = errors.New("upgrade is not a network upgrade of precompileconfig.ChainConfig")
+	errUnknownUpgradeFunc = errors.New("upgrade function does not exist in the ABI")
+	errNoUpgrade          = errors.New("upgrade functions require an upgrade name")
+
+	upgradeNameRegex = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9]*$")
+)
 
 const (
-	ContractFileName     = "contract.go"
-	ConfigFileName       = "config.go"
-	ModuleFileName       = "module.go"
-	EventFileName        = "event.go"
-	ContractTestFileName = "contract_test.go"
-	ConfigTestFileName   = "config_test.go"
+	ContractFileName      = "contract.go"
+	ConfigFileName        = "config.go"
+	ModuleFileName        = "module.go"
+	EventFileName         = "event.go"
+	ContractTestFileName  = "contract_test.go"
+	ConfigTestFileName    = "config_test.go"
+	PredicateTestFileName = "predicate_test.go"
+
+	// BindingsPackage is the package the Go bindings of the precompile are generated into,
+	// relative to the precompile package.
+	BindingsPackage        = "bindings"
+	BindingsFileName       = BindingsPackage + "/bindings.go"
+	BindingsHelperFileName = BindingsPackage + "/precompile.go"
+
+	// ContractsTestDir is the directory the contract test stubs are generated into, relative
+	// to the precompile package.
+	ContractsTestDir = "contracts"
 )
 
 type PrecompileBindFile struct {
@@ -70,18 +92,35 @@ func NewPrecompileBindFile(fileName string, content string, isTest bool) Precomp
 
 // PrecompileBind generates a Go binding for a precompiled contract. It returns a slice of
 // PrecompileBindFile structs containing the file name and its contents.
-func PrecompileBind(types []string, abiData string, bytecodes []string, fsigs []map[string]string, pkg string, lang bind.Lang, libs map[string]string, aliases map[string]string, abifilename string, generateTests bool) ([]PrecompileBindFile, error) {
-	// create hooks
-	configHook := createPrecompileHook(abifilename, tmplSourcePrecompileConfigGo)
-	contractHook := createPrecompileHook(abifilename, tmplSourcePrecompileContractGo)
-	moduleHook := createPrecompileHook(abifilename, tmplSourcePrecompileModuleGo)
-	eventHook := createPrecompileHook(abifilename, tmplSourcePrecompileEventGo)
-	configTestHook := createPrecompileHook(abifilename, tmplSourcePrecompileConfigTestGo)
-	contractTestHook := createPrecompileHook(abifilename, tmplSourcePrecompileContractTestGo)
-
+// If [predicater] is set, the generated config implements precompileconfig.Predicater.
+// If [accepter] is set, the generated config implements precompileconfig.Accepter.
+// If [upgrade] is set, the generated precompile decodes inputs in strict mode while
+// precompileconfig.ChainConfig.Is<upgrade> is false for the block timestamp, and enables
+// [upgradeFuncs] once it is true. The generated config carries no activation timestamp of
+// its own, so [upgrade] must be a network upgrade exposed by precompileconfig.ChainConfig.
+func PrecompileBind(types []string, abiData string, bytecodes []string, fsigs []map[string]string, pkg string, lang bind.Lang, libs map[string]string, aliases map[string]string, abifilename string, predicater bool, accepter bool, upgrade string, upgradeFuncs []string, generateTests bool) ([]PrecompileBindFile, error) {
 	if err := verifyABI(abiData); err != nil {
 		return nil, err
 	}
+	if err := verifyUpgrade(abiData, upgrade, upgradeFuncs); err != nil {
+		return nil, err
+	}
+	if upgrade != "" {
+		upgrade = abi.ToCamelCase(upgrade)
+	}
+	hookUpgradeFuncs := make(map[string]bool, len(upgradeFuncs))
+	for _, name := range upgradeFuncs {
+		hookUpgradeFuncs[name] = true
+	}
+
+	// create hooks
+	configHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileConfigGo)
+	contractHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileContractGo)
+	moduleHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileModuleGo)
+	eventHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileEventGo)
+	configTestHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileConfigTestGo)
+	contractTestHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompileContractTestGo)
+	predicateTestHook := createPrecompileHook(abifilename, predicater, accepter, upgrade, hookUpgradeFuncs, tmplSourcePrecompilePredicateTestGo)
 
 	abis := []string{abiData}
 
@@ -108,6 +147,23 @@ func PrecompileBind(types []string, abiData string, bytecodes []string, fsigs []
 	result = append(result, NewPrecompileBindFile(ModuleFileName, moduleBind, false))
 	result = append(result, NewPrecompileBindFile(EventFileName, eventBind, false))
 
+	solidityInterface, err := PrecompileSolidityInterface(types[0], abiData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate solidity interface: %w", err)
+	}
+	result = append(result, NewPrecompileBindFile(SolidityInterfaceFileName(types[0]), solidityInterface, false))
+
+	bindings, err := bind.Bind(types, abis, bytecodes, fsigs, BindingsPackage, lang, libs, aliases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate go bindings: %w", err)
+	}
+	result = append(result, NewPrecompileBindFile(BindingsFileName, bindings, false))
+	bindingsHelper, err := bind.BindHelper(types, abis, bytecodes, fsigs, BindingsPackage, lang, libs, aliases, bindingsHook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate go bindings helpers: %w", err)
+	}
+	result = append(result, NewPrecompileBindFile(BindingsHelperFileName, bindingsHelper, false))
+
 	if generateTests {
 		configTestBind, err := bind.BindHelper(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, configTestHook)
 		if err != nil {
@@ -120,13 +176,29 @@ func PrecompileBind(types []string, abiData string, bytecodes []string, fsigs []
 			return nil, fmt.Errorf("failed to generate contract test binding: %w", err)
 		}
 		result = append(result, NewPrecompileBindFile(ContractTestFileName, contractTestBind, true))
+
+		solidityTest, hardhatTest, err := PrecompileContractTests(types[0], abiData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate contract tests: %w", err)
+		}
+		solidityTestFileName, hardhatTestFileName := ContractTestFileNames(types[0])
+		result = append(result, NewPrecompileBindFile(solidityTestFileName, solidityTest, true))
+		result = append(result, NewPrecompileBindFile(hardhatTestFileName, hardhatTest, true))
+
+		if predicater {
+			predicateTestBind, err := bind.BindHelper(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, predicateTestHook)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate predicate test binding: %w", err)
+			}
+			result = append(result, NewPrecompileBindFile(PredicateTestFileName, predicateTestBind, true))
+		}
 	}
 
 	return result, nil
 }
 
 // createPrecompileHook creates a bind hook for precompiled contracts.
-func createPrecompileHook(abifilename string, template string) bind.BindHook {
+func createPrecompileHook(abifilename string, predicater bool, accepter bool, upgrade string, upgradeFuncs map[string]bool, template string) bind.BindHook {
 	return func(lang bind.Lang, pkg string, types []string, contracts map[string]*bind.TmplContract, structs map[string]*bind.TmplStruct) (interface{}, string, error) {
 		// verify first
 		if lang != bind.LangGo {
@@ -163,6 +235,10 @@ func createPrecompileHook(abifilename string, template string) bind.BindHook {
 		precompileContract := &tmplPrecompileContract{
 			TmplContract: contract,
 			AllowList:    isAllowList,
+			Predicater:   predicater,
+			Accepter:     accepter,
+			Upgrade:      upgrade,
+			UpgradeFuncs: upgradeFuncs,
 			Funcs:        funcs,
 			ABIFilename:  abifilename,
 		}
@@ -176,6 +252,24 @@ func createPrecompileHook(abifilename string, template string) bind.BindHook {
 	}
 }
 
+// bindingsHook is the bind hook of the helpers generated alongside the Go bindings.
+// Unlike createPrecompileHook, it keeps the allow list functions and events since
+// the Go bindings are generated for the full ABI.
+func bindingsHook(lang bind.Lang, pkg string, types []string, contracts map[string]*bind.TmplContract, structs map[string]*bind.TmplStruct) (interface{}, string, error) {
+	if lang != bind.LangGo {
+		return nil, "", errors.New("only GoLang binding for precompiled contracts is supported yet")
+	}
+	if len(types) != 1 {
+		return nil, "", errors.New("cannot generate more than 1 contract")
+	}
+	data := &tmplPrecompileData{
+		Contract: &tmplPrecompileContract{TmplContract: contracts[types[0]]},
+		Structs:  structs,
+		Package:  pkg,
+	}
+	return data, tmplSourcePrecompileBindingsGo, nil
+}
+
 func allowListEnabled(funcs map[string]*bind.TmplMethod) bool {
 	for key := range allowlist.AllowListABI.Methods {
 		if _, ok := funcs[key]; !ok {
@@ -185,6 +279,43 @@ func allowListEnabled(funcs map[string]*bind.TmplMethod) bool {
 	return true
 }
 
+// verifyUpgrade verifies that [upgrade] is a network upgrade the precompile can check
+// through precompileconfig.ChainConfig and that [upgradeFuncs] are functions of the precompile.
+// Only the upgrades with an Is<Upgrade> method in precompileconfig.ChainConfig are accepted,
+// so a future network upgrade must be added there before a precompile can be gated on it.
+func verifyUpgrade(abiData string, upgrade string, upgradeFuncs []string) error {
+	if upgrade == "" {
+		if len(upgradeFuncs) != 0 {
+			return errNoUpgrade
+		}
+		return nil
+	}
+	if !upgradeNameRegex.MatchString(upgrade) {
+		return fmt.Errorf("%w: %s", errInvalidUpgradeName, upgrade)
+	}
+	chainConfigType := reflect.TypeOf((*precompileconfig.ChainConfig)(nil)).Elem()
+	if _, ok := chainConfigType.MethodByName("Is" + upgrade); !ok {
+		return fmt.Errorf("%w: %s", errUnknownUpgrade, upgrade)
+	}
+	evmABI, err := abi.JSON(strings.NewReader(abiData))
+	if err != nil {
+		return err
+	}
+	funcs := make(map[string]*bind.TmplMethod, len(evmABI.Methods))
+	for name := range evmABI.Methods {
+		funcs[name] = nil
+	}
+	isAllowList := allowListEnabled(funcs)
+	for _, name := range upgradeFuncs {
+		// Allow list functions are created by the allowlist package and cannot be gated.
+		_, isAllowListFunc := allowlist.AllowListABI.Methods[name]
+		if _, ok := evmABI.Methods[name]; !ok || (isAllowList && isAllowListFunc) {
+			return fmt.Errorf("%w: %s", errUnknownUpgradeFunc, name)
+		}
+	}
+	return nil
+}
+
 func verifyABI(abiData string) error {
 	// check abi first
 	evmABI, err := abi.JSON(strings.NewReader(abiData))
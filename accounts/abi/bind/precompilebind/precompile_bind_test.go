@@ -48,6 +48,10 @@ var bindTests = []struct {
 	tester          string
 	errMsg          string
 	expectAllowlist bool
+	predicater      bool
+	accepter        bool
+	upgrade         string
+	upgradeFuncs    []string
 }{
 	{
 		"AnonOutputChecker",
@@ -61,6 +65,10 @@ var bindTests = []struct {
 		"",
 		"ABI outputs for anonOutput require a name to generate the precompile binding, re-generate the ABI from a Solidity source file with all named outputs",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		"AnonOutputsChecker",
@@ -74,6 +82,10 @@ var bindTests = []struct {
 		"",
 		"ABI outputs for anonOutputs require a name to generate the precompile binding, re-generate the ABI from a Solidity source file with all named outputs",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		"MixedOutputsChecker",
@@ -87,6 +99,10 @@ var bindTests = []struct {
 		"",
 		"ABI outputs for mixedOutputs require a name to generate the precompile binding, re-generate the ABI from a Solidity source file with all named outputs",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	// Test that module is generated correctly
 	{
@@ -97,6 +113,10 @@ var bindTests = []struct {
 		"",
 		"no ABI methods found",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	// Test that named and anonymous inputs are handled correctly
 	{
@@ -135,6 +155,10 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	// Test that named and anonymous outputs are handled correctly
 	{
@@ -169,6 +193,10 @@ var bindTests = []struct {
 			`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`Tupler`,
@@ -192,6 +220,10 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`Slicer`,
@@ -268,6 +300,10 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`Fallback`,
@@ -295,6 +331,10 @@ var bindTests = []struct {
 			`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`Structs`,
@@ -330,6 +370,10 @@ var bindTests = []struct {
 			`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`Underscorer`,
@@ -356,6 +400,10 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`OutputCollision`,
@@ -368,6 +416,10 @@ var bindTests = []struct {
 		"",
 		"normalized output name is duplicated",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 
 	{
@@ -381,6 +433,10 @@ var bindTests = []struct {
 		"",
 		"normalized input name is duplicated",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`DeeplyNestedArray`,
@@ -424,6 +480,10 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		"RangeKeyword",
@@ -437,6 +497,10 @@ var bindTests = []struct {
 		"",
 		"input name func is a keyword",
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`HelloWorld`,
@@ -474,6 +538,10 @@ var bindTests = []struct {
 		`,
 		"",
 		true,
+		false,
+		false,
+		"",
+		nil,
 	},
 	{
 		`HelloWorldNoAL`,
@@ -500,6 +568,74 @@ var bindTests = []struct {
 		`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
+	},
+	// Test that predicate verification and accept hooks are generated correctly
+	{
+		`Oracle`,
+		`interface IOracle {
+			// readPrice returns the price attested by the predicate at index
+			function readPrice(uint32 index) external view returns (uint256 price);
+		}
+		`,
+		`[{"inputs":[{"internalType":"uint32","name":"index","type":"uint32"}],"name":"readPrice","outputs":[{"internalType":"uint256","name":"price","type":"uint256"}],"stateMutability":"view","type":"function"}]`,
+		`"github.com/stretchr/testify/require"
+		 "github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+		 "github.com/shubhamdubey02/subnet-evm/predicate"
+		 "github.com/shubhamdubey02/subnet-evm/utils"
+		`,
+		`
+			var config precompileconfig.Config = NewConfig(utils.NewUint64(0))
+			predicater, ok := config.(precompileconfig.Predicater)
+			require.True(t, ok)
+			_, ok = config.(precompileconfig.Accepter)
+			require.True(t, ok)
+
+			predicateBytes := predicate.PackPredicate([]byte("price"))
+			gas, err := predicater.PredicateGas(predicateBytes)
+			require.NoError(t, err)
+			require.Equal(t, PredicateBaseGasCost+PredicateGasCostPerByte*uint64(len(predicateBytes)), gas)
+			require.NoError(t, predicater.VerifyPredicate(nil, predicateBytes))
+			require.ErrorIs(t, predicater.VerifyPredicate(nil, []byte{1}), ErrInvalidPredicateBytes)
+		`,
+		"",
+		false,
+		true,
+		true,
+		"",
+		nil,
+	},
+	{
+		`OracleAL`,
+		`interface IOracle is IAllowList {
+			// sayHello returns the stored greeting string
+			function sayHello() external view returns (string calldata result);
+
+			// setGreeting  stores the greeting string
+			function setGreeting(string calldata response) external;
+		}
+		`,
+		`[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		`"github.com/stretchr/testify/require"
+		 "github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+		 "github.com/shubhamdubey02/subnet-evm/utils"
+		`,
+		`
+			var config precompileconfig.Config = NewConfig(utils.NewUint64(0), nil, nil, nil)
+			_, ok := config.(precompileconfig.Predicater)
+			require.True(t, ok)
+			_, ok = config.(precompileconfig.Accepter)
+			require.False(t, ok)
+		`,
+		"",
+		true,
+		true,
+		false,
+		"",
+		nil,
 	},
 	{
 		`IEventer`,
@@ -593,6 +729,113 @@ var bindTests = []struct {
 	`,
 		"",
 		false,
+		false,
+		false,
+		"",
+		nil,
+	},
+	// Test that upgrade-gated strict mode and functions are generated correctly
+	{
+		`Counter`,
+		`interface ICounter {
+			function get() external view returns (uint256 value);
+			function increment(uint256 amount) external;
+			function reset() external;
+		}
+		`,
+		`[{"inputs":[],"name":"get","outputs":[{"internalType":"uint256","name":"value","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"amount","type":"uint256"}],"name":"increment","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"reset","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		`"github.com/stretchr/testify/require"
+		 "math/big"
+		`,
+		`
+			packedInput, err := PackIncrement(big.NewInt(1))
+			require.NoError(t, err)
+			// strict mode rejects inputs that are not a multiple of 32 bytes
+			paddedInput := append(packedInput[4:], 0)
+			_, err = UnpackIncrementInput(paddedInput, true)
+			require.Error(t, err)
+			amount, err := UnpackIncrementInput(paddedInput, false)
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(1), amount)
+		`,
+		"",
+		false,
+		false,
+		false,
+		"Durango",
+		[]string{"reset"},
+	},
+	{
+		`CounterAL`,
+		`interface ICounter is IAllowList {
+			function sayHello() external view returns (string calldata result);
+			function setGreeting(string calldata response) external;
+		}
+		`,
+		`[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		`"github.com/stretchr/testify/require"`,
+		`
+			_, err := UnpackSetGreetingInput([]byte{}, true)
+			require.Error(t, err)
+		`,
+		"",
+		true,
+		false,
+		false,
+		"Durango",
+		[]string{"setGreeting", "sayHello"},
+	},
+	{
+		`CounterUnknownUpgradeFunc`,
+		``,
+		`[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		``,
+		``,
+		errUnknownUpgradeFunc.Error(),
+		false,
+		false,
+		false,
+		"Durango",
+		[]string{"setAdmin"},
+	},
+	{
+		`CounterUnknownUpgrade`,
+		``,
+		`[{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"}]`,
+		``,
+		``,
+		errUnknownUpgrade.Error(),
+		false,
+		false,
+		false,
+		"V2",
+		nil,
+	},
+	{
+		`CounterInvalidUpgradeName`,
+		``,
+		`[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		``,
+		``,
+		errInvalidUpgradeName.Error(),
+		false,
+		false,
+		false,
+		"2upgrade",
+		nil,
+	},
+	{
+		`CounterNoUpgrade`,
+		``,
+		`[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+		``,
+		``,
+		errNoUpgrade.Error(),
+		false,
+		false,
+		false,
+		"",
+		[]string{"setGreeting"},
 	},
 	{
 		`IEventerAnonymous`,
@@ -607,6 +850,10 @@ var bindTests = []struct {
 		``,
 		errNoAnonymousEvent.Error(),
 		false,
+		false,
+		false,
+		"",
+		nil,
 	},
 }
 
@@ -631,7 +878,7 @@ func TestPrecompileBind(t *testing.T) {
 			types := []string{tt.name}
 
 			// Generate the binding and create a Go source file in the workspace
-			bindedFiles, err := PrecompileBind(types, tt.abi, []string{""}, nil, tt.name, bind.LangGo, nil, nil, "contract.abi", true)
+			bindedFiles, err := PrecompileBind(types, tt.abi, []string{""}, nil, tt.name, bind.LangGo, nil, nil, "contract.abi", tt.predicater, tt.accepter, tt.upgrade, tt.upgradeFuncs, true)
 			if tt.errMsg != "" {
 				require.ErrorContains(t, err, tt.errMsg)
 				return
@@ -653,10 +900,19 @@ func TestPrecompileBind(t *testing.T) {
 					} else {
 						require.NotContains(t, file.Content, "allowlist.CreateAllowListFunctions(", "generated contract contains AllowListFunctions")
 					}
+					// check if the predicate helper is generated
+					if tt.predicater {
+						require.Contains(t, file.Content, "func getVerifiedPredicate(", "generated contract does not contain getVerifiedPredicate")
+					} else {
+						require.NotContains(t, file.Content, "func getVerifiedPredicate(", "generated contract contains getVerifiedPredicate")
+					}
 				case ModuleFileName:
 					// change address to a suitable one for testing
 					file.Content = strings.Replace(file.Content, `common.HexToAddress("{ASUITABLEHEXADDRESS}")`, `common.HexToAddress("0x03000000000000000000000000000000000000ff")`, 1)
 				}
+				if err := os.MkdirAll(filepath.Dir(filepath.Join(precompilePath, file.FileName)), 0o700); err != nil {
+					t.Fatalf("failed to create package: %v", err)
+				}
 				if err = os.WriteFile(filepath.Join(precompilePath, file.FileName), []byte(file.Content), 0o600); err != nil {
 					t.Fatalf("test %d: failed to write binding: %v", i, err)
 				}
@@ -707,3 +963,83 @@ func TestPrecompileBind(t *testing.T) {
 		t.Fatalf("failed to run binding test: %v\n%s", err, out)
 	}
 }
+
+func TestPrecompileSolidityInterface(t *testing.T) {
+	tests := map[string]struct {
+		abi      string
+		expected string
+	}{
+		"structs and events": {
+			abi: `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"components":[{"internalType":"uint256","name":"price","type":"uint256"},{"components":[{"internalType":"string","name":"symbol","type":"string"}],"internalType":"struct IOracle.Asset","name":"asset","type":"tuple"}],"indexed":false,"internalType":"struct IOracle.Quote","name":"quote","type":"tuple"}],"name":"QuoteSet","type":"event"},{"inputs":[{"internalType":"string","name":"symbol","type":"string"}],"name":"getQuote","outputs":[{"components":[{"internalType":"uint256","name":"price","type":"uint256"},{"components":[{"internalType":"string","name":"symbol","type":"string"}],"internalType":"struct IOracle.Asset","name":"asset","type":"tuple"}],"internalType":"struct IOracle.Quote","name":"quote","type":"tuple"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32[2]","name":"ids","type":"bytes32[2]"},{"internalType":"uint256[]","name":"prices","type":"uint256[]"}],"name":"setPrices","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			expected: `//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// Code generated
+// This file is a generated Solidity interface of the Oracle precompile.
+// It should be kept in sync with the ABI the precompile was generated from.
+interface IOracle {
+  struct Asset {
+    string symbol;
+  }
+  struct Quote {
+    uint256 price;
+    Asset asset;
+  }
+  event QuoteSet(address indexed sender, Quote quote);
+
+  function getQuote(string calldata symbol) external view returns (Quote memory quote);
+
+  function setPrices(bytes32[2] calldata ids, uint256[] calldata prices) external;
+}
+`,
+		},
+		"allow list": {
+			abi: `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"role","type":"uint256"},{"indexed":true,"internalType":"address","name":"account","type":"address"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":false,"internalType":"uint256","name":"oldRole","type":"uint256"}],"name":"RoleSet","type":"event"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"},{"stateMutability":"payable","type":"fallback"}]`,
+			expected: `//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+import "./IAllowList.sol";
+
+// Code generated
+// This file is a generated Solidity interface of the Oracle precompile.
+// It should be kept in sync with the ABI the precompile was generated from.
+interface IOracle is IAllowList {
+
+  function setGreeting(string calldata response) external;
+
+  fallback() external payable;
+}
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			solidityInterface, err := PrecompileSolidityInterface("Oracle", test.abi)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, solidityInterface)
+		})
+	}
+}
+
+func TestPrecompileContractTests(t *testing.T) {
+	require := require.New(t)
+
+	abi := `[{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"readAllowList","outputs":[{"internalType":"uint256","name":"role","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setAdmin","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setEnabled","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setManager","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"addr","type":"address"}],"name":"setNone","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"string","name":"response","type":"string"}],"name":"setGreeting","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"sayHello","outputs":[{"internalType":"string","name":"result","type":"string"}],"stateMutability":"view","type":"function"}]`
+	solidityTest, hardhatTest, err := PrecompileContractTests("HelloWorld", abi)
+	require.NoError(err)
+
+	require.Contains(solidityTest, `import "../interfaces/IHelloWorld.sol";`)
+	require.Contains(solidityTest, "contract HelloWorldTest is AllowListTest {")
+	require.Contains(solidityTest, "function step_sayHello() public {")
+	require.Contains(solidityTest, "function step_setGreeting() public {")
+	// The allow list functions are tested by the allow list tests.
+	require.NotContains(solidityTest, "step_setAdmin")
+
+	require.Contains(hardhatTest, `ethers.getContractFactory("HelloWorldTest", { signer })`)
+	require.Contains(hardhatTest, `precompile.setAdmin(this.testContract.address)`)
+	require.Contains(hardhatTest, `test("should call sayHello", "step_sayHello")`)
+	require.Contains(hardhatTest, `test("should call setGreeting", "step_setGreeting")`)
+
+	solidityTestFileName, hardhatTestFileName := ContractTestFileNames("HelloWorld")
+	require.Equal("contracts/HelloWorldTest.sol", solidityTestFileName)
+	require.Equal("contracts/helloworld.ts", hardhatTestFileName)
+}
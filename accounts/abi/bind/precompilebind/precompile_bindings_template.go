@@ -0,0 +1,69 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompilebind
+
+// tmplSourcePrecompileBindingsGo is the Go source template of the helpers generated
+// alongside the Go bindings of a precompile.
+const tmplSourcePrecompileBindingsGo = `
+// Code generated
+// This file is generated alongside the Go bindings of the {{.Contract.Type}} precompile in bindings.go.
+// The file is generated by a template. Please inspect every code and comment in this file before use.
+
+package {{.Package}}
+
+import (
+	{{- if .Contract.Events}}
+	"errors"
+	"fmt"
+	{{- end}}
+
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi/bind"
+	{{- if .Contract.Events}}
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	{{- end}}
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+{{$contract := .Contract}}
+// {{.Contract.Type}}Address is the address the {{.Contract.Type}} precompile is activated at.
+// This must be the same address as ContractAddress in module.go of the precompile.
+var {{.Contract.Type}}Address = common.HexToAddress("{ASUITABLEHEXADDRESS}") // SET A SUITABLE HEX ADDRESS HERE
+
+// New{{.Contract.Type}}Precompile creates a new instance of {{.Contract.Type}}, bound to the
+// {{.Contract.Type}} precompile at {{.Contract.Type}}Address.
+func New{{.Contract.Type}}Precompile(backend bind.ContractBackend) (*{{.Contract.Type}}, error) {
+	return New{{.Contract.Type}}({{.Contract.Type}}Address, backend)
+}
+{{- if .Contract.Events}}
+
+// Parse{{.Contract.Type}}Log decodes [log] emitted by the {{.Contract.Type}} precompile into
+// the binding of its event, e.g.
+{{- range .Contract.Events}} *{{$contract.Type}}{{.Normalized.Name}}{{break}}{{end}}.
+func Parse{{.Contract.Type}}Log(log types.Log) (interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("cannot parse log without topics")
+	}
+	parsed, err := {{.Contract.Type}}MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	event, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := New{{.Contract.Type}}Filterer(log.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch event.Name {
+	{{- range .Contract.Events}}
+	case "{{.Original.Name}}":
+		return filterer.Parse{{.Normalized.Name}}(log)
+	{{- end}}
+	default:
+		return nil, fmt.Errorf("unknown {{.Contract.Type}} event %s", event.Name)
+	}
+}
+{{- end}}
+`
@@ -11,16 +11,50 @@ const tmplSourcePrecompileConfigGo = `
 package {{.Package}}
 
 import (
+	{{- if .Contract.Predicater}}
+	"errors"
+	"fmt"
+	{{- end}}
+
 	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
 	{{- if .Contract.AllowList}}
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
-
+	{{- end}}
+	{{- if .Contract.Predicater}}
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	{{- end}}
+	{{- if or .Contract.AllowList .Contract.Predicater .Contract.Accepter}}{{"\n"}}{{- end}}
+	{{- if or .Contract.AllowList .Contract.Accepter}}
 	"github.com/ethereum/go-ethereum/common"
 	{{- end}}
-
+	{{- if .Contract.Predicater}}
+	"github.com/ethereum/go-ethereum/common/math"
+	{{- end}}
+	{{- if .Contract.Accepter}}
+	"github.com/ethereum/go-ethereum/log"
+	{{- end}}
 )
 
 var _ precompileconfig.Config = &Config{}
+{{- if .Contract.Predicater}}
+var _ precompileconfig.Predicater = &Config{}
+{{- end}}
+{{- if .Contract.Accepter}}
+var _ precompileconfig.Accepter = &Config{}
+{{- end}}
+{{- if .Contract.Predicater}}
+
+const (
+	// Gas costs for predicate verification. These are charged as part of the intrinsic gas
+	// of every transaction that includes a predicate for {{.Contract.Type}} in its access list.
+	// You should set gas costs that cover the work done in VerifyPredicate,
+	// otherwise your network may be vulnerable to DoS attacks.
+	PredicateBaseGasCost    uint64 = 1 // SET A GAS COST HERE
+	PredicateGasCostPerByte uint64 = 1 // SET A GAS COST HERE
+)
+
+var ErrInvalidPredicateBytes = errors.New("cannot unpack predicate bytes")
+{{- end}}
 
 // Config implements the precompileconfig.Config interface and
 // adds specific configuration for {{.Contract.Type}}.
@@ -90,4 +124,51 @@ func (c *Config) Equal(s precompileconfig.Config) bool {
 	equals := c.Upgrade.Equal(&other.Upgrade) {{- if .Contract.AllowList}} && c.AllowListConfig.Equal(&other.AllowListConfig) {{end}}
 	return equals
 }
+{{- if .Contract.Predicater}}
+
+// PredicateGas returns the amount of gas necessary to verify [predicateBytes].
+// The gas is charged while computing the intrinsic gas of the transaction, so
+// returning an error invalidates the transaction.
+func (c *Config) PredicateGas(predicateBytes []byte) (uint64, error) {
+	bytesGasCost, overflow := math.SafeMul(PredicateGasCostPerByte, uint64(len(predicateBytes)))
+	if overflow {
+		return 0, fmt.Errorf("overflow calculating gas cost for predicate bytes of size %d", len(predicateBytes))
+	}
+	totalGas, overflow := math.SafeAdd(PredicateBaseGasCost, bytesGasCost)
+	if overflow {
+		return 0, fmt.Errorf("overflow adding bytes gas cost of size %d", len(predicateBytes))
+	}
+	// CUSTOM CODE STARTS HERE
+	// Add the gas cost of any additional work done in VerifyPredicate here
+	return totalGas, nil
+}
+
+// VerifyPredicate returns nil if [predicateBytes] passes verification.
+// A predicate that fails verification does not invalidate the transaction.
+// Instead, its index is marked as failed in the predicate results of the block,
+// which the contract reads through getVerifiedPredicate.
+func (c *Config) VerifyPredicate(predicateContext *precompileconfig.PredicateContext, predicateBytes []byte) error {
+	unpackedPredicateBytes, err := predicate.UnpackPredicate(predicateBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPredicateBytes, err)
+	}
+	// CUSTOM CODE STARTS HERE
+	// Verify [unpackedPredicateBytes] against [predicateContext] here
+	// and return an error accordingly
+	_ = unpackedPredicateBytes // CUSTOM CODE OPERATES ON PREDICATE
+	return nil
+}
+{{- end}}
+{{- if .Contract.Accepter}}
+
+// Accept is called once for every log emitted by {{.Contract.Type}} when the block
+// containing it is accepted. Accept must not modify the state of the chain, it is
+// meant to notify off-chain components (e.g. through [acceptCtx].Warp) of accepted logs.
+func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, blockHash common.Hash, blockNumber uint64, txHash common.Hash, logIndex int, topics []common.Hash, logData []byte) error {
+	// CUSTOM CODE STARTS HERE
+	// Add your own code to handle accepted logs here
+	log.Debug("accepted {{.Contract.Type}} log", "blockHash", blockHash, "blockNumber", blockNumber, "txHash", txHash, "logIndex", logIndex)
+	return nil
+}
+{{- end}}
 `
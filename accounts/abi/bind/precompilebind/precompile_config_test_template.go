@@ -18,6 +18,11 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/utils"
 	{{- if .Contract.AllowList}}
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	{{- end}}
+	{{- if .Contract.Accepter}}
+	"github.com/stretchr/testify/require"
+	{{- end}}
+	{{- if or .Contract.AllowList .Contract.Accepter}}
 
 	"github.com/ethereum/go-ethereum/common"
 	{{- end}}
@@ -104,4 +109,18 @@ func TestEqual(t *testing.T) {
 		testutils.RunEqualTests(t, tests)
 		{{- end}}
 	}
+{{- if .Contract.Accepter}}
+
+// TestAccept tests the handling of accepted logs by Config.
+func TestAccept(t *testing.T) {
+	config := NewConfig(utils.NewUint64(3){{- if .Contract.AllowList}}, nil, nil, nil{{- end}})
+	acceptCtx := &precompileconfig.AcceptContext{}
+	// CUSTOM CODE STARTS HERE
+	// Replace the topics and data with a log emitted by your precompile
+	// and check the expected side effects of accepting it.
+	topics := []common.Hash{common.HexToHash("0x01")}
+	err := config.Accept(acceptCtx, common.HexToHash("0x02"), 1, common.HexToHash("0x03"), 0, topics, nil)
+	require.NoError(t, err)
+}
+{{- end}}
 `
@@ -14,9 +14,13 @@ type tmplPrecompileData struct {
 // tmplPrecompileContract contains the data needed to generate an individual contract binding.
 type tmplPrecompileContract struct {
 	*bind.TmplContract
-	AllowList   bool                        // Indicator whether the contract uses AllowList precompile
-	Funcs       map[string]*bind.TmplMethod // Contract functions that include both Calls + Transacts in tmplContract
-	ABIFilename string                      // Path to the ABI file
+	AllowList    bool                        // Indicator whether the contract uses AllowList precompile
+	Predicater   bool                        // Indicator whether the config implements precompileconfig.Predicater
+	Accepter     bool                        // Indicator whether the config implements precompileconfig.Accepter
+	Upgrade      string                      // Name of the network upgrade disabling strict mode and enabling UpgradeFuncs, empty if none
+	UpgradeFuncs map[string]bool             // Original names of the functions activated by Upgrade
+	Funcs        map[string]*bind.TmplMethod // Contract functions that include both Calls + Transacts in tmplContract
+	ABIFilename  string                      // Path to the ABI file
 }
 
 // tmplSourcePrecompileContractGo is the Go precompiled contract source template.
@@ -37,10 +41,16 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
 	{{- end}}
 	"github.com/shubhamdubey02/subnet-evm/precompile/contract"
+	{{- if .Contract.Predicater}}
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	{{- end}}
 	"github.com/shubhamdubey02/subnet-evm/vmerrs"
 
 	_ "embed"
 
+	{{- if .Contract.Predicater}}
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	{{- end}}
 	"github.com/ethereum/go-ethereum/common"
 )
 {{$contract := .Contract}}
@@ -137,14 +147,55 @@ func Set{{.Contract.Type}}AllowListStatus(stateDB contract.StateDB, address comm
 }
 {{end}}
 
+{{- if .Contract.Predicater}}
+
+// getVerifiedPredicate returns the unpacked predicate at [index] in the access list
+// of the current transaction for ContractAddress, and whether it exists and passed
+// VerifyPredicate when the block was verified.
+// Predicates are verified before the transaction is executed, so a failed predicate
+// does not revert the transaction. Your functions should handle the invalid case.
+func getVerifiedPredicate(accessibleState contract.AccessibleState, index int) ([]byte, bool) {
+	stateDB := accessibleState.GetStateDB()
+	predicateBytes, exists := stateDB.GetPredicateStorageSlots(ContractAddress, index)
+	predicateResults := accessibleState.GetBlockContext().GetPredicateResults(stateDB.GetTxHash(), ContractAddress)
+	if !exists || set.BitsFromBytes(predicateResults).Contains(index) {
+		return nil, false
+	}
+	// Note: predicates are unpacked during verification, so this should not fail here.
+	unpackedPredicateBytes, err := predicate.UnpackPredicate(predicateBytes)
+	if err != nil {
+		return nil, false
+	}
+	return unpackedPredicateBytes, true
+}
+{{- end}}
+{{- if .Contract.Upgrade}}
+
+// is{{.Contract.Upgrade}}Activated returns true if the {{.Contract.Upgrade}} network upgrade is activated
+// at the current block. The upgrade is scheduled in the chain config, so it also applies to
+// {{.Contract.Type}} if it was enabled before the upgrade, without reconfiguring it.
+// Behavior changed with the upgrade, such as input decoding, new functions or new events, must be
+// gated behind this check so that blocks accepted before the upgrade are re-executed identically.
+func is{{.Contract.Upgrade}}Activated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().Is{{.Contract.Upgrade}}(accessibleState.GetBlockContext().Timestamp())
+}
+{{- end}}
+
 {{range .Contract.Funcs}}
 {{if len .Normalized.Inputs | lt 1}}
 // Unpack{{capitalise .Normalized.Name}}Input attempts to unpack [input] as {{capitalise .Normalized.Name}}Input
 // assumes that [input] does not include selector (omits first 4 func signature bytes)
+{{- if $contract.Upgrade}}
+// if [useStrictMode] is true, it will return an error if the length of [input] is not a multiple of 32 bytes.
+func Unpack{{capitalise .Normalized.Name}}Input(input []byte, useStrictMode bool) ({{capitalise .Normalized.Name}}Input, error) {
+	inputStruct := {{capitalise .Normalized.Name}}Input{}
+	err := {{$contract.Type}}ABI.UnpackInputIntoInterface(&inputStruct, "{{.Original.Name}}", input, useStrictMode)
+{{- else}}
 func Unpack{{capitalise .Normalized.Name}}Input(input []byte) ({{capitalise .Normalized.Name}}Input, error) {
 	inputStruct := {{capitalise .Normalized.Name}}Input{}
 	// The strict mode in decoding is disabled after Durango. You can re-enable by changing the last argument to true.
 	err := {{$contract.Type}}ABI.UnpackInputIntoInterface(&inputStruct, "{{.Original.Name}}", input, false)
+{{- end}}
 
 	return inputStruct, err
 }
@@ -159,9 +210,15 @@ func Pack{{.Normalized.Name}}(inputStruct {{capitalise .Normalized.Name}}Input)
 {{$bindedType := bindtype $input.Type $structs}}
 // Unpack{{capitalise .Normalized.Name}}Input attempts to unpack [input] into the {{$bindedType}} type argument
 // assumes that [input] does not include selector (omits first 4 func signature bytes)
+{{- if $contract.Upgrade}}
+// if [useStrictMode] is true, it will return an error if the length of [input] is not a multiple of 32 bytes.
+func Unpack{{capitalise .Normalized.Name}}Input(input []byte, useStrictMode bool)({{$bindedType}}, error) {
+res, err := {{$contract.Type}}ABI.UnpackInput("{{$method.Original.Name}}", input, useStrictMode)
+{{- else}}
 func Unpack{{capitalise .Normalized.Name}}Input(input []byte)({{$bindedType}}, error) {
 // The strict mode in decoding is disabled after Durango. You can re-enable by changing the last argument to true.
 res, err := {{$contract.Type}}ABI.UnpackInput("{{$method.Original.Name}}", input, false)
+{{- end}}
 if err != nil {
 	return {{bindtypenew $input.Type $structs}}, err
 }
@@ -242,7 +299,13 @@ func {{decapitalise .Normalized.Name}}(accessibleState contract.AccessibleState,
 	// attempts to unpack [input] into the arguments to the {{.Normalized.Name}}Input.
 	// Assumes that [input] does not include selector
 	// You can use unpacked [inputStruct] variable in your code
+	{{- if $contract.Upgrade}}
+	// Inputs are decoded in strict mode until the {{$contract.Upgrade}} upgrade is activated.
+	useStrictMode := !is{{$contract.Upgrade}}Activated(accessibleState)
+	inputStruct, err := Unpack{{capitalise .Normalized.Name}}Input(input, useStrictMode)
+	{{- else}}
 	inputStruct, err := Unpack{{capitalise .Normalized.Name}}Input(input)
+	{{- end}}
 	if err != nil{
 		return nil, remainingGas, err
 	}
@@ -264,6 +327,10 @@ func {{decapitalise .Normalized.Name}}(accessibleState contract.AccessibleState,
 	{{- if len .Normalized.Inputs | ne 0}}
 	_ = inputStruct // CUSTOM CODE OPERATES ON INPUT
 	{{- end}}
+	{{- if $contract.Upgrade}}
+	// Gate behavior added with the {{$contract.Upgrade}} upgrade, e.g. emitting new events, with:
+	// if is{{$contract.Upgrade}}Activated(accessibleState) { ... }
+	{{- end}}
 
 	{{- if len .Normalized.Outputs | eq 0}}
 	// this function does not return an output, leave this one as is
@@ -338,11 +405,26 @@ func create{{.Contract.Type}}Precompile() contract.StatefulPrecompiledContract {
 		{{- end}}
 	}
 
+	{{- if .Contract.UpgradeFuncs}}
+	// Functions added with the {{.Contract.Upgrade}} upgrade. These cannot be called before the upgrade is activated.
+	{{decapitalise .Contract.Upgrade}}Functions := map[string]bool{
+		{{- range $name, $_ := .Contract.UpgradeFuncs}}
+		"{{$name}}": true,
+		{{- end}}
+	}
+	{{- end}}
+
 	for name, function := range abiFunctionMap {
 		method, ok := {{$contract.Type}}ABI.Methods[name]
 		if !ok {
 			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
 		}
+		{{- if .Contract.UpgradeFuncs}}
+		if {{decapitalise .Contract.Upgrade}}Functions[name] {
+			functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, function, is{{.Contract.Upgrade}}Activated))
+			continue
+		}
+		{{- end}}
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
 
@@ -14,14 +14,26 @@ import (
 	"testing"
 	"math/big"
 
+	{{- if .Contract.Upgrade}}
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	{{- end}}
 	"github.com/shubhamdubey02/subnet-evm/core/state"
 	{{- if .Contract.AllowList}}
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
 	{{- end}}
+	{{- if .Contract.Upgrade}}
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	{{- end}}
 	"github.com/shubhamdubey02/subnet-evm/precompile/testutils"
+	{{- if .Contract.UpgradeFuncs}}
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	{{- end}}
 	"github.com/shubhamdubey02/subnet-evm/vmerrs"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+	{{- if .Contract.Upgrade}}
+	"go.uber.org/mock/gomock"
+	{{- end}}
 )
 
 var (
@@ -30,6 +42,23 @@ var (
 	_ = common.Big0
 	_ = require.New
 )
+{{- if .Contract.Upgrade}}
+
+// chainConfigWith{{.Contract.Upgrade}} returns the chain config of a test with the {{.Contract.Upgrade}}
+// network upgrade activated if [activated] is true.
+func chainConfigWith{{.Contract.Upgrade}}(activated bool) func(*gomock.Controller) precompileconfig.ChainConfig {
+	return func(ctrl *gomock.Controller) precompileconfig.ChainConfig {
+		chainConfig := precompileconfig.NewMockChainConfig(ctrl)
+		chainConfig.EXPECT().GetFeeConfig().AnyTimes().Return(commontype.ValidTestFeeConfig)
+		chainConfig.EXPECT().AllowedFeeRecipients().AnyTimes().Return(false)
+		{{- if ne .Contract.Upgrade "Durango"}}
+		chainConfig.EXPECT().IsDurango(gomock.Any()).AnyTimes().Return(true)
+		{{- end}}
+		chainConfig.EXPECT().Is{{.Contract.Upgrade}}(gomock.Any()).AnyTimes().Return(activated)
+		return chainConfig
+	}
+}
+{{- end}}
 
 // These tests are run against the precompile contract directly with
 // the given input and expected output. They're just a guide to
@@ -42,12 +71,16 @@ var(
 		{{- $structs := .Structs}}
 		{{- range .Contract.Funcs}}
 		{{- $func := .}}
+		{{- $upgraded := index $contract.UpgradeFuncs $func.Original.Name}}
 		{{- if $contract.AllowList}}
 		{{- $roles := mkList "NoRole" "Enabled" "Manager" "Admin"}}
 		{{- range $role := $roles}}
 		{{- $fail := and (not $func.Original.IsConstant) (eq $role "NoRole")}}
 		"calling {{decapitalise $func.Normalized.Name}} from {{$role}} should {{- if $fail}} fail {{- else}} succeed{{- end}}":  {
 			Caller:     allowlist.Test{{$role}}Addr,
+			{{- if $contract.Upgrade}}
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(true),
+			{{- end}}
 			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
 			InputFn: func(t testing.TB) []byte {
 				{{- if len $func.Normalized.Inputs | lt 1}}
@@ -100,6 +133,9 @@ var(
 		{{- if not $func.Original.IsConstant}}
 		"readOnly {{decapitalise $func.Normalized.Name}} should fail": {
 			Caller:	common.Address{1},
+			{{- if $contract.Upgrade}}
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(true),
+			{{- end}}
 			InputFn: func(t testing.TB) []byte {
 				{{- if len $func.Normalized.Inputs | lt 1}}
 				// CUSTOM CODE STARTS HERE
@@ -126,6 +162,9 @@ var(
 		{{- end}}
 		"insufficient gas for {{decapitalise $func.Normalized.Name}} should fail": {
 			Caller:	common.Address{1},
+			{{- if $contract.Upgrade}}
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(true),
+			{{- end}}
 			InputFn: func(t testing.TB) []byte {
 				{{- if len $func.Normalized.Inputs | lt 1}}
 				// CUSTOM CODE STARTS HERE
@@ -149,6 +188,35 @@ var(
 			ReadOnly:    false,
 			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
 		},
+		{{- if $upgraded}}
+		"calling {{decapitalise $func.Normalized.Name}} before {{$contract.Upgrade}} should fail": {
+			{{- if $contract.AllowList}}
+			Caller:     allowlist.TestAdminAddr,
+			BeforeHook: allowlist.SetDefaultRoles(Module.Address),
+			{{- else}}
+			Caller:	common.Address{1},
+			{{- end}}
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(false),
+			InputFn: func(t testing.TB) []byte {
+				{{- if len $func.Normalized.Inputs | lt 1}}
+				testInput := {{capitalise $func.Normalized.Name}}Input{}
+				input, err := Pack{{$func.Normalized.Name}}(testInput)
+				{{- else if len $func.Normalized.Inputs | eq 1 }}
+				{{- $input := index $func.Normalized.Inputs 0}}
+				var testInput {{bindtype $input.Type $structs}}
+				testInput = {{bindtypenew $input.Type $structs}}
+				input, err := Pack{{$func.Normalized.Name}}(testInput)
+				{{- else}}
+				input, err := Pack{{$func.Normalized.Name}}()
+				{{- end}}
+				require.NoError(t, err)
+				return input
+			},
+			SuppliedGas: 0,
+			ReadOnly:    false,
+			ExpectedErr: "invalid non-activated function selector",
+		},
+		{{- end}}
 		{{- end}}
 		{{- if .Contract.Fallback}}
 		"insufficient gas for fallback should fail": {
@@ -198,6 +266,89 @@ func Test{{.Contract.Type}}Run(t *testing.T) {
 	{{- end}}
 }
 
+{{- if .Contract.UpgradeFuncs}}
+
+// Test{{.Contract.Type}}{{.Contract.Upgrade}}UpgradesActivePrecompile tests that the {{.Contract.Upgrade}}
+// network upgrade activates the functions added with it on a precompile enabled before the
+// upgrade, keeping the state of the precompile and without reconfiguring it.
+func Test{{.Contract.Type}}{{.Contract.Upgrade}}UpgradesActivePrecompile(t *testing.T) {
+	{{- range .Contract.Funcs}}
+	{{- $func := .}}
+	{{- if index $contract.UpgradeFuncs $func.Original.Name}}
+	t.Run("{{decapitalise $func.Normalized.Name}}", func(t *testing.T) {
+		stateDB := state.NewTestStateDB(t)
+		{{- if $contract.AllowList}}
+		allowlist.SetDefaultRoles(Module.Address)(t, stateDB)
+		caller := allowlist.TestAdminAddr
+		{{- else}}
+		caller := common.Address{1}
+		{{- end}}
+		inputFn := func(t testing.TB) []byte {
+			{{- if len $func.Normalized.Inputs | lt 1}}
+			// CUSTOM CODE STARTS HERE
+			// populate test input here
+			testInput := {{capitalise $func.Normalized.Name}}Input{}
+			input, err := Pack{{$func.Normalized.Name}}(testInput)
+			{{- else if len $func.Normalized.Inputs | eq 1 }}
+			{{- $input := index $func.Normalized.Inputs 0}}
+			// CUSTOM CODE STARTS HERE
+			// set test input to a value here
+			var testInput {{bindtype $input.Type $structs}}
+			testInput = {{bindtypenew $input.Type $structs}}
+			input, err := Pack{{$func.Normalized.Name}}(testInput)
+			{{- else}}
+			input, err := Pack{{$func.Normalized.Name}}()
+			{{- end}}
+			require.NoError(t, err)
+			return input
+		}
+
+		// The precompile is enabled before the upgrade, so the function cannot be called yet.
+		testutils.PrecompileTest{
+			Caller:        caller,
+			Config:        NewConfig(utils.NewUint64(0){{- if $contract.AllowList}}, nil, nil, nil{{- end}}),
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(false),
+			InputFn:       inputFn,
+			SuppliedGas:   0,
+			ReadOnly:      false,
+			ExpectedErr:   "invalid non-activated function selector",
+		}.Run(t, Module, stateDB)
+
+		// Once the upgrade is activated, the function can be called on the same state.
+		testutils.PrecompileTest{
+			Caller:        caller,
+			ChainConfigFn: chainConfigWith{{$contract.Upgrade}}(true),
+			InputFn:       inputFn,
+			// This test is for a successful call. You can set the expected output here.
+			// CUSTOM CODE STARTS HERE
+			ExpectedRes: func() []byte{
+				{{- if len $func.Normalized.Outputs | eq 0}}
+				// this function does not return an output, leave this one as is
+				packedOutput := []byte{}
+				{{- else}}
+				{{- if len $func.Normalized.Outputs | lt 1}}
+				var output {{capitalise $func.Normalized.Name}}Output // CUSTOM CODE FOR AN OUTPUT
+				{{- else }}
+				{{$output := index $func.Normalized.Outputs 0}}
+				var output {{bindtype $output.Type $structs}} // CUSTOM CODE FOR AN OUTPUT
+				output = {{bindtypenew $output.Type $structs}} // CUSTOM CODE FOR AN OUTPUT
+				{{- end}}
+				packedOutput, err := Pack{{$func.Normalized.Name}}Output(output)
+				if err != nil {
+					panic(err)
+				}
+				{{- end}}
+				return packedOutput
+			}(),
+			SuppliedGas: {{$func.Normalized.Name}}GasCost,
+			ReadOnly:    false,
+		}.Run(t, Module, stateDB)
+	})
+	{{- end}}
+	{{- end}}
+}
+{{- end}}
+
 {{range .Contract.Events}}
 {{$hasData := false}}
 {{range .Normalized.Inputs}}
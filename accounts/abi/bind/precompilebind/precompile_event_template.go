@@ -38,6 +38,10 @@ Topics cannot be fully unpacked into their original values since they're 32-byte
 The non-indexed arguments are encoded using the ABI encoding scheme. The non-indexed arguments can be unpacked into their original values.
 Before packing the event, you need to calculate the gas cost of the event. The gas cost of an event is the base gas cost + the gas cost of the topics + the gas cost of the non-indexed data.
 See Get{EvetName}EventGasCost functions for more details.
+{{- if .Contract.Upgrade}}
+Events added with the {{.Contract.Upgrade}} upgrade must only be emitted if is{{.Contract.Upgrade}}Activated(accessibleState) returns true,
+otherwise re-executing blocks accepted before the upgrade would produce different logs.
+{{- end}}
 You can use the following code to emit an event in your state-changing precompile functions (generated packer might be different)):
 topics, data, err := PackMyEvent(
 	topic1,
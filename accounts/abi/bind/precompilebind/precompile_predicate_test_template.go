@@ -0,0 +1,57 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompilebind
+
+// tmplSourcePrecompilePredicateTestGo is the Go precompiled predicate test source template.
+const tmplSourcePrecompilePredicateTestGo = `
+// Code generated
+// This file is a generated precompile predicate test with the skeleton of test functions.
+// The file is generated by a template. Please inspect every code and comment in this file before use.
+
+package {{.Package}}
+
+import (
+	"testing"
+
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	"github.com/shubhamdubey02/subnet-evm/precompile/testutils"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+
+	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
+)
+
+// TestPredicate tests the predicate gas and verification of Config.
+func TestPredicate(t *testing.T) {
+	predicateBytes := predicate.PackPredicate([]byte("predicate"))
+	predicateContext := &precompileconfig.PredicateContext{
+		ProposerVMBlockCtx: &block.Context{PChainHeight: 1},
+	}
+	tests := map[string]testutils.PredicateTest{
+		"valid predicate": {
+			Config:           NewConfig(utils.NewUint64(3){{- if .Contract.AllowList}}, nil, nil, nil{{- end}}),
+			PredicateContext: predicateContext,
+			PredicateBytes:   predicateBytes,
+			Gas:              PredicateBaseGasCost + PredicateGasCostPerByte*uint64(len(predicateBytes)),
+		},
+		"invalid predicate packing": {
+			Config:           NewConfig(utils.NewUint64(3){{- if .Contract.AllowList}}, nil, nil, nil{{- end}}),
+			PredicateContext: predicateContext,
+			PredicateBytes:   []byte{1},
+			Gas:              PredicateBaseGasCost + PredicateGasCostPerByte,
+			ExpectedErr:      ErrInvalidPredicateBytes,
+		},
+		// CUSTOM CODE STARTS HERE
+		// Add your own predicate tests here, e.g.:
+		// "your custom test name": {
+		// 	Config:           NewConfig(utils.NewUint64(3){{- if .Contract.AllowList}}, nil, nil, nil{{- end}}),
+		// 	PredicateContext: predicateContext,
+		// 	PredicateBytes:   predicate.PackPredicate(yourPredicate),
+		// 	Gas:              PredicateBaseGasCost + PredicateGasCostPerByte*uint64(len(predicate.PackPredicate(yourPredicate))),
+		// 	ExpectedErr:      ErrYourCustomError,
+		// },
+	}
+	// Run predicate tests.
+	testutils.RunPredicateTests(t, tests)
+}
+`
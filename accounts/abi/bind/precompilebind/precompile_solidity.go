@@ -0,0 +1,360 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package precompilebind
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+)
+
+// tmplSolidityInterface is the data structure required to fill the Solidity interface template.
+type tmplSolidityInterface struct {
+	Type            string                // Type name of the precompile
+	Name            string                // Name of the interface
+	AllowList       bool                  // Indicator whether the interface extends IAllowList
+	Structs         []*tmplSolidityStruct // Structs in dependency order
+	Events          []*tmplSolidityMember
+	Functions       []*tmplSolidityFunction
+	Fallback        bool
+	FallbackPayable bool
+	Receive         bool
+}
+
+// tmplSolidityStruct is a struct declared in the Solidity interface.
+type tmplSolidityStruct struct {
+	Name   string
+	Fields []string // Declarations of the fields, e.g. "uint256 amount"
+}
+
+// tmplSolidityMember is an event declared in the Solidity interface.
+type tmplSolidityMember struct {
+	Name   string
+	Params []string // Declarations of the parameters, e.g. "address indexed sender"
+}
+
+// tmplSolidityFunction is a function declared in the Solidity interface.
+type tmplSolidityFunction struct {
+	Name       string
+	Inputs     []string
+	Outputs    []string
+	Mutability string // Empty for nonpayable functions
+}
+
+// SolidityInterfaceFileName returns the name of the Solidity interface file generated for [typ].
+func SolidityInterfaceFileName(typ string) string {
+	return "I" + typ + ".sol"
+}
+
+// PrecompileSolidityInterface generates the Solidity interface I[typ] of a precompile from [abiData].
+// If the ABI contains the allow list functions, the interface extends IAllowList instead of
+// declaring them.
+func PrecompileSolidityInterface(typ string, abiData string) (string, error) {
+	data, err := newSolidityInterface(typ, abiData)
+	if err != nil {
+		return "", err
+	}
+	return executeSolidityTemplate(tmplSourcePrecompileSolidity, data)
+}
+
+// ContractTestFileNames returns the names of the DS-Test contract and of the Hardhat test
+// generated for [typ].
+func ContractTestFileNames(typ string) (string, string) {
+	return ContractsTestDir + "/" + typ + "Test.sol", ContractsTestDir + "/" + strings.ToLower(typ) + ".ts"
+}
+
+// PrecompileContractTests generates the stubs of a DS-Test contract testing the precompile [typ]
+// with a step per function of [abiData], and of the Hardhat test running these steps.
+// DS-Test contracts can also be run with Foundry.
+func PrecompileContractTests(typ string, abiData string) (string, string, error) {
+	data, err := newSolidityInterface(typ, abiData)
+	if err != nil {
+		return "", "", err
+	}
+	solidityTest, err := executeSolidityTemplate(tmplSourcePrecompileSolidityTest, data)
+	if err != nil {
+		return "", "", err
+	}
+	hardhatTest, err := executeSolidityTemplate(tmplSourcePrecompileHardhatTest, data)
+	if err != nil {
+		return "", "", err
+	}
+	return solidityTest, hardhatTest, nil
+}
+
+// newSolidityInterface returns the template data of the Solidity interface I[typ] of [abiData].
+func newSolidityInterface(typ string, abiData string) (*tmplSolidityInterface, error) {
+	evmABI, err := abi.JSON(strings.NewReader(abiData))
+	if err != nil {
+		return nil, err
+	}
+
+	isAllowList := true
+	for name := range allowlist.AllowListABI.Methods {
+		if _, ok := evmABI.Methods[name]; !ok {
+			isAllowList = false
+			break
+		}
+	}
+
+	g := &solidityGenerator{
+		prefix:      "I" + typ,
+		structNames: make(map[string]string),
+	}
+	data := &tmplSolidityInterface{
+		Type:            typ,
+		Name:            "I" + typ,
+		AllowList:       isAllowList,
+		Fallback:        evmABI.HasFallback(),
+		FallbackPayable: evmABI.Fallback.IsPayable(),
+		Receive:         evmABI.HasReceive(),
+	}
+
+	for _, name := range sortedKeys(evmABI.Events) {
+		if _, ok := allowlist.AllowListABI.Events[name]; ok && isAllowList {
+			continue
+		}
+		event := evmABI.Events[name]
+		member := &tmplSolidityMember{Name: event.RawName}
+		for _, input := range event.Inputs {
+			param := g.typeName(input.Type)
+			if input.Indexed {
+				param += " indexed"
+			}
+			if input.Name != "" {
+				param += " " + input.Name
+			}
+			member.Params = append(member.Params, param)
+		}
+		data.Events = append(data.Events, member)
+	}
+
+	for _, name := range sortedKeys(evmABI.Methods) {
+		if _, ok := allowlist.AllowListABI.Methods[name]; ok && isAllowList {
+			continue
+		}
+		method := evmABI.Methods[name]
+		function := &tmplSolidityFunction{
+			Name:       method.RawName,
+			Inputs:     g.params(method.Inputs, "calldata"),
+			Outputs:    g.params(method.Outputs, "memory"),
+			Mutability: method.StateMutability,
+		}
+		switch {
+		case function.Mutability == "nonpayable":
+			function.Mutability = ""
+		case function.Mutability == "" && method.IsConstant():
+			function.Mutability = "view"
+		case function.Mutability == "" && method.IsPayable():
+			function.Mutability = "payable"
+		}
+		data.Functions = append(data.Functions, function)
+	}
+	data.Structs = g.structs
+
+	return data, nil
+}
+
+func executeSolidityTemplate(source string, data *tmplSolidityInterface) (string, error) {
+	buffer := new(bytes.Buffer)
+	tmpl := template.Must(template.New("").Funcs(template.FuncMap{"join": strings.Join}).Parse(source))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// solidityGenerator converts ABI types into Solidity declarations and collects
+// the structs they reference.
+type solidityGenerator struct {
+	prefix      string            // Interface name stripped from qualified struct names
+	structNames map[string]string // Tuple signature -> struct name
+	structs     []*tmplSolidityStruct
+}
+
+// params returns the declarations of [args], using [location] as the data location of
+// reference types.
+func (g *solidityGenerator) params(args abi.Arguments, location string) []string {
+	params := make([]string, 0, len(args))
+	for _, arg := range args {
+		param := g.typeName(arg.Type)
+		if isReferenceType(arg.Type) {
+			param += " " + location
+		}
+		if arg.Name != "" {
+			param += " " + arg.Name
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// typeName returns the Solidity type of [t], declaring any struct it references.
+func (g *solidityGenerator) typeName(t abi.Type) string {
+	switch t.T {
+	case abi.TupleTy:
+		return g.structName(t)
+	case abi.SliceTy:
+		return g.typeName(*t.Elem) + "[]"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", g.typeName(*t.Elem), t.Size)
+	default:
+		return t.String()
+	}
+}
+
+// structName returns the name of the struct of tuple [t], declaring it on first use.
+// Nested structs are declared before the structs that use them.
+func (g *solidityGenerator) structName(t abi.Type) string {
+	key := t.TupleRawName + t.String()
+	if name, ok := g.structNames[key]; ok {
+		return name
+	}
+	name := strings.TrimPrefix(t.TupleRawName, g.prefix)
+	if name == "" {
+		name = fmt.Sprintf("Struct%d", len(g.structs))
+	}
+	fields := make([]string, len(t.TupleElems))
+	for i, elem := range t.TupleElems {
+		fields[i] = g.typeName(*elem) + " " + t.TupleRawNames[i]
+	}
+	g.structNames[key] = name
+	g.structs = append(g.structs, &tmplSolidityStruct{Name: name, Fields: fields})
+	return name
+}
+
+// isReferenceType returns true if values of [t] require a data location in function parameters.
+func isReferenceType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tmplSourcePrecompileSolidity is the Solidity interface source template.
+const tmplSourcePrecompileSolidity = `//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+{{- if .AllowList}}
+import "./IAllowList.sol";
+{{- end}}
+
+// Code generated
+// This file is a generated Solidity interface of the {{.Type}} precompile.
+// It should be kept in sync with the ABI the precompile was generated from.
+interface {{.Name}}{{if .AllowList}} is IAllowList{{end}} {
+{{- range .Structs}}
+  struct {{.Name}} {
+  {{- range .Fields}}
+    {{.}};
+  {{- end}}
+  }
+{{- end}}
+{{- range .Events}}
+  event {{.Name}}({{join .Params ", "}});
+{{- end}}
+{{- range .Functions}}
+
+  function {{.Name}}({{join .Inputs ", "}}) external{{if .Mutability}} {{.Mutability}}{{end}}{{if .Outputs}} returns ({{join .Outputs ", "}}){{end}};
+{{- end}}
+{{- if .Fallback}}
+
+  fallback() external{{if .FallbackPayable}} payable{{end}};
+{{- end}}
+{{- if .Receive}}
+
+  receive() external payable;
+{{- end}}
+}
+`
+
+// tmplSourcePrecompileSolidityTest is the DS-Test contract source template.
+const tmplSourcePrecompileSolidityTest = `//SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "../interfaces/{{.Name}}.sol";
+{{- if .AllowList}}
+import "./AllowListTest.sol";
+{{- else}}
+import "ds-test/src/test.sol";
+{{- end}}
+
+// Code generated
+// This file is a generated DS-Test contract testing the {{.Type}} precompile.
+// Each step is called by the Hardhat test {{.Type}}Test in a separate transaction.
+// There are some must-be-done changes waiting in the file. Each area requiring you to add your code
+// is marked with CUSTOM CODE to make them easy to find and modify.
+contract {{.Type}}Test is {{if .AllowList}}AllowListTest{{else}}DSTest{{end}} {
+  // CUSTOM CODE STARTS HERE
+  // Set the address of the precompile set in the generated module.go.
+  {{.Name}} precompile = {{.Name}}(address(0));
+
+  function setUp() public {
+    // noop
+  }
+{{- range .Functions}}
+
+  function step_{{.Name}}() public {
+    // CUSTOM CODE STARTS HERE
+    // Call precompile.{{.Name}} and assert its results.
+  }
+{{- end}}
+}
+`
+
+// tmplSourcePrecompileHardhatTest is the Hardhat test source template.
+const tmplSourcePrecompileHardhatTest = `// Code generated
+// This file is a generated Hardhat test running the steps of the DS-Test contract {{.Type}}Test.
+// There are some must-be-done changes waiting in the file. Each area requiring you to add your code
+// is marked with CUSTOM CODE to make them easy to find and modify.
+
+import { ethers } from "hardhat"
+import { test } from "./utils"
+
+// CUSTOM CODE STARTS HERE
+// Set an account funded in the genesis{{if .AllowList}} and admin of the precompile{{end}}, and the address of the
+// precompile set in the generated module.go.
+const ADMIN_ADDRESS = "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+const PRECOMPILE_ADDRESS = "{ASUITABLEHEXADDRESS}"
+
+describe("{{.Type}}Test", function () {
+  beforeEach("setup DS-Test contract", async function () {
+    const signer = await ethers.getSigner(ADMIN_ADDRESS)
+{{- if .AllowList}}
+    const precompilePromise = ethers.getContractAt("{{.Name}}", PRECOMPILE_ADDRESS, signer)
+{{- end}}
+
+    return ethers.getContractFactory("{{.Type}}Test", { signer })
+      .then(factory => factory.deploy())
+      .then(contract => {
+        this.testContract = contract
+        return contract.deployed().then(() => contract)
+      })
+      .then(contract => contract.setUp())
+{{- if .AllowList}}
+      .then(tx => Promise.all([precompilePromise, tx.wait()]))
+      .then(([precompile]) => precompile.setAdmin(this.testContract.address))
+{{- end}}
+      .then(tx => tx.wait())
+  })
+{{- range .Functions}}
+
+  test("should call {{.Name}}", "step_{{.Name}}")
+{{- end}}
+})
+`
@@ -0,0 +1,367 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi"
+	"github.com/shubhamdubey02/subnet-evm/consensus/dummy"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/modules"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+)
+
+// defaultGas is the gas limit of the transactions calling or deploying a
+// contract if the template does not set one.
+const defaultGas = 1_000_000
+
+// Fixture describes a generated chain, to be used by the tests importing it.
+type Fixture struct {
+	Accounts  map[string]common.Address `json:"accounts"`
+	Contracts map[string]common.Address `json:"contracts"`
+	Genesis   common.Hash               `json:"genesis"`
+	Head      common.Hash               `json:"head"`
+	Number    uint64                    `json:"number"`
+	Timestamp uint64                    `json:"timestamp"`
+}
+
+// generator generates the blocks of a spec.
+type generator struct {
+	spec   *Spec
+	config *params.ChainConfig
+	signer types.Signer
+
+	keys      map[string]*ecdsa.PrivateKey
+	accounts  map[string]common.Address
+	contracts map[string]common.Address
+	// abis are the ABIs of the contracts deployed with one, by name.
+	abis map[string]abi.ABI
+}
+
+func newGenerator(spec *Spec) (*generator, error) {
+	g := &generator{
+		spec:      spec,
+		config:    spec.Genesis.Config,
+		signer:    types.LatestSigner(spec.Genesis.Config),
+		keys:      make(map[string]*ecdsa.PrivateKey),
+		accounts:  make(map[string]common.Address),
+		contracts: make(map[string]common.Address),
+		abis:      make(map[string]abi.ABI),
+	}
+	for _, account := range spec.Accounts {
+		if _, ok := g.keys[account.Name]; ok || account.Name == "" {
+			return nil, fmt.Errorf("invalid or duplicate account name %q", account.Name)
+		}
+		key, err := account.key()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key of account %q: %w", account.Name, err)
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		g.keys[account.Name] = key
+		g.accounts[account.Name] = address
+		if account.Balance != nil {
+			genesisAccount := spec.Genesis.Alloc[address]
+			genesisAccount.Balance = (*big.Int)(account.Balance)
+			spec.Genesis.Alloc[address] = genesisAccount
+		}
+	}
+	return g, nil
+}
+
+// generate generates the chain of the spec. Each block is verified by
+// inserting it into a blockchain, as a node importing the chain would.
+func (g *generator) generate() ([]*types.Block, *Fixture, error) {
+	var (
+		engine = dummy.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+	)
+	genesis, err := g.spec.Genesis.Commit(db, trie.NewDatabase(db))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to commit genesis: %w", err)
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfig, g.spec.Genesis, engine, vm.Config{}, common.Hash{}, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create blockchain: %w", err)
+	}
+	defer chain.Stop()
+
+	var (
+		blocks []*types.Block
+		parent = genesis
+	)
+	for i, template := range g.spec.Blocks {
+		count, gap := template.Count, template.Gap
+		if count == 0 {
+			count = 1
+		}
+		if gap == 0 {
+			gap = g.spec.BlockGap
+		}
+		for j := 0; j < count; j++ {
+			block, err := g.generateBlock(chain, engine, db, parent, gap, template.Txs)
+			if err != nil {
+				return nil, nil, fmt.Errorf("block %d (template %d): %w", parent.NumberU64()+1, i, err)
+			}
+			log.Debug("Generated block", "number", block.NumberU64(), "hash", block.Hash(), "txs", len(block.Transactions()))
+			blocks = append(blocks, block)
+			parent = block
+		}
+	}
+	chain.DrainAcceptorQueue()
+
+	fixture := &Fixture{
+		Accounts:  g.accounts,
+		Contracts: g.contracts,
+		Genesis:   genesis.Hash(),
+		Head:      parent.Hash(),
+		Number:    parent.NumberU64(),
+		Timestamp: parent.Time(),
+	}
+	return blocks, fixture, nil
+}
+
+// generateBlock generates a block on [parent] including the transactions of
+// [txs], then inserts and accepts it in [chain].
+func (g *generator) generateBlock(chain *core.BlockChain, engine *dummy.DummyEngine, db ethdb.Database, parent *types.Block, gap uint64, txs []TxSpec) (*types.Block, error) {
+	coinbase, allowFeeRecipients, err := chain.GetCoinbaseAt(parent.Header())
+	if err != nil {
+		return nil, err
+	}
+	if allowFeeRecipients {
+		coinbase = g.spec.Coinbase
+	}
+	var genErr error
+	blocks, _, err := core.GenerateChain(g.config, parent, engine, db, 1, gap, func(_ int, b *core.BlockGen) {
+		b.SetCoinbase(coinbase)
+		for i, template := range txs {
+			tx, err := g.makeTx(b, template)
+			if err != nil {
+				genErr = fmt.Errorf("tx %d: %w", i, err)
+				return
+			}
+			if err := addTx(b, tx); err != nil {
+				genErr = fmt.Errorf("tx %d: %w", i, err)
+				return
+			}
+		}
+	})
+	if genErr != nil {
+		return nil, genErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		return nil, fmt.Errorf("invalid block: %w", err)
+	}
+	if err := chain.Accept(blocks[0]); err != nil {
+		return nil, err
+	}
+	return blocks[0], nil
+}
+
+// addTx adds [tx] to [b], turning the panic of a tx that cannot be
+// executed into an error.
+func addTx(b *core.BlockGen, tx *types.Transaction) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	b.AddTx(tx)
+	return nil
+}
+
+// makeTx makes the signed transaction of [template] in the block of [b].
+func (g *generator) makeTx(b *core.BlockGen, template TxSpec) (*types.Transaction, error) {
+	key, ok := g.keys[template.From]
+	if !ok {
+		return nil, fmt.Errorf("unknown sender %q", template.From)
+	}
+	var (
+		from  = crypto.PubkeyToAddress(key.PublicKey)
+		nonce = b.TxNonce(from)
+		data  = common.CopyBytes(template.Data)
+		to    *common.Address
+	)
+	if template.To != "" {
+		address, err := g.resolve(template.To)
+		if err != nil {
+			return nil, err
+		}
+		to = &address
+	}
+	contractABI, err := g.abiOf(template)
+	if err != nil {
+		return nil, err
+	}
+	if template.Method != "" || len(template.Args) > 0 {
+		if contractABI == nil {
+			return nil, fmt.Errorf("no ABI to call %q of %q", template.Method, template.To)
+		}
+		packed, err := packArgs(*contractABI, template.Method, template.Args, g.resolve)
+		if err != nil {
+			return nil, err
+		}
+		if template.Method == "" {
+			data = append(data, packed...)
+		} else {
+			data = packed
+		}
+	}
+	if to == nil && template.Deploy != "" {
+		g.contracts[template.Deploy] = crypto.CreateAddress(from, nonce)
+		if contractABI != nil {
+			g.abis[template.Deploy] = *contractABI
+		}
+	}
+
+	gas := template.Gas
+	if gas == 0 {
+		gas = defaultGas
+		if to != nil && len(data) == 0 {
+			gas = params.TxGas
+		}
+	}
+	gasTipCap := defaultGasTipCap
+	if template.GasTipCap != nil {
+		gasTipCap = (*big.Int)(template.GasTipCap)
+	}
+	value := new(big.Int)
+	if template.Value != nil {
+		value = (*big.Int)(template.Value)
+	}
+	var txData types.TxData
+	if baseFee := b.BaseFee(); baseFee != nil {
+		txData = &types.DynamicFeeTx{
+			ChainID:   g.config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: new(big.Int).Add(new(big.Int).Mul(baseFee, common.Big2), gasTipCap),
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	} else {
+		txData = &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasTipCap,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	}
+	return types.SignNewTx(key, g.signer, txData)
+}
+
+// resolve returns the address of the account or deployed contract named
+// [name], of the precompile with config key [name], or the hex address [name].
+func (g *generator) resolve(name string) (common.Address, error) {
+	if address, ok := g.accounts[name]; ok {
+		return address, nil
+	}
+	if address, ok := g.contracts[name]; ok {
+		return address, nil
+	}
+	if module, ok := modules.GetPrecompileModule(name); ok {
+		return module.Address, nil
+	}
+	if common.IsHexAddress(name) {
+		return common.HexToAddress(name), nil
+	}
+	return common.Address{}, fmt.Errorf("unknown account, contract or precompile %q", name)
+}
+
+// abiOf returns the ABI of [template]: the ABI given in the template, or the
+// ABI of the precompile or deployed contract it calls. It returns nil if there
+// is none.
+func (g *generator) abiOf(template TxSpec) (*abi.ABI, error) {
+	if len(template.ABI) != 0 {
+		raw := []byte(template.ABI)
+		// The ABI may be given inline or as the path of a file.
+		var path string
+		if err := json.Unmarshal(raw, &path); err == nil {
+			if raw, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("failed to read ABI: %w", err)
+			}
+		}
+		parsed, err := abi.JSON(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI: %w", err)
+		}
+		return &parsed, nil
+	}
+	if parsed, ok := precompileABIs[template.To]; ok {
+		return &parsed, nil
+	}
+	if parsed, ok := g.abis[template.To]; ok {
+		return &parsed, nil
+	}
+	return nil, nil
+}
+
+// writeChain writes [blocks] RLP encoded to the file at [path], gzipped if
+// the path ends with ".gz", in the format imported by admin_importChain.
+func writeChain(path string, blocks []*types.Block) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := encodeChain(out, strings.HasSuffix(path, ".gz"), blocks); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// encodeChain writes [blocks] RLP encoded to [out], gzipped if [compress] is
+// set. The gzip writer is closed to flush the compressed blocks.
+func encodeChain(out io.Writer, compress bool, blocks []*types.Block) error {
+	var (
+		writer     = out
+		gzipWriter *gzip.Writer
+	)
+	if compress {
+		gzipWriter = gzip.NewWriter(out)
+		writer = gzipWriter
+	}
+	for _, block := range blocks {
+		if err := rlp.Encode(writer, block); err != nil {
+			return err
+		}
+	}
+	if gzipWriter != nil {
+		return gzipWriter.Close()
+	}
+	return nil
+}
+
+// writeJSON writes [v] indented to the file at [path].
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
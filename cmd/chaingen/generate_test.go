@@ -0,0 +1,110 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/consensus/dummy"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+
+	// Force-load precompiles to trigger registration
+	_ "github.com/shubhamdubey02/subnet-evm/precompile/registry"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	spec, err := loadSpec("testdata/spec.json")
+	require.NoError(err)
+	g, err := newGenerator(spec)
+	require.NoError(err)
+	blocks, fixture, err := g.generate()
+	require.NoError(err)
+	require.Len(blocks, 7)
+	require.Equal(uint64(7), fixture.Number)
+	require.Equal(uint64(16), fixture.Timestamp)
+	require.Equal(blocks[len(blocks)-1].Hash(), fixture.Head)
+
+	// The chain is deterministic.
+	spec2, err := loadSpec("testdata/spec.json")
+	require.NoError(err)
+	g2, err := newGenerator(spec2)
+	require.NoError(err)
+	_, fixture2, err := g2.generate()
+	require.NoError(err)
+	require.Equal(fixture, fixture2)
+
+	dir := t.TempDir()
+	require.NoError(writeJSON(filepath.Join(dir, "genesis.json"), spec.Genesis))
+	require.NoError(writeJSON(filepath.Join(dir, "upgrade.json"), spec.Upgrades))
+	require.NoError(writeChain(filepath.Join(dir, "chain.rlp.gz"), blocks))
+
+	// Import the written chain into a fresh blockchain, as admin_importChain does.
+	genesisBytes, err := os.ReadFile(filepath.Join(dir, "genesis.json"))
+	require.NoError(err)
+	genesis := new(core.Genesis)
+	require.NoError(json.Unmarshal(genesisBytes, genesis))
+	upgradeBytes, err := os.ReadFile(filepath.Join(dir, "upgrade.json"))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(upgradeBytes, &genesis.Config.UpgradeConfig))
+	genesis.Config.AvalancheContext = params.AvalancheContext{SnowCtx: utils.TestSnowContext()}
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), core.DefaultCacheConfig, genesis, dummy.NewFaker(), vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	defer chain.Stop()
+	require.Equal(fixture.Genesis, chain.Genesis().Hash())
+
+	imported := readChain(t, filepath.Join(dir, "chain.rlp.gz"))
+	_, err = chain.InsertChain(imported)
+	require.NoError(err)
+	require.Equal(fixture.Head, chain.CurrentBlock().Hash())
+
+	statedb, err := chain.State()
+	require.NoError(err)
+	// bob received 1000 wei, 0x100 minted twice and 1 wei twice.
+	require.Equal(big.NewInt(1000+2*0x100+2), statedb.GetBalance(fixture.Accounts["bob"]))
+	require.Equal(common.BigToHash(big.NewInt(42)), statedb.GetState(fixture.Contracts["store"], common.Hash{}))
+	feeConfig := feemanager.GetStoredFeeConfig(statedb)
+	require.Equal(big.NewInt(params.GWei), feeConfig.MinBaseFee)
+	require.Equal(fixture.Number-1, feemanager.GetFeeConfigLastChangedAt(statedb).Uint64())
+}
+
+func readChain(t *testing.T, path string) []*types.Block {
+	t.Helper()
+	in, err := os.Open(path)
+	require.NoError(t, err)
+	defer in.Close()
+	reader, err := gzip.NewReader(in)
+	require.NoError(t, err)
+
+	var (
+		blocks []*types.Block
+		stream = rlp.NewStream(reader, 0)
+	)
+	for {
+		block := new(types.Block)
+		if err := stream.Decode(block); err == io.EOF {
+			return blocks
+		} else {
+			require.NoError(t, err)
+		}
+		blocks = append(blocks, block)
+	}
+}
@@ -0,0 +1,106 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// chaingen generates a deterministic chain from a declarative spec, and writes
+// its genesis, upgrade bytes and blocks so it can be imported into a node with
+// admin_importChain.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/internal/flags"
+	"github.com/urfave/cli/v2"
+
+	// Force-load precompiles to trigger registration
+	_ "github.com/shubhamdubey02/subnet-evm/precompile/registry"
+)
+
+var (
+	specFlag = &cli.StringFlag{
+		Name:  "spec",
+		Usage: "Path to the JSON spec of the chain to generate",
+	}
+	outFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "Output folder for the genesis, upgrade bytes, chain and fixture files",
+		Value: ".",
+	}
+	chainFlag = &cli.StringFlag{
+		Name:  "chain",
+		Usage: "File name of the exported chain in the output folder, gzipped if it ends with .gz",
+		Value: "chain.rlp",
+	}
+	verbosityFlag = &cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+		Value: 3,
+	}
+)
+
+var app = flags.NewApp("subnet-evm chain fixture generator")
+
+func init() {
+	app.Name = "chaingen"
+	app.Flags = []cli.Flag{
+		specFlag,
+		outFlag,
+		chainFlag,
+		verbosityFlag,
+	}
+	app.Action = chaingen
+}
+
+func chaingen(c *cli.Context) error {
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(c.Int(verbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	if c.String(specFlag.Name) == "" {
+		utils.Fatalf("no spec path is specified (--spec)")
+	}
+	spec, err := loadSpec(c.String(specFlag.Name))
+	if err != nil {
+		utils.Fatalf("Invalid spec: %v", err)
+	}
+	g, err := newGenerator(spec)
+	if err != nil {
+		utils.Fatalf("Invalid spec: %v", err)
+	}
+	blocks, fixture, err := g.generate()
+	if err != nil {
+		utils.Fatalf("Failed to generate chain: %v", err)
+	}
+
+	out := c.String(outFlag.Name)
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		utils.Fatalf("Failed to create output folder: %v", err)
+	}
+	if err := writeJSON(filepath.Join(out, "genesis.json"), spec.Genesis); err != nil {
+		utils.Fatalf("Failed to write genesis: %v", err)
+	}
+	if spec.Upgrades != nil {
+		if err := writeJSON(filepath.Join(out, "upgrade.json"), spec.Upgrades); err != nil {
+			utils.Fatalf("Failed to write upgrade bytes: %v", err)
+		}
+	}
+	if err := writeChain(filepath.Join(out, c.String(chainFlag.Name)), blocks); err != nil {
+		utils.Fatalf("Failed to write chain: %v", err)
+	}
+	if err := writeJSON(filepath.Join(out, "fixture.json"), fixture); err != nil {
+		utils.Fatalf("Failed to write fixture: %v", err)
+	}
+	log.Info("Generated chain", "blocks", len(blocks), "head", fixture.Head, "out", out)
+	return nil
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
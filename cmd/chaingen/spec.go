@@ -0,0 +1,254 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+)
+
+const defaultBlockGap = 2
+
+var (
+	// defaultGasTipCap is the tip of the generated transactions if the template
+	// does not set one.
+	defaultGasTipCap = big.NewInt(params.GWei)
+
+	// precompileABIs are the ABIs used to call the precompiles by config key.
+	precompileABIs = map[string]abi.ABI{
+		deployerallowlist.ConfigKey: allowlist.AllowListABI,
+		txallowlist.ConfigKey:       allowlist.AllowListABI,
+		nativeminter.ConfigKey:      nativeminter.NativeMinterABI,
+		feemanager.ConfigKey:        feemanager.FeeManagerABI,
+		rewardmanager.ConfigKey:     rewardmanager.RewardManagerABI,
+		warp.ConfigKey:              warp.WarpABI,
+	}
+
+	errNoBlocks = errors.New("spec has no blocks")
+)
+
+// Spec is the declarative description of a chain to generate.
+type Spec struct {
+	// Genesis is the genesis of the chain. The accounts of the spec are added
+	// to its alloc.
+	Genesis *core.Genesis `json:"genesis"`
+	// Upgrades are the precompile and state upgrades of the chain, written to
+	// the upgrade bytes of the node.
+	Upgrades *params.UpgradeConfig `json:"upgrades,omitempty"`
+	// Accounts are the accounts sending the transactions of the chain.
+	Accounts []AccountSpec `json:"accounts"`
+	// BlockGap is the default number of seconds between blocks.
+	BlockGap uint64 `json:"blockGap,omitempty"`
+	// Coinbase is the coinbase of the blocks if fee recipients are allowed.
+	Coinbase common.Address `json:"coinbase,omitempty"`
+	// Blocks are the templates of the blocks of the chain, in order.
+	Blocks []BlockSpec `json:"blocks"`
+}
+
+// AccountSpec is a named account. Its key is derived from its name unless it
+// is given explicitly, so the generated chain does not depend on randomness.
+type AccountSpec struct {
+	Name    string                `json:"name"`
+	Key     hexutil.Bytes         `json:"key,omitempty"`
+	Balance *math.HexOrDecimal256 `json:"balance"`
+}
+
+// BlockSpec is the template of [Count] consecutive blocks.
+type BlockSpec struct {
+	// Count is the number of blocks generated from the template. It defaults
+	// to 1.
+	Count int `json:"count,omitempty"`
+	// Gap is the number of seconds between the blocks. It defaults to the
+	// block gap of the spec.
+	Gap uint64 `json:"gap,omitempty"`
+	// Txs are the transactions included in each of the blocks.
+	Txs []TxSpec `json:"txs,omitempty"`
+}
+
+// TxSpec is the template of a transaction.
+type TxSpec struct {
+	// From is the name of the sending account.
+	From string `json:"from"`
+	// To is the name of an account or of a deployed contract, the config key
+	// of a precompile or a hex address. It is empty for deployments.
+	To string `json:"to,omitempty"`
+	// Deploy is the name the contract deployed by the transaction is referred
+	// to by in later transactions.
+	Deploy string                `json:"deploy,omitempty"`
+	Value  *math.HexOrDecimal256 `json:"value,omitempty"`
+	// Data is the calldata of the transaction, or the init code of the
+	// deployed contract.
+	Data hexutil.Bytes `json:"data,omitempty"`
+	// ABI is the ABI used to pack [Method] and [Args]. It is not needed to call
+	// precompiles, or contracts deployed with an ABI.
+	ABI json.RawMessage `json:"abi,omitempty"`
+	// Method is the method called with [Args]. If it is empty, [Args] are
+	// packed as constructor arguments and appended to [Data].
+	Method string            `json:"method,omitempty"`
+	Args   []json.RawMessage `json:"args,omitempty"`
+	// Gas is the gas limit of the transaction. It defaults to 21000 for plain
+	// transfers and to 1000000 otherwise.
+	Gas       uint64                `json:"gas,omitempty"`
+	GasTipCap *math.HexOrDecimal256 `json:"gasTipCap,omitempty"`
+}
+
+// loadSpec reads the spec in the file at [path] and fills in its defaults.
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	spec := new(Spec)
+	if err := json.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if err := spec.setDefaults(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// setDefaults fills in the defaults of the spec, as the VM does for the
+// genesis, and verifies the genesis.
+func (s *Spec) setDefaults() error {
+	if s.Genesis == nil || s.Genesis.Config == nil {
+		return errors.New("spec has no genesis chain config")
+	}
+	if len(s.Blocks) == 0 {
+		return errNoBlocks
+	}
+	if s.BlockGap == 0 {
+		s.BlockGap = defaultBlockGap
+	}
+	config := s.Genesis.Config
+	config.AvalancheContext = params.AvalancheContext{SnowCtx: utils.TestSnowContext()}
+	if config.FeeConfig == commontype.EmptyFeeConfig {
+		config.FeeConfig = params.DefaultFeeConfig
+	}
+	if s.Upgrades != nil {
+		config.UpgradeConfig = *s.Upgrades
+	}
+	// Set the network upgrades explicitly, so the written genesis does not
+	// depend on the defaults of the network it is imported into.
+	config.SetNetworkUpgradeDefaults()
+	return s.Genesis.Verify()
+}
+
+// key returns the private key of the account.
+func (a *AccountSpec) key() (*ecdsa.PrivateKey, error) {
+	if len(a.Key) != 0 {
+		return crypto.ToECDSA(a.Key)
+	}
+	return crypto.ToECDSA(crypto.Keccak256([]byte(a.Name)))
+}
+
+// packArgs packs the JSON [args] as the inputs of [method] of [contractABI],
+// or as the constructor arguments if [method] is empty. Addresses can be given
+// as names resolved by [resolve].
+func packArgs(contractABI abi.ABI, method string, args []json.RawMessage, resolve func(string) (common.Address, error)) ([]byte, error) {
+	inputs := contractABI.Constructor.Inputs
+	if method != "" {
+		m, ok := contractABI.Methods[method]
+		if !ok {
+			return nil, fmt.Errorf("method %q not found in ABI", method)
+		}
+		inputs = m.Inputs
+	}
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("method %q takes %d arguments, got %d", method, len(inputs), len(args))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		value, err := decodeArg(inputs[i].Type, arg, resolve)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s) of method %q: %w", i, inputs[i].Name, method, err)
+		}
+		values[i] = value
+	}
+	return contractABI.Pack(method, values...)
+}
+
+// decodeArg decodes the JSON [arg] into the Go value of [typ]. Integers can be
+// given as JSON numbers or as decimal or hex strings.
+func decodeArg(typ abi.Type, arg json.RawMessage, resolve func(string) (common.Address, error)) (interface{}, error) {
+	switch typ.T {
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(arg, &s); err != nil {
+			return nil, err
+		}
+		return resolve(s)
+	case abi.BoolTy:
+		var b bool
+		err := json.Unmarshal(arg, &b)
+		return b, err
+	case abi.StringTy:
+		var s string
+		err := json.Unmarshal(arg, &s)
+		return s, err
+	case abi.BytesTy:
+		var b hexutil.Bytes
+		err := json.Unmarshal(arg, &b)
+		return []byte(b), err
+	case abi.FixedBytesTy:
+		var b hexutil.Bytes
+		if err := json.Unmarshal(arg, &b); err != nil {
+			return nil, err
+		}
+		if len(b) != typ.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", typ.Size, len(b))
+		}
+		value := reflect.New(typ.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf([]byte(b)))
+		return value.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		s := strings.Trim(string(arg), `"`)
+		n, ok := math.ParseBig256(s)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %s", arg)
+		}
+		goType := typ.GetType()
+		if goType == reflect.TypeOf(n) {
+			return n, nil
+		}
+		value := reflect.New(goType).Elem()
+		if typ.T == abi.UintTy {
+			if !n.IsUint64() || value.OverflowUint(n.Uint64()) {
+				return nil, fmt.Errorf("integer %s overflows %s", n, typ)
+			}
+			value.SetUint(n.Uint64())
+		} else {
+			if !n.IsInt64() || value.OverflowInt(n.Int64()) {
+				return nil, fmt.Errorf("integer %s overflows %s", n, typ)
+			}
+			value.SetInt(n.Int64())
+		}
+		return value.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported argument type %s", typ)
+	}
+}
@@ -0,0 +1,230 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/urfave/cli/v2"
+)
+
+// maxReportedProblems is the number of problems of each check that are
+// reported individually, the others are only counted.
+const maxReportedProblems = 20
+
+var checkCommand = &cli.Command{
+	Name: "check",
+	Usage: "Checks the consistency of the last accepted block, the canonical hashes, the bodies and receipts, " +
+		"the tx lookup indices, the snapshot and the state",
+	Action: checkCmd,
+}
+
+// checker collects the problems found by the consistency checks.
+type checker struct {
+	db       *chainDatabase
+	problems map[string][]string
+	counts   map[string]int
+}
+
+func checkCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	lastAccepted, err := db.lastAccepted()
+	if err != nil {
+		utils.Fatalf("Failed to read last accepted block: %v", err)
+	}
+	log.Info("Checking database", "lastAccepted", lastAccepted.Hash(), "number", lastAccepted.Number)
+
+	chk := &checker{
+		db:       db,
+		problems: make(map[string][]string),
+		counts:   make(map[string]int),
+	}
+	chk.checkHeads(lastAccepted)
+	chk.checkCanonicalChain(lastAccepted)
+	chk.checkSnapshot(lastAccepted)
+	chk.checkState(lastAccepted)
+
+	var total int
+	for check, count := range chk.counts {
+		for _, problem := range chk.problems[check] {
+			log.Error("Inconsistency", "check", check, "problem", problem)
+		}
+		if count > len(chk.problems[check]) {
+			log.Error("More inconsistencies", "check", check, "count", count-len(chk.problems[check]))
+		}
+		total += count
+	}
+	if total > 0 {
+		utils.Fatalf("Found %d inconsistencies", total)
+	}
+	log.Info("Database is consistent")
+	return nil
+}
+
+// report records [problem] found by [check].
+func (chk *checker) report(check string, format string, args ...interface{}) {
+	chk.counts[check]++
+	if len(chk.problems[check]) < maxReportedProblems {
+		chk.problems[check] = append(chk.problems[check], fmt.Sprintf(format, args...))
+	}
+}
+
+// checkHeads checks that the last accepted block, the acceptor tip and the
+// head block are canonical.
+func (chk *checker) checkHeads(lastAccepted *types.Header) {
+	number := lastAccepted.Number.Uint64()
+	if canonical := rawdb.ReadCanonicalHash(chk.db, number); canonical != lastAccepted.Hash() {
+		chk.report("heads", "last accepted block %s is not canonical, canonical hash of %d is %s", lastAccepted.Hash(), number, canonical)
+	}
+
+	// The acceptor tip may be behind the last accepted block, the blocks after
+	// it are reprocessed at startup.
+	tip, err := rawdb.ReadAcceptorTip(chk.db)
+	if err != nil {
+		chk.report("heads", "invalid acceptor tip: %v", err)
+	} else if tip != (common.Hash{}) {
+		chk.checkCanonicalHead("acceptor tip", tip, number)
+	}
+	if head := rawdb.ReadHeadBlockHash(chk.db); head != (common.Hash{}) {
+		chk.checkCanonicalHead("head block", head, ^uint64(0))
+	}
+	if head := rawdb.ReadHeadHeaderHash(chk.db); head != (common.Hash{}) {
+		chk.checkCanonicalHead("head header", head, ^uint64(0))
+	}
+}
+
+// checkCanonicalHead checks that the block [hash], recorded as [name], is
+// canonical and at most at height [max].
+func (chk *checker) checkCanonicalHead(name string, hash common.Hash, max uint64) {
+	number := rawdb.ReadHeaderNumber(chk.db, hash)
+	switch {
+	case number == nil:
+		chk.report("heads", "%s %s not found", name, hash)
+	case *number > max:
+		chk.report("heads", "%s %s at %d is after the last accepted block", name, hash, *number)
+	case rawdb.ReadCanonicalHash(chk.db, *number) != hash:
+		chk.report("heads", "%s %s at %d is not canonical", name, hash, *number)
+	}
+}
+
+// checkCanonicalChain walks the canonical chain back from [lastAccepted] to
+// the genesis, checking that the canonical hashes link the headers, that the
+// bodies and receipts are present and that the transactions after the tx index
+// tail are indexed. Without a tx index tail, the node does not index
+// transactions or has not started to, so the tx indices are not checked.
+func (chk *checker) checkCanonicalChain(lastAccepted *types.Header) {
+	var (
+		txIndexTail = rawdb.ReadTxIndexTail(chk.db)
+		start       = time.Now()
+		logged      = time.Now()
+		hash        = lastAccepted.Hash()
+		blocks      int
+		txs         int
+	)
+	if txIndexTail == nil {
+		log.Warn("No tx index tail found, the tx indices are not checked")
+	}
+	for number := lastAccepted.Number.Uint64(); ; number-- {
+		if canonical := rawdb.ReadCanonicalHash(chk.db, number); canonical != hash {
+			chk.report("canonical", "canonical hash of %d is %s, expected %s", number, canonical, hash)
+		}
+		header := rawdb.ReadHeader(chk.db, hash, number)
+		if header == nil {
+			chk.report("canonical", "header %s at %d not found, cannot check the blocks before it", hash, number)
+			return
+		}
+		body := rawdb.ReadBody(chk.db, hash, number)
+		if body == nil {
+			chk.report("bodies", "body of block %s at %d not found", hash, number)
+		}
+		if header.ReceiptHash != types.EmptyReceiptsHash && !rawdb.HasReceipts(chk.db, hash, number) {
+			chk.report("receipts", "receipts of block %s at %d not found", hash, number)
+		}
+		if body != nil && txIndexTail != nil && number >= *txIndexTail {
+			for _, tx := range body.Transactions {
+				txs++
+				if entry := rawdb.ReadTxLookupEntry(chk.db, tx.Hash()); entry == nil {
+					chk.report("tx indices", "tx %s of block %d is not indexed", tx.Hash(), number)
+				} else if *entry != number {
+					chk.report("tx indices", "tx %s of block %d is indexed at block %d", tx.Hash(), number, *entry)
+				}
+			}
+		}
+		blocks++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Checking canonical chain", "number", number, "blocks", blocks, "txs", txs, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		if number == 0 {
+			break
+		}
+		hash = header.ParentHash
+	}
+	log.Info("Checked canonical chain", "blocks", blocks, "txs", txs, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// checkSnapshot checks that the snapshot is at an accepted block, along with
+// the state root of that block. The snapshot may be at the acceptor tip, behind
+// the last accepted block, in which case the node updates it at startup.
+func (chk *checker) checkSnapshot(lastAccepted *types.Header) {
+	blockHash := rawdb.ReadSnapshotBlockHash(chk.db)
+	if blockHash == (common.Hash{}) {
+		log.Warn("No snapshot found, it is generated at startup if snapshots are enabled")
+		return
+	}
+	number := rawdb.ReadHeaderNumber(chk.db, blockHash)
+	switch {
+	case number == nil:
+		chk.report("snapshot", "snapshot block %s not found", blockHash)
+		return
+	case *number > lastAccepted.Number.Uint64():
+		chk.report("snapshot", "snapshot block %s at %d is after the last accepted block", blockHash, *number)
+		return
+	case rawdb.ReadCanonicalHash(chk.db, *number) != blockHash:
+		chk.report("snapshot", "snapshot block %s at %d is not canonical", blockHash, *number)
+		return
+	}
+	header := rawdb.ReadHeader(chk.db, blockHash, *number)
+	if header == nil {
+		chk.report("snapshot", "header of snapshot block %s at %d not found", blockHash, *number)
+		return
+	}
+	if blockHash != lastAccepted.Hash() {
+		log.Warn("Snapshot is behind the last accepted block, it is updated at startup",
+			"snapshotBlock", *number, "lastAccepted", lastAccepted.Number)
+	}
+	if root := rawdb.ReadSnapshotRoot(chk.db); root != header.Root {
+		chk.report("snapshot", "snapshot root %s is not the state root %s of the snapshot block %d", root, header.Root, *number)
+	}
+}
+
+// checkState checks that the state of the last accepted block, or of one of
+// its ancestors from which the node reprocesses the chain at startup, is
+// present.
+func (chk *checker) checkState(lastAccepted *types.Header) {
+	for header := lastAccepted; header != nil; header = rawdb.ReadHeader(chk.db, header.ParentHash, header.Number.Uint64()-1) {
+		if rawdb.HasLegacyTrieNode(chk.db, header.Root) {
+			if header != lastAccepted {
+				log.Warn("State of the last accepted block not found, it is reprocessed at startup",
+					"stateBlock", header.Number, "lastAccepted", lastAccepted.Number)
+			}
+			return
+		}
+		if header.Number.Sign() == 0 {
+			break
+		}
+	}
+	chk.report("state", "no state found for the last accepted block %s or its ancestors", lastAccepted.Hash())
+}
@@ -0,0 +1,114 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestChecker returns a checker of a database holding a canonical chain of
+// 3 blocks with a transaction each, along with their headers.
+func newTestChecker() (*checker, []*types.Header) {
+	db := rawdb.NewMemoryDatabase()
+	var (
+		headers []*types.Header
+		parent  common.Hash
+	)
+	for i := int64(0); i < 3; i++ {
+		header := &types.Header{
+			ParentHash:  parent,
+			Number:      big.NewInt(i),
+			Root:        common.Hash{byte(i + 1)},
+			ReceiptHash: types.EmptyReceiptsHash,
+		}
+		body := &types.Body{Transactions: []*types.Transaction{types.NewTx(&types.LegacyTx{Nonce: uint64(i)})}}
+		hash := header.Hash()
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteBody(db, hash, header.Number.Uint64(), body)
+		rawdb.WriteCanonicalHash(db, hash, header.Number.Uint64())
+		headers = append(headers, header)
+		parent = hash
+	}
+	chk := &checker{
+		db:       &chainDatabase{Database: db},
+		problems: make(map[string][]string),
+		counts:   make(map[string]int),
+	}
+	return chk, headers
+}
+
+func TestCheckTxIndices(t *testing.T) {
+	require := require.New(t)
+
+	// Without a tx index tail, the tx indices are not checked.
+	chk, headers := newTestChecker()
+	chk.checkCanonicalChain(headers[2])
+	require.Empty(chk.counts)
+
+	// The transactions after the tail are expected to be indexed.
+	chk, headers = newTestChecker()
+	rawdb.WriteTxIndexTail(chk.db, 1)
+	chk.checkCanonicalChain(headers[2])
+	require.Equal(map[string]int{"tx indices": 2}, chk.counts)
+}
+
+func TestCheckSnapshot(t *testing.T) {
+	tests := map[string]struct {
+		lastAccepted   int
+		snapshotBlock  int // -1 for a block that is not in the database
+		rootBlock      int
+		expectedCounts map[string]int
+	}{
+		"at last accepted": {
+			lastAccepted:   2,
+			snapshotBlock:  2,
+			rootBlock:      2,
+			expectedCounts: map[string]int{},
+		},
+		"at an accepted ancestor": {
+			lastAccepted:   2,
+			snapshotBlock:  1,
+			rootBlock:      1,
+			expectedCounts: map[string]int{},
+		},
+		"root of another block": {
+			lastAccepted:   2,
+			snapshotBlock:  1,
+			rootBlock:      2,
+			expectedCounts: map[string]int{"snapshot": 1},
+		},
+		"unknown block": {
+			lastAccepted:   2,
+			snapshotBlock:  -1,
+			rootBlock:      2,
+			expectedCounts: map[string]int{"snapshot": 1},
+		},
+		"after last accepted": {
+			lastAccepted:   1,
+			snapshotBlock:  2,
+			rootBlock:      2,
+			expectedCounts: map[string]int{"snapshot": 1},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			chk, headers := newTestChecker()
+			blockHash := common.Hash{1}
+			if test.snapshotBlock >= 0 {
+				blockHash = headers[test.snapshotBlock].Hash()
+			}
+			rawdb.WriteSnapshotBlockHash(chk.db, blockHash)
+			rawdb.WriteSnapshotRoot(chk.db, headers[test.rootBlock].Root)
+
+			chk.checkSnapshot(headers[test.lastAccepted])
+			require.Equal(t, test.expectedCounts, chk.counts)
+		})
+	}
+}
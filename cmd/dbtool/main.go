@@ -0,0 +1,134 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// dbtool inspects, verifies and repairs the database of a stopped node.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/internal/flags"
+	"github.com/shubhamdubey02/subnet-evm/plugin/evm"
+	"github.com/urfave/cli/v2"
+)
+
+var verbosityFlag = &cli.IntFlag{
+	Name:  "verbosity",
+	Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+	Value: 3,
+}
+
+var inspectCommand = &cli.Command{
+	Name:   "inspect",
+	Usage:  "Prints the size of each kind of data in the database",
+	Action: inspectCmd,
+}
+
+var app = flags.NewApp("subnet-evm database tool")
+
+func init() {
+	app.Name = "dbtool"
+	app.Flags = append([]cli.Flag{verbosityFlag}, utils.DatabaseFlags...)
+	app.Before = func(c *cli.Context) error {
+		glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+		glogger.Verbosity(log.Lvl(c.Int(verbosityFlag.Name)))
+		log.Root().SetHandler(glogger)
+		return nil
+	}
+	app.Commands = []*cli.Command{
+		inspectCommand,
+		verifyStateCommand,
+		checkCommand,
+		reindexTxsCommand,
+		rebuildBloomBitsCommand,
+		repairSnapshotCommand,
+	}
+}
+
+// chainDatabase is the chain database of the VM, opened offline.
+type chainDatabase struct {
+	ethdb.Database
+	// vmDB is the database the node passes to the VM, holding the chain
+	// database along with the metadata of the VM.
+	vmDB    database.Database
+	closeDB func() error
+}
+
+func openChainDatabase(c *cli.Context) (*chainDatabase, error) {
+	vmDB, closeDB, err := utils.OpenVMDatabase(c)
+	if err != nil {
+		return nil, err
+	}
+	return &chainDatabase{
+		Database: evm.NewChainDatabase(vmDB),
+		vmDB:     vmDB,
+		closeDB:  closeDB,
+	}, nil
+}
+
+// Close closes the node database.
+func (db *chainDatabase) Close() error {
+	return db.closeDB()
+}
+
+// lastAccepted returns the header of the last accepted block, as recorded by
+// the VM, or of the genesis if no block was accepted.
+func (db *chainDatabase) lastAccepted() (*types.Header, error) {
+	hash, err := evm.ReadLastAcceptedHash(db.vmDB)
+	if err != nil {
+		return nil, err
+	}
+	if hash == (common.Hash{}) {
+		if hash = rawdb.ReadCanonicalHash(db, 0); hash == (common.Hash{}) {
+			return nil, errors.New("genesis not found, is the blockchain ID correct?")
+		}
+	}
+	number := rawdb.ReadHeaderNumber(db, hash)
+	if number == nil {
+		return nil, fmt.Errorf("header %s not found", hash)
+	}
+	header := rawdb.ReadHeader(db, hash, *number)
+	if header == nil {
+		return nil, fmt.Errorf("header %s not found", hash)
+	}
+	return header, nil
+}
+
+// parseHash parses the hex hash [s].
+func parseHash(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("hash should be %d bytes, got %d", common.HashLength, len(b))
+	}
+	return common.BytesToHash(b), nil
+}
+
+func inspectCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	return rawdb.InspectDatabase(db, nil, nil)
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,138 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/state/snapshot"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	fromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "First block whose transactions are indexed. Defaults to the tx index tail, or the genesis",
+	}
+	forceFlag = &cli.BoolFlag{
+		Name:  "force",
+		Usage: "Regenerates the snapshot even if it is valid",
+	}
+
+	reindexTxsCommand = &cli.Command{
+		Name:   "reindex-txs",
+		Usage:  "Rebuilds the tx lookup indices of the canonical chain up to the last accepted block",
+		Action: reindexTxsCmd,
+		Flags:  []cli.Flag{fromFlag},
+	}
+	rebuildBloomBitsCommand = &cli.Command{
+		Name:   "rebuild-bloombits",
+		Usage:  "Rebuilds the bloom bits used to filter logs, up to the last accepted block",
+		Action: rebuildBloomBitsCmd,
+	}
+	repairSnapshotCommand = &cli.Command{
+		Name:   "repair-snapshot",
+		Usage:  "Verifies the snapshot against the state of the last accepted block and regenerates it if it is corrupted",
+		Action: repairSnapshotCmd,
+		Flags:  []cli.Flag{forceFlag},
+	}
+)
+
+func reindexTxsCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	lastAccepted, err := db.lastAccepted()
+	if err != nil {
+		utils.Fatalf("Failed to read last accepted block: %v", err)
+	}
+	var from uint64
+	if c.IsSet(fromFlag.Name) {
+		from = c.Uint64(fromFlag.Name)
+	} else if tail := rawdb.ReadTxIndexTail(db); tail != nil {
+		from = *tail
+	}
+	to := lastAccepted.Number.Uint64() + 1
+	if from >= to {
+		utils.Fatalf("First block %d is after the last accepted block %d", from, lastAccepted.Number)
+	}
+
+	log.Info("Indexing transactions", "from", from, "to", lastAccepted.Number)
+	start := time.Now()
+	rawdb.IndexTransactions(db, from, to, nil)
+	log.Info("Indexed transactions", "from", from, "to", lastAccepted.Number, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func rebuildBloomBitsCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	lastAccepted, err := db.lastAccepted()
+	if err != nil {
+		utils.Fatalf("Failed to read last accepted block: %v", err)
+	}
+
+	log.Info("Rebuilding bloom bits", "head", lastAccepted.Number)
+	start := time.Now()
+	indexer := core.NewBloomIndexer(db, params.BloomBitsBlocks, params.BloomConfirms)
+	defer indexer.Close()
+	sections, err := indexer.Rebuild(lastAccepted.Number.Uint64())
+	if err != nil {
+		utils.Fatalf("Failed to rebuild bloom bits after %d sections: %v", sections, err)
+	}
+	log.Info("Rebuilt bloom bits", "sections", sections, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func repairSnapshotCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	lastAccepted, err := db.lastAccepted()
+	if err != nil {
+		utils.Fatalf("Failed to read last accepted block: %v", err)
+	}
+	if !rawdb.HasLegacyTrieNode(db, lastAccepted.Root) {
+		utils.Fatalf("State %s of the last accepted block not found, the snapshot is regenerated by the node once it is reprocessed", lastAccepted.Root)
+	}
+	triedb := trie.NewDatabase(db)
+
+	if !c.Bool(forceFlag.Name) {
+		// Loading the snapshot without building it verifies it against the state.
+		_, err := snapshot.New(snapshot.Config{CacheSize: 256, NoBuild: true}, db, triedb, lastAccepted.Hash(), lastAccepted.Root)
+		if err == nil {
+			log.Info("Snapshot is valid", "block", lastAccepted.Hash(), "root", lastAccepted.Root)
+			return nil
+		}
+		log.Warn("Snapshot is invalid", "err", err)
+	}
+
+	// Without a snapshot block hash the snapshot fails to load, so it is wiped
+	// and regenerated from the state, then verified.
+	log.Info("Regenerating snapshot", "block", lastAccepted.Hash(), "root", lastAccepted.Root)
+	start := time.Now()
+	rawdb.DeleteSnapshotBlockHash(db)
+	if _, err := snapshot.New(snapshot.Config{CacheSize: 256}, db, triedb, lastAccepted.Hash(), lastAccepted.Root); err != nil {
+		utils.Fatalf("Failed to regenerate snapshot: %v", err)
+	}
+	log.Info("Regenerated snapshot", "root", lastAccepted.Root, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
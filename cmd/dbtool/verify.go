@@ -0,0 +1,196 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rootFlag = &cli.StringFlag{
+		Name:  "root",
+		Usage: "State root to verify. Defaults to the state root of the last accepted block",
+	}
+
+	verifyStateCommand = &cli.Command{
+		Name:   "verify-state",
+		Usage:  "Verifies the integrity of the state trie, the storage tries and the code of a state root",
+		Action: verifyStateCmd,
+		Flags:  []cli.Flag{rootFlag},
+	}
+)
+
+// stateReport is the result of the verification of a state.
+type stateReport struct {
+	Accounts int
+	Slots    int
+	Codes    int
+	Nodes    int
+	// Problems are the missing or corrupted nodes and code found in the state.
+	Problems []string
+}
+
+func verifyStateCmd(c *cli.Context) error {
+	db, err := openChainDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	var root common.Hash
+	if c.IsSet(rootFlag.Name) {
+		if root, err = parseHash(c.String(rootFlag.Name)); err != nil {
+			utils.Fatalf("Invalid root: %v", err)
+		}
+	} else {
+		header, err := db.lastAccepted()
+		if err != nil {
+			utils.Fatalf("Failed to read last accepted block: %v", err)
+		}
+		root = header.Root
+	}
+
+	report, err := verifyState(db, root)
+	if err != nil {
+		utils.Fatalf("Failed to verify state %s: %v", root, err)
+	}
+	for _, problem := range report.Problems {
+		log.Error("State is corrupted", "problem", problem)
+	}
+	if len(report.Problems) > 0 {
+		utils.Fatalf("State %s has %d problems", root, len(report.Problems))
+	}
+	return nil
+}
+
+// verifyState traverses the state trie of [root] in [db], along with the
+// storage tries and the code of its accounts, and checks that every node and
+// code is present and hashes to its key. Missing or corrupted storage nodes
+// and code are reported and the traversal continues with the next account,
+// while a missing node of the state trie is reported and stops the traversal.
+func verifyState(db ethdb.Database, root common.Hash) (*stateReport, error) {
+	var (
+		report = new(stateReport)
+		triedb = trie.NewDatabase(db)
+		start  = time.Now()
+		logged = time.Now()
+	)
+	log.Info("Verifying state", "root", root)
+	if !rawdb.HasLegacyTrieNode(db, root) {
+		return nil, fmt.Errorf("state root %s not found", root)
+	}
+	accountTrie, err := trie.New(trie.StateTrieID(root), triedb)
+	if err != nil {
+		return nil, err
+	}
+	accIt, err := accountTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	for accIt.Next(true) {
+		if hash := accIt.Hash(); hash != (common.Hash{}) {
+			report.Nodes++
+			if problem := checkNode(db, hash); problem != "" {
+				report.Problems = append(report.Problems, fmt.Sprintf("state trie node %s at path %x: %s", hash, accIt.Path(), problem))
+			}
+		}
+		if !accIt.Leaf() {
+			continue
+		}
+		report.Accounts++
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(accIt.LeafBlob(), &account); err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("account %x: invalid account: %v", accIt.LeafKey(), err))
+			continue
+		}
+		accountHash := common.BytesToHash(accIt.LeafKey())
+		if account.Root != types.EmptyRootHash {
+			slots, nodes, problems, err := verifyStorage(db, triedb, root, accountHash, account.Root)
+			report.Slots += slots
+			report.Nodes += nodes
+			report.Problems = append(report.Problems, problems...)
+			if err != nil {
+				report.Problems = append(report.Problems, fmt.Sprintf("storage trie %s of account %x: %v", account.Root, accountHash, err))
+			}
+		}
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			report.Codes++
+			code := rawdb.ReadCode(db, codeHash)
+			switch {
+			case len(code) == 0:
+				report.Problems = append(report.Problems, fmt.Sprintf("code %s of account %x: missing", codeHash, accountHash))
+			case crypto.Keccak256Hash(code) != codeHash:
+				report.Problems = append(report.Problems, fmt.Sprintf("code %s of account %x: hash mismatch", codeHash, accountHash))
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying state", "at", accountHash, "accounts", report.Accounts, "slots", report.Slots, "nodes", report.Nodes,
+				"problems", len(report.Problems), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		// The accounts after a missing node of the state trie cannot be reached.
+		report.Problems = append(report.Problems, fmt.Sprintf("state trie: %v, traversal stopped", err))
+	}
+	log.Info("Verified state", "root", root, "accounts", report.Accounts, "slots", report.Slots, "codes", report.Codes,
+		"nodes", report.Nodes, "problems", len(report.Problems), "elapsed", common.PrettyDuration(time.Since(start)))
+	return report, nil
+}
+
+// verifyStorage traverses the storage trie [storageRoot] of [accountHash] and
+// returns the number of slots and nodes in it, along with the problems found.
+func verifyStorage(db ethdb.Database, triedb *trie.Database, stateRoot, accountHash, storageRoot common.Hash) (int, int, []string, error) {
+	storageTrie, err := trie.New(trie.StorageTrieID(stateRoot, accountHash, storageRoot), triedb)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	it, err := storageTrie.NodeIterator(nil)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	var (
+		slots, nodes int
+		problems     []string
+	)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			nodes++
+			if problem := checkNode(db, hash); problem != "" {
+				problems = append(problems, fmt.Sprintf("storage trie node %s of account %x at path %x: %s", hash, accountHash, it.Path(), problem))
+			}
+		}
+		if it.Leaf() {
+			slots++
+		}
+	}
+	return slots, nodes, problems, it.Error()
+}
+
+// checkNode returns the problem of the trie node [hash] in [db], or an empty
+// string if it is present and hashes to its key.
+func checkNode(db ethdb.KeyValueReader, hash common.Hash) string {
+	blob := rawdb.ReadLegacyTrieNode(db, hash)
+	switch {
+	case len(blob) == 0:
+		return "missing"
+	case !bytes.Equal(crypto.Keccak256(blob), hash.Bytes()):
+		return "hash mismatch"
+	default:
+		return ""
+	}
+}
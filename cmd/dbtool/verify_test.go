@@ -0,0 +1,61 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyState(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		sdb      = state.NewDatabase(db)
+		contract = common.HexToAddress("0x0100000000000000000000000000000000000001")
+		code     = []byte{0x60, 0x01, 0x60, 0x00, 0x55}
+	)
+	statedb, err := state.New(types.EmptyRootHash, sdb, nil)
+	require.NoError(err)
+	for i := int64(1); i <= 10; i++ {
+		statedb.SetBalance(common.BigToAddress(big.NewInt(i)), big.NewInt(i))
+	}
+	statedb.SetCode(contract, code)
+	statedb.SetState(contract, common.Hash{1}, common.Hash{2})
+	root, err := statedb.Commit(0, false, false)
+	require.NoError(err)
+	require.NoError(sdb.TrieDB().Commit(root, false))
+
+	report, err := verifyState(db, root)
+	require.NoError(err)
+	require.Empty(report.Problems)
+	require.Equal(11, report.Accounts)
+	require.Equal(1, report.Slots)
+	require.Equal(1, report.Codes)
+
+	// Delete the code and the storage root of the contract.
+	tr, err := sdb.OpenTrie(root)
+	require.NoError(err)
+	account, err := tr.GetAccount(contract)
+	require.NoError(err)
+	rawdb.DeleteCode(db, crypto.Keccak256Hash(code))
+	rawdb.DeleteLegacyTrieNode(db, account.Root)
+
+	report, err = verifyState(db, root)
+	require.NoError(err)
+	require.Len(report.Problems, 2)
+	require.Equal(11, report.Accounts)
+	require.Zero(report.Slots)
+
+	_, err = verifyState(db, common.Hash{1})
+	require.ErrorContains(err, "not found")
+}
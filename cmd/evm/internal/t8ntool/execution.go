@@ -31,11 +31,13 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
 	"github.com/shubhamdubey02/subnet-evm/consensus/misc/eip4844"
 	"github.com/shubhamdubey02/subnet-evm/core"
 	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
@@ -43,6 +45,12 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core/types"
 	"github.com/shubhamdubey02/subnet-evm/core/vm"
 	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contract"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/modules"
+	// Force-load precompiles to trigger registration
+	_ "github.com/shubhamdubey02/subnet-evm/precompile/registry"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
 	"github.com/shubhamdubey02/subnet-evm/trie"
 	"golang.org/x/crypto/sha3"
 )
@@ -67,6 +75,7 @@ type ExecutionResult struct {
 	BaseFee              *math.HexOrDecimal256 `json:"currentBaseFee,omitempty"`
 	CurrentExcessBlobGas *math.HexOrDecimal64  `json:"currentExcessBlobGas,omitempty"`
 	CurrentBlobGasUsed   *math.HexOrDecimal64  `json:"currentBlobGasUsed,omitempty"`
+	BlockGasCost         *math.HexOrDecimal256 `json:"currentBlockGasCost,omitempty"`
 }
 
 type ommer struct {
@@ -95,6 +104,12 @@ type stEnv struct {
 	ExcessBlobGas       *uint64                             `json:"excessBlobGas,omitempty"`
 	ParentExcessBlobGas *uint64                             `json:"parentExcessBlobGas,omitempty"`
 	ParentBlobGasUsed   *uint64                             `json:"parentBlobGasUsed,omitempty"`
+	ParentExtra         hexutil.Bytes                       `json:"parentExtra,omitempty"`
+	ParentBlockGasCost  *big.Int                            `json:"parentBlockGasCost,omitempty"`
+	BlockGasCost        *big.Int                            `json:"currentBlockGasCost,omitempty"`
+	// PredicateResults are the results of predicate verification of the
+	// transactions, keyed by tx hash and precompile address.
+	PredicateResults map[common.Hash]map[common.Address]hexutil.Bytes `json:"predicateResults,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -114,6 +129,8 @@ type stEnvMarshaling struct {
 	ExcessBlobGas       *math.HexOrDecimal64
 	ParentExcessBlobGas *math.HexOrDecimal64
 	ParentBlobGasUsed   *math.HexOrDecimal64
+	ParentBlockGasCost  *math.HexOrDecimal256
+	BlockGasCost        *math.HexOrDecimal256
 }
 
 type rejectedTx struct {
@@ -165,6 +182,18 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	if pre.Env.BaseFee != nil {
 		vmContext.BaseFee = new(big.Int).Set(pre.Env.BaseFee)
 	}
+	// If predicateResults are defined, add them to the vmContext.
+	if pre.Env.PredicateResults != nil {
+		predicateResults := predicate.NewResults()
+		for txHash, results := range pre.Env.PredicateResults {
+			txResults := make(predicate.TxResults, len(results))
+			for address, result := range results {
+				txResults[address] = result
+			}
+			predicateResults.SetTxResults(txHash, txResults)
+		}
+		vmContext.PredicateResults = predicateResults
+	}
 	// NOTE: this has been removed
 	// If random is defined, add it to the vmContext.
 	// if pre.Env.Random != nil {
@@ -191,6 +220,15 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	// 	chainConfig.DAOForkBlock.Cmp(new(big.Int).SetUint64(pre.Env.Number)) == 0 {
 	// 	misc.ApplyDAOHardFork(statedb)
 	// }
+	// Configure the precompiles enabled at the parent that are missing from the
+	// pre-state, then apply the upgrades activating in this block, as is done in
+	// StateProcessor.Process before transactions are applied.
+	if err := configurePrecompiles(chainConfig, pre.parentContext(), statedb); err != nil {
+		return nil, nil, NewError(ErrorConfig, fmt.Errorf("could not configure precompiles: %v", err))
+	}
+	if err := core.ApplyUpgrades(chainConfig, &pre.Env.ParentTimestamp, &vmContext, statedb); err != nil {
+		return nil, nil, NewError(ErrorConfig, fmt.Errorf("could not apply upgrades: %v", err))
+	}
 	var blobGasUsed uint64
 	for i, tx := range txs {
 		if tx.Type() == types.BlobTxType && vmContext.ExcessBlobGas == nil {
@@ -316,6 +354,9 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		GasUsed:     (math.HexOrDecimal64)(gasUsed),
 		BaseFee:     (*math.HexOrDecimal256)(vmContext.BaseFee),
 	}
+	if pre.Env.BlockGasCost != nil {
+		execRs.BlockGasCost = (*math.HexOrDecimal256)(pre.Env.BlockGasCost)
+	}
 	if vmContext.ExcessBlobGas != nil {
 		execRs.CurrentExcessBlobGas = (*math.HexOrDecimal64)(vmContext.ExcessBlobGas)
 		execRs.CurrentBlobGasUsed = (*math.HexOrDecimal64)(&blobGasUsed)
@@ -329,6 +370,62 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 	return statedb, execRs, nil
 }
 
+// parentContext returns the context of the parent block, used to configure
+// the precompiles enabled before the block.
+func (pre *Prestate) parentContext() *vm.BlockContext {
+	number := new(big.Int).SetUint64(pre.Env.Number)
+	if number.Sign() > 0 {
+		number.Sub(number, common.Big1)
+	}
+	return &vm.BlockContext{
+		BlockNumber: number,
+		Time:        pre.Env.ParentTimestamp,
+	}
+}
+
+// feeConfig returns the fee config of the block: the fee config stored by the
+// FeeManager precompile if it is enabled at the parent, or the fee config of
+// [chainConfig] otherwise.
+func (pre *Prestate) feeConfig(chainConfig *params.ChainConfig) (commontype.FeeConfig, error) {
+	if !chainConfig.IsPrecompileEnabled(feemanager.ContractAddress, pre.Env.ParentTimestamp) {
+		if chainConfig.FeeConfig == commontype.EmptyFeeConfig {
+			return params.DefaultFeeConfig, nil
+		}
+		return chainConfig.FeeConfig, nil
+	}
+	statedb := MakePreState(rawdb.NewMemoryDatabase(), pre.Pre)
+	if err := configurePrecompiles(chainConfig, pre.parentContext(), statedb); err != nil {
+		return commontype.EmptyFeeConfig, err
+	}
+	feeConfig := feemanager.GetStoredFeeConfig(statedb)
+	if err := feeConfig.Verify(); err != nil {
+		return commontype.EmptyFeeConfig, fmt.Errorf("invalid stored fee config: %w", err)
+	}
+	return feeConfig, nil
+}
+
+// configurePrecompiles configures the stateful precompiles enabled at [parent]
+// that have no account in [statedb], as they would be configured at genesis.
+// This lets tests enable precompiles through the chain config without spelling
+// out their storage in the pre-state alloc.
+func configurePrecompiles(chainConfig *params.ChainConfig, parent contract.ConfigurationBlockContext, statedb *state.StateDB) error {
+	enabled := chainConfig.EnabledStatefulPrecompiles(parent.Timestamp())
+	// Note: RegisteredModules returns precompiles sorted by module addresses,
+	// so precompiles are configured in a deterministic order.
+	for _, module := range modules.RegisteredModules() {
+		config, ok := enabled[module.ConfigKey]
+		if !ok || statedb.Exist(module.Address) {
+			continue
+		}
+		statedb.SetNonce(module.Address, 1)
+		statedb.SetCode(module.Address, []byte{0x1})
+		if err := module.Configure(chainConfig, config, statedb, parent); err != nil {
+			return fmt.Errorf("could not configure precompile, name: %s, reason: %w", module.ConfigKey, err)
+		}
+	}
+	return nil
+}
+
 func MakePreState(db ethdb.Database, accounts core.GenesisAlloc) *state.StateDB {
 	sdb := state.NewDatabaseWithConfig(db, &trie.Config{Preimages: true})
 	statedb, _ := state.New(types.EmptyRootHash, sdb, nil)
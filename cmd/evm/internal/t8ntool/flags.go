@@ -153,6 +153,11 @@ var (
 			strings.Join(vm.ActivateableEips(), ", ")),
 		Value: "GrayGlacier",
 	}
+	ChainConfigFlag = &cli.StringFlag{
+		Name: "state.chainconfig",
+		Usage: "File name of a subnet-evm chain config to use instead of the config of state.fork. " +
+			"The config may enable precompiles, set the fee config and list precompile and state upgrades under 'upgrades'.",
+	}
 	VerbosityFlag = &cli.IntFlag{
 		Name:  "verbosity",
 		Usage: "sets the verbosity level",
@@ -8,6 +8,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
@@ -16,25 +17,29 @@ var _ = (*stEnvMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (s stEnv) MarshalJSON() ([]byte, error) {
 	type stEnv struct {
-		Coinbase            common.UnprefixedAddress            `json:"currentCoinbase"   gencodec:"required"`
-		Difficulty          *math.HexOrDecimal256               `json:"currentDifficulty"`
-		Random              *math.HexOrDecimal256               `json:"currentRandom"`
-		ParentDifficulty    *math.HexOrDecimal256               `json:"parentDifficulty"`
-		ParentBaseFee       *math.HexOrDecimal256               `json:"parentBaseFee,omitempty"`
-		ParentGasUsed       math.HexOrDecimal64                 `json:"parentGasUsed,omitempty"`
-		ParentGasLimit      math.HexOrDecimal64                 `json:"parentGasLimit,omitempty"`
-		MinBaseFee          *math.HexOrDecimal256               `json:"minBaseFee,omitempty"`
-		GasLimit            math.HexOrDecimal64                 `json:"currentGasLimit"   gencodec:"required"`
-		Number              math.HexOrDecimal64                 `json:"currentNumber"     gencodec:"required"`
-		Timestamp           math.HexOrDecimal64                 `json:"currentTimestamp"  gencodec:"required"`
-		ParentTimestamp     math.HexOrDecimal64                 `json:"parentTimestamp,omitempty"`
-		BlockHashes         map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
-		Ommers              []ommer                             `json:"ommers,omitempty"`
-		BaseFee             *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
-		ParentUncleHash     common.Hash                         `json:"parentUncleHash"`
-		ExcessBlobGas       *math.HexOrDecimal64                `json:"excessBlobGas,omitempty"`
-		ParentExcessBlobGas *math.HexOrDecimal64                `json:"parentExcessBlobGas,omitempty"`
-		ParentBlobGasUsed   *math.HexOrDecimal64                `json:"parentBlobGasUsed,omitempty"`
+		Coinbase            common.UnprefixedAddress                         `json:"currentCoinbase"   gencodec:"required"`
+		Difficulty          *math.HexOrDecimal256                            `json:"currentDifficulty"`
+		Random              *math.HexOrDecimal256                            `json:"currentRandom"`
+		ParentDifficulty    *math.HexOrDecimal256                            `json:"parentDifficulty"`
+		ParentBaseFee       *math.HexOrDecimal256                            `json:"parentBaseFee,omitempty"`
+		ParentGasUsed       math.HexOrDecimal64                              `json:"parentGasUsed,omitempty"`
+		ParentGasLimit      math.HexOrDecimal64                              `json:"parentGasLimit,omitempty"`
+		MinBaseFee          *math.HexOrDecimal256                            `json:"minBaseFee,omitempty"`
+		GasLimit            math.HexOrDecimal64                              `json:"currentGasLimit"   gencodec:"required"`
+		Number              math.HexOrDecimal64                              `json:"currentNumber"     gencodec:"required"`
+		Timestamp           math.HexOrDecimal64                              `json:"currentTimestamp"  gencodec:"required"`
+		ParentTimestamp     math.HexOrDecimal64                              `json:"parentTimestamp,omitempty"`
+		BlockHashes         map[math.HexOrDecimal64]common.Hash              `json:"blockHashes,omitempty"`
+		Ommers              []ommer                                          `json:"ommers,omitempty"`
+		BaseFee             *math.HexOrDecimal256                            `json:"currentBaseFee,omitempty"`
+		ParentUncleHash     common.Hash                                      `json:"parentUncleHash"`
+		ExcessBlobGas       *math.HexOrDecimal64                             `json:"excessBlobGas,omitempty"`
+		ParentExcessBlobGas *math.HexOrDecimal64                             `json:"parentExcessBlobGas,omitempty"`
+		ParentBlobGasUsed   *math.HexOrDecimal64                             `json:"parentBlobGasUsed,omitempty"`
+		ParentExtra         hexutil.Bytes                                    `json:"parentExtra,omitempty"`
+		ParentBlockGasCost  *math.HexOrDecimal256                            `json:"parentBlockGasCost,omitempty"`
+		BlockGasCost        *math.HexOrDecimal256                            `json:"currentBlockGasCost,omitempty"`
+		PredicateResults    map[common.Hash]map[common.Address]hexutil.Bytes `json:"predicateResults,omitempty"`
 	}
 	var enc stEnv
 	enc.Coinbase = common.UnprefixedAddress(s.Coinbase)
@@ -56,31 +61,39 @@ func (s stEnv) MarshalJSON() ([]byte, error) {
 	enc.ExcessBlobGas = (*math.HexOrDecimal64)(s.ExcessBlobGas)
 	enc.ParentExcessBlobGas = (*math.HexOrDecimal64)(s.ParentExcessBlobGas)
 	enc.ParentBlobGasUsed = (*math.HexOrDecimal64)(s.ParentBlobGasUsed)
+	enc.ParentExtra = s.ParentExtra
+	enc.ParentBlockGasCost = (*math.HexOrDecimal256)(s.ParentBlockGasCost)
+	enc.BlockGasCost = (*math.HexOrDecimal256)(s.BlockGasCost)
+	enc.PredicateResults = s.PredicateResults
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (s *stEnv) UnmarshalJSON(input []byte) error {
 	type stEnv struct {
-		Coinbase            *common.UnprefixedAddress           `json:"currentCoinbase"   gencodec:"required"`
-		Difficulty          *math.HexOrDecimal256               `json:"currentDifficulty"`
-		Random              *math.HexOrDecimal256               `json:"currentRandom"`
-		ParentDifficulty    *math.HexOrDecimal256               `json:"parentDifficulty"`
-		ParentBaseFee       *math.HexOrDecimal256               `json:"parentBaseFee,omitempty"`
-		ParentGasUsed       *math.HexOrDecimal64                `json:"parentGasUsed,omitempty"`
-		ParentGasLimit      *math.HexOrDecimal64                `json:"parentGasLimit,omitempty"`
-		MinBaseFee          *math.HexOrDecimal256               `json:"minBaseFee,omitempty"`
-		GasLimit            *math.HexOrDecimal64                `json:"currentGasLimit"   gencodec:"required"`
-		Number              *math.HexOrDecimal64                `json:"currentNumber"     gencodec:"required"`
-		Timestamp           *math.HexOrDecimal64                `json:"currentTimestamp"  gencodec:"required"`
-		ParentTimestamp     *math.HexOrDecimal64                `json:"parentTimestamp,omitempty"`
-		BlockHashes         map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
-		Ommers              []ommer                             `json:"ommers,omitempty"`
-		BaseFee             *math.HexOrDecimal256               `json:"currentBaseFee,omitempty"`
-		ParentUncleHash     *common.Hash                        `json:"parentUncleHash"`
-		ExcessBlobGas       *math.HexOrDecimal64                `json:"excessBlobGas,omitempty"`
-		ParentExcessBlobGas *math.HexOrDecimal64                `json:"parentExcessBlobGas,omitempty"`
-		ParentBlobGasUsed   *math.HexOrDecimal64                `json:"parentBlobGasUsed,omitempty"`
+		Coinbase            *common.UnprefixedAddress                        `json:"currentCoinbase"   gencodec:"required"`
+		Difficulty          *math.HexOrDecimal256                            `json:"currentDifficulty"`
+		Random              *math.HexOrDecimal256                            `json:"currentRandom"`
+		ParentDifficulty    *math.HexOrDecimal256                            `json:"parentDifficulty"`
+		ParentBaseFee       *math.HexOrDecimal256                            `json:"parentBaseFee,omitempty"`
+		ParentGasUsed       *math.HexOrDecimal64                             `json:"parentGasUsed,omitempty"`
+		ParentGasLimit      *math.HexOrDecimal64                             `json:"parentGasLimit,omitempty"`
+		MinBaseFee          *math.HexOrDecimal256                            `json:"minBaseFee,omitempty"`
+		GasLimit            *math.HexOrDecimal64                             `json:"currentGasLimit"   gencodec:"required"`
+		Number              *math.HexOrDecimal64                             `json:"currentNumber"     gencodec:"required"`
+		Timestamp           *math.HexOrDecimal64                             `json:"currentTimestamp"  gencodec:"required"`
+		ParentTimestamp     *math.HexOrDecimal64                             `json:"parentTimestamp,omitempty"`
+		BlockHashes         map[math.HexOrDecimal64]common.Hash              `json:"blockHashes,omitempty"`
+		Ommers              []ommer                                          `json:"ommers,omitempty"`
+		BaseFee             *math.HexOrDecimal256                            `json:"currentBaseFee,omitempty"`
+		ParentUncleHash     *common.Hash                                     `json:"parentUncleHash"`
+		ExcessBlobGas       *math.HexOrDecimal64                             `json:"excessBlobGas,omitempty"`
+		ParentExcessBlobGas *math.HexOrDecimal64                             `json:"parentExcessBlobGas,omitempty"`
+		ParentBlobGasUsed   *math.HexOrDecimal64                             `json:"parentBlobGasUsed,omitempty"`
+		ParentExtra         *hexutil.Bytes                                   `json:"parentExtra,omitempty"`
+		ParentBlockGasCost  *math.HexOrDecimal256                            `json:"parentBlockGasCost,omitempty"`
+		BlockGasCost        *math.HexOrDecimal256                            `json:"currentBlockGasCost,omitempty"`
+		PredicateResults    map[common.Hash]map[common.Address]hexutil.Bytes `json:"predicateResults,omitempty"`
 	}
 	var dec stEnv
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -147,5 +160,17 @@ func (s *stEnv) UnmarshalJSON(input []byte) error {
 	if dec.ParentBlobGasUsed != nil {
 		s.ParentBlobGasUsed = (*uint64)(dec.ParentBlobGasUsed)
 	}
+	if dec.ParentExtra != nil {
+		s.ParentExtra = *dec.ParentExtra
+	}
+	if dec.ParentBlockGasCost != nil {
+		s.ParentBlockGasCost = (*big.Int)(dec.ParentBlockGasCost)
+	}
+	if dec.BlockGasCost != nil {
+		s.BlockGasCost = (*big.Int)(dec.BlockGasCost)
+	}
+	if dec.PredicateResults != nil {
+		s.PredicateResults = dec.PredicateResults
+	}
 	return nil
 }
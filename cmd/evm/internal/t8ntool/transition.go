@@ -193,14 +193,23 @@ func Transition(ctx *cli.Context) error {
 	}
 	// Construct the chainconfig
 	var chainConfig *params.ChainConfig
-	if cConf, extraEips, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
+	if ctx.IsSet(ChainConfigFlag.Name) {
+		// A subnet-evm chain config replaces the config of the fork, and keeps
+		// its chain id unless one is given explicitly.
+		if chainConfig, err = LoadChainConfig(ctx.String(ChainConfigFlag.Name)); err != nil {
+			return err
+		}
+		if ctx.IsSet(ChainIDFlag.Name) {
+			chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
+		}
+	} else if cConf, extraEips, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
 		return NewError(ErrorConfig, fmt.Errorf("failed constructing chain configuration: %v", err))
 	} else {
 		chainConfig = cConf
 		vmConfig.ExtraEips = extraEips
+		// Set the chain id
+		chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
 	}
-	// Set the chain id
-	chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
 
 	var txsWithKeys []*txWithKey
 	if txStr != stdinSelector {
@@ -258,22 +267,32 @@ func Transition(ctx *cli.Context) error {
 	// Sanity check, to not `panic` in state_transition
 	// NOTE: IsLondon replaced with IsSubnetEVM here
 	if chainConfig.IsSubnetEVM(prestate.Env.Timestamp) {
-		if prestate.Env.BaseFee != nil {
-			// Already set, base fee has precedent over parent base fee.
-		} else if prestate.Env.ParentBaseFee != nil && prestate.Env.Number != 0 {
-			parent := &types.Header{
-				Number:   new(big.Int).SetUint64(prestate.Env.Number - 1),
-				Time:     prestate.Env.ParentTimestamp,
-				BaseFee:  prestate.Env.ParentBaseFee,
-				GasUsed:  prestate.Env.ParentGasUsed,
-				GasLimit: prestate.Env.ParentGasLimit,
-				Extra:    make([]byte, params.DynamicFeeExtraDataSize), // TODO: consider passing extra through env
+		feeConfig, err := prestate.feeConfig(chainConfig)
+		if err != nil {
+			return NewError(ErrorConfig, fmt.Errorf("failed reading fee config: %v", err))
+		}
+		if prestate.Env.MinBaseFee != nil {
+			// Override the min base fee of the fee config if it's set in the env
+			feeConfig.MinBaseFee = prestate.Env.MinBaseFee
+		}
+		var parent *types.Header
+		if prestate.Env.Number != 0 {
+			parent = &types.Header{
+				Number:       new(big.Int).SetUint64(prestate.Env.Number - 1),
+				Time:         prestate.Env.ParentTimestamp,
+				BaseFee:      prestate.Env.ParentBaseFee,
+				GasUsed:      prestate.Env.ParentGasUsed,
+				GasLimit:     prestate.Env.ParentGasLimit,
+				Extra:        prestate.Env.ParentExtra,
+				BlockGasCost: prestate.Env.ParentBlockGasCost,
 			}
-			feeConfig := params.DefaultFeeConfig
-			if prestate.Env.MinBaseFee != nil {
-				// Override the default min base fee if it's set in the env
-				feeConfig.MinBaseFee = prestate.Env.MinBaseFee
+			if len(parent.Extra) == 0 {
+				parent.Extra = make([]byte, params.DynamicFeeExtraDataSize)
 			}
+		}
+		if prestate.Env.BaseFee != nil {
+			// Already set, base fee has precedent over parent base fee.
+		} else if prestate.Env.ParentBaseFee != nil && parent != nil {
 			_, prestate.Env.BaseFee, err = dummy.CalcBaseFee(chainConfig, feeConfig, parent, prestate.Env.Timestamp)
 			if err != nil {
 				return NewError(ErrorConfig, fmt.Errorf("failed calculating base fee: %v", err))
@@ -281,6 +300,11 @@ func Transition(ctx *cli.Context) error {
 		} else {
 			return NewError(ErrorConfig, errors.New("EIP-1559 config but missing 'currentBaseFee' in env section"))
 		}
+		if prestate.Env.BlockGasCost != nil {
+			// Already set, block gas cost has precedent over parent block gas cost.
+		} else if prestate.Env.ParentBlockGasCost != nil && parent != nil {
+			prestate.Env.BlockGasCost = dummy.BlockGasCost(feeConfig, parent, prestate.Env.Timestamp)
+		}
 	}
 	// NOTE: Removed isMerged logic here.
 	// isMerged := chainConfig.TerminalTotalDifficulty != nil && chainConfig.TerminalTotalDifficulty.BitLen() == 0
@@ -31,9 +31,36 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/utils"
 	"github.com/urfave/cli/v2"
 )
 
+// LoadChainConfig reads the subnet-evm chain config in the provided path. The
+// precompile and state upgrades of the chain may be given under "upgrades", as
+// in the upgrade bytes of the VM. Unset fields take the defaults the VM uses.
+func LoadChainConfig(path string) (*params.ChainConfig, error) {
+	var config params.ChainConfigWithUpgradesJSON
+	if err := readFile(path, "chain config", &config); err != nil {
+		return nil, err
+	}
+	chainConfig := &config.ChainConfig
+	chainConfig.UpgradeConfig = config.UpgradeConfig
+	chainConfig.AvalancheContext = params.AvalancheContext{SnowCtx: utils.TestSnowContext()}
+	if chainConfig.FeeConfig == commontype.EmptyFeeConfig {
+		chainConfig.FeeConfig = params.DefaultFeeConfig
+	}
+	chainConfig.SetNetworkUpgradeDefaults()
+	if overrides := chainConfig.UpgradeConfig.NetworkUpgradeOverrides; overrides != nil {
+		chainConfig.Override(overrides)
+	}
+	if err := chainConfig.Verify(); err != nil {
+		return nil, NewError(ErrorConfig, fmt.Errorf("invalid chain config: %v", err))
+	}
+	return chainConfig, nil
+}
+
 // readFile reads the json-data in the provided path and marshals into dest.
 func readFile(path, desc string, dest interface{}) error {
 	inFile, err := os.Open(path)
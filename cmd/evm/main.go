@@ -105,6 +105,10 @@ var (
 		Name:  "prestate",
 		Usage: "JSON file with prestate (genesis) config",
 	}
+	ChainConfigFlag = &cli.StringFlag{
+		Name:  "chainconfig",
+		Usage: "JSON file with a subnet-evm chain config, used instead of the prestate config or the configs of the test forks",
+	}
 	MachineFlag = &cli.BoolFlag{
 		Name:  "json",
 		Usage: "output trace logs in machine readable format (json)",
@@ -157,6 +161,7 @@ var stateTransitionCommand = &cli.Command{
 		t8ntool.InputEnvFlag,
 		t8ntool.InputTxsFlag,
 		t8ntool.ForknameFlag,
+		t8ntool.ChainConfigFlag,
 		t8ntool.ChainIDFlag,
 		t8ntool.RewardFlag,
 		t8ntool.VerbosityFlag,
@@ -212,6 +217,7 @@ func init() {
 		CPUProfileFlag,
 		StatDumpFlag,
 		GenesisFlag,
+		ChainConfigFlag,
 		MachineFlag,
 		SenderFlag,
 		ReceiverFlag,
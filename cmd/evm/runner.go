@@ -41,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/shubhamdubey02/subnet-evm/cmd/evm/internal/compiler"
+	"github.com/shubhamdubey02/subnet-evm/cmd/evm/internal/t8ntool"
 	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
 	"github.com/shubhamdubey02/subnet-evm/core"
 	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
@@ -148,8 +149,19 @@ func runCmd(ctx *cli.Context) error {
 	} else {
 		debugLogger = logger.NewStructLogger(logconfig)
 	}
+	if ctx.String(ChainConfigFlag.Name) != "" {
+		var err error
+		if chainConfig, err = t8ntool.LoadChainConfig(ctx.String(ChainConfigFlag.Name)); err != nil {
+			return err
+		}
+	}
 	if ctx.String(GenesisFlag.Name) != "" {
 		gen := readGenesis(ctx.String(GenesisFlag.Name))
+		if chainConfig != nil {
+			// The chain config given explicitly takes precedence over the
+			// config of the genesis.
+			gen.Config = chainConfig
+		}
 		genesisConfig = gen
 		db := rawdb.NewMemoryDatabase()
 		genesis := gen.MustCommit(db)
@@ -160,6 +172,14 @@ func runCmd(ctx *cli.Context) error {
 		sdb := state.NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &trie.Config{Preimages: preimages})
 		statedb, _ = state.New(types.EmptyRootHash, sdb, nil)
 		genesisConfig = new(core.Genesis)
+		if chainConfig != nil {
+			// Configure the precompiles enabled at genesis, as committing a
+			// genesis with the chain config would.
+			blockContext := &vm.BlockContext{BlockNumber: common.Big0}
+			if err := core.ApplyPrecompileActivations(chainConfig, nil, blockContext, statedb); err != nil {
+				return err
+			}
+		}
 	}
 	if ctx.String(SenderFlag.Name) != "" {
 		sender = common.HexToAddress(ctx.String(SenderFlag.Name))
@@ -34,9 +34,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/evm/internal/t8ntool"
 	"github.com/shubhamdubey02/subnet-evm/core/state"
 	"github.com/shubhamdubey02/subnet-evm/core/vm"
 	"github.com/shubhamdubey02/subnet-evm/eth/tracers/logger"
+	"github.com/shubhamdubey02/subnet-evm/params"
 	"github.com/shubhamdubey02/subnet-evm/tests"
 	"github.com/urfave/cli/v2"
 )
@@ -80,9 +82,16 @@ func stateTestCmd(ctx *cli.Context) error {
 	case ctx.Bool(DebugFlag.Name):
 		cfg.Tracer = logger.NewStructLogger(config)
 	}
+	var chainConfig *params.ChainConfig
+	if ctx.String(ChainConfigFlag.Name) != "" {
+		var err error
+		if chainConfig, err = t8ntool.LoadChainConfig(ctx.String(ChainConfigFlag.Name)); err != nil {
+			return err
+		}
+	}
 	// Load the test content from the input file
 	if len(ctx.Args().First()) != 0 {
-		return runStateTest(ctx.Args().First(), cfg, ctx.Bool(MachineFlag.Name), ctx.Bool(DumpFlag.Name))
+		return runStateTest(ctx.Args().First(), cfg, chainConfig, ctx.Bool(MachineFlag.Name), ctx.Bool(DumpFlag.Name))
 	}
 	// Read filenames from stdin and execute back-to-back
 	scanner := bufio.NewScanner(os.Stdin)
@@ -91,7 +100,7 @@ func stateTestCmd(ctx *cli.Context) error {
 		if len(fname) == 0 {
 			return nil
 		}
-		if err := runStateTest(fname, cfg, ctx.Bool(MachineFlag.Name), ctx.Bool(DumpFlag.Name)); err != nil {
+		if err := runStateTest(fname, cfg, chainConfig, ctx.Bool(MachineFlag.Name), ctx.Bool(DumpFlag.Name)); err != nil {
 			return err
 		}
 	}
@@ -99,7 +108,8 @@ func stateTestCmd(ctx *cli.Context) error {
 }
 
 // runStateTest loads the state-test given by fname, and executes the test.
-func runStateTest(fname string, cfg vm.Config, jsonOut, dump bool) error {
+// If chainConfig is set, it is used instead of the configs of the test forks.
+func runStateTest(fname string, cfg vm.Config, chainConfig *params.ChainConfig, jsonOut, dump bool) error {
 	src, err := os.ReadFile(fname)
 	if err != nil {
 		return err
@@ -111,6 +121,7 @@ func runStateTest(fname string, cfg vm.Config, jsonOut, dump bool) error {
 	// Iterate over all the tests, run them and aggregate the results
 	results := make([]StatetestResult, 0, len(tests))
 	for key, test := range tests {
+		test.ChainConfig = chainConfig
 		for _, st := range test.Subtests() {
 			// Run the test and aggregate the result
 			result := &StatetestResult{Name: key, Fork: st.Fork, Pass: true}
@@ -121,6 +121,7 @@ func TestT8n(t *testing.T) {
 	for i, tc := range []struct {
 		base        string
 		input       t8nInput
+		chainConfig string
 		output      t8nOutput
 		expExitCode int
 		expOut      string
@@ -284,10 +285,22 @@ func TestT8n(t *testing.T) {
 			output: t8nOutput{alloc: true, result: true},
 			expOut: "exp.json",
 		},
+		{ // Subnet-EVM chain config with the tx allow list and fee manager enabled
+			base: "./testdata/29",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "", "",
+			},
+			chainConfig: "chainconfig.json",
+			output:      t8nOutput{alloc: true, result: true},
+			expOut:      "exp.json",
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
 		args = append(args, tc.input.get(tc.base)...)
+		if tc.chainConfig != "" {
+			args = append(args, "--state.chainconfig", fmt.Sprintf("%v/%v", tc.base, tc.chainConfig))
+		}
 		var qArgs []string // quoted args for debugging purposes
 		for _, arg := range args {
 			if len(arg) == 0 {
@@ -64,6 +64,22 @@ var (
 		Name:  "out",
 		Usage: "Output folder for the generated precompile files, - for STDOUT (default = ./precompile/contracts/{pkg}). Test files won't be generated if STDOUT is used",
 	}
+	predicaterFlag = &cli.BoolFlag{
+		Name:  "predicater",
+		Usage: "Generate predicate verification (PredicateGas/VerifyPredicate) for transactions that include predicates for the precompile in their access list",
+	}
+	accepterFlag = &cli.BoolFlag{
+		Name:  "accepter",
+		Usage: "Generate an Accept hook that is called for every log emitted by the precompile when its block is accepted",
+	}
+	upgradeFlag = &cli.StringFlag{
+		Name:  "upgrade",
+		Usage: "Name of the network upgrade that upgrades the precompile. It must have an Is{Upgrade} method in precompileconfig.ChainConfig, which currently only exposes Durango, so a future network upgrade must be added there first. Inputs are decoded in strict mode until the upgrade is activated, and upgrade functions are enabled once it is",
+	}
+	upgradeFuncsFlag = &cli.StringSliceFlag{
+		Name:  "upgrade-funcs",
+		Usage: "Comma separated functions of the ABI that can only be called once the upgrade (--upgrade) is activated",
+	}
 )
 
 var app = flags.NewApp("subnet-evm precompile generator tool")
@@ -75,6 +91,10 @@ func init() {
 		outFlag,
 		pkgFlag,
 		typeFlag,
+		predicaterFlag,
+		accepterFlag,
+		upgradeFlag,
+		upgradeFuncsFlag,
 	}
 	app.Action = precompilegen
 }
@@ -145,7 +165,7 @@ func precompilegen(c *cli.Context) error {
 	generateTests := !isOutStdout
 
 	// Generate the contract precompile
-	bindedFiles, err := precompilebind.PrecompileBind(types, string(abi), bins, sigs, pkg, lang, libs, aliases, abifilename, generateTests)
+	bindedFiles, err := precompilebind.PrecompileBind(types, string(abi), bins, sigs, pkg, lang, libs, aliases, abifilename, c.Bool(predicaterFlag.Name), c.Bool(accepterFlag.Name), c.String(upgradeFlag.Name), c.StringSlice(upgradeFuncsFlag.Name), generateTests)
 	if err != nil {
 		utils.Fatalf("Failed to generate precompile: %v", err)
 	}
@@ -168,6 +188,9 @@ func precompilegen(c *cli.Context) error {
 
 	for _, file := range bindedFiles {
 		outputPath := filepath.Join(outFlagStr, file.FileName)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0o700); err != nil {
+			utils.Fatalf("Failed to create directory for generated file %s: %v", file.FileName, err)
+		}
 		if err := os.WriteFile(outputPath, []byte(file.Content), 0o600); err != nil {
 			utils.Fatalf("Failed to write generated file %s: %v", file.FileName, err)
 		}
@@ -0,0 +1,191 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// regenesis exports the state of a chain at a block as the genesis of a
+// successor chain, from the database of a stopped node.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/cmd/utils"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/internal/flags"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/plugin/evm"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+	evmutils "github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/urfave/cli/v2"
+
+	// Force-load precompiles to trigger registration
+	_ "github.com/shubhamdubey02/subnet-evm/precompile/registry"
+)
+
+var (
+	blockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Number of the block whose state is exported. Defaults to the last accepted block",
+	}
+	networkIDFlag = &cli.UintFlag{
+		Name:  "network-id",
+		Usage: "ID of the network the successor chain runs on, used to verify its network upgrades",
+	}
+	outFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "Output folder for the genesis and upgrade bytes of the successor chain",
+		Value: ".",
+	}
+	verbosityFlag = &cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+		Value: 3,
+	}
+)
+
+var app = flags.NewApp("subnet-evm regenesis tool")
+
+func init() {
+	app.Name = "regenesis"
+	app.Flags = append([]cli.Flag{
+		blockFlag,
+		networkIDFlag,
+		outFlag,
+		verbosityFlag,
+	}, utils.DatabaseFlags...)
+	app.Action = regenesis
+}
+
+func regenesis(c *cli.Context) error {
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(c.Int(verbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	vmDB, closeDB, err := utils.OpenVMDatabase(c)
+	if err != nil {
+		utils.Fatalf("Failed to open database: %v", err)
+	}
+	defer closeDB()
+	chaindb := evm.NewChainDatabase(vmDB)
+
+	config, err := readChainConfig(chaindb, uint32(c.Uint(networkIDFlag.Name)))
+	if err != nil {
+		utils.Fatalf("Failed to read chain config: %v", err)
+	}
+	header, err := readHeader(c, chaindb)
+	if err != nil {
+		utils.Fatalf("Failed to read block: %v", err)
+	}
+	log.Info("Exporting state", "number", header.Number, "hash", header.Hash(), "root", header.Root)
+
+	out := c.String(outFlag.Name)
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		utils.Fatalf("Failed to create output folder: %v", err)
+	}
+	// Preimages are read from the database to recover the addresses and storage
+	// keys of the state.
+	sdb := state.NewDatabaseWithConfig(chaindb, &trie.Config{Preimages: true})
+	genesis, err := exportGenesis(config, sdb, header, filepath.Join(out, "genesis.json"))
+	if err != nil {
+		utils.Fatalf("Failed to export genesis: %v", err)
+	}
+	upgrades := genesis.Config.UpgradeConfig
+	if len(upgrades.PrecompileUpgrades) > 0 || len(upgrades.StateUpgrades) > 0 || upgrades.NetworkUpgradeOverrides != nil {
+		if err := writeJSON(filepath.Join(out, "upgrade.json"), upgrades); err != nil {
+			utils.Fatalf("Failed to write upgrade bytes: %v", err)
+		}
+	}
+	log.Info("Exported genesis", "root", header.Root, "out", out)
+	return nil
+}
+
+// exportGenesis writes the genesis exported from the state of [header] to the
+// file at [path]. The genesis is streamed to a temporary file, which is renamed
+// once the genesis is verified.
+func exportGenesis(config *params.ChainConfig, db state.Database, header *types.Header, path string) (*core.Genesis, error) {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := core.ExportGenesis(config, db, header, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return genesis, os.Rename(tmp, path)
+}
+
+// readChainConfig reads the chain config, along with its upgrades, stored with
+// the genesis of the chain in [db].
+func readChainConfig(db ethdb.Database, networkID uint32) (*params.ChainConfig, error) {
+	genesisHash := rawdb.ReadCanonicalHash(db, 0)
+	if genesisHash == (common.Hash{}) {
+		return nil, errors.New("genesis not found, is the blockchain ID correct?")
+	}
+	config := rawdb.ReadChainConfig(db, genesisHash)
+	if config == nil {
+		return nil, fmt.Errorf("chain config of genesis %s not found", genesisHash)
+	}
+	snowCtx := evmutils.TestSnowContext()
+	snowCtx.NetworkID = networkID
+	config.AvalancheContext = params.AvalancheContext{SnowCtx: snowCtx}
+	return config, nil
+}
+
+// readHeader reads the header of the block set by [blockFlag], or of the last
+// accepted block.
+func readHeader(c *cli.Context, db ethdb.Database) (*types.Header, error) {
+	var hash common.Hash
+	if c.IsSet(blockFlag.Name) {
+		hash = rawdb.ReadCanonicalHash(db, c.Uint64(blockFlag.Name))
+	} else {
+		tip, err := rawdb.ReadAcceptorTip(db)
+		if err != nil {
+			return nil, err
+		}
+		if hash = tip; hash == (common.Hash{}) {
+			hash = rawdb.ReadHeadBlockHash(db)
+		}
+	}
+	if hash == (common.Hash{}) {
+		return nil, errors.New("block not found")
+	}
+	number := rawdb.ReadHeaderNumber(db, hash)
+	if number == nil {
+		return nil, fmt.Errorf("header %s not found", hash)
+	}
+	header := rawdb.ReadHeader(db, hash, *number)
+	if header == nil {
+		return nil, fmt.Errorf("header %s not found", hash)
+	}
+	return header, nil
+}
+
+// writeJSON writes [v] indented to the file at [path].
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
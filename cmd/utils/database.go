@@ -0,0 +1,81 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/chains"
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/leveldb"
+	"github.com/MetalBlockchain/metalgo/database/pebble"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	DBPathFlag = &cli.StringFlag{
+		Name:  "db",
+		Usage: "Path of the node database (e.g. ~/.metalgo/db/mainnet/v1.4.5). The node must be stopped",
+	}
+	DBTypeFlag = &cli.StringFlag{
+		Name:  "db-type",
+		Usage: "Type of the node database: leveldb or pebble",
+		Value: leveldb.Name,
+	}
+	BlockchainIDFlag = &cli.StringFlag{
+		Name:  "blockchain-id",
+		Usage: "ID of the chain in the node database. If empty, the database is opened as the database of the VM",
+	}
+)
+
+// DatabaseFlags are the flags of OpenVMDatabase.
+var DatabaseFlags = []cli.Flag{
+	DBPathFlag,
+	DBTypeFlag,
+	BlockchainIDFlag,
+}
+
+// OpenVMDatabase opens the node database set by the database flags, and
+// returns the database of the VM of the chain set by BlockchainIDFlag, as the
+// node passes it to the VM. The returned database must be closed with the
+// returned function.
+func OpenVMDatabase(ctx *cli.Context) (database.Database, func() error, error) {
+	path := ctx.String(DBPathFlag.Name)
+	if path == "" {
+		return nil, nil, fmt.Errorf("no database path is specified (--%s)", DBPathFlag.Name)
+	}
+	var (
+		db  database.Database
+		err error
+	)
+	switch dbType := ctx.String(DBTypeFlag.Name); dbType {
+	case leveldb.Name:
+		db, err = leveldb.New(path, nil, logging.NoLog{}, "db_internal", prometheus.NewRegistry())
+	case pebble.Name:
+		db, err = pebble.New(path, nil, logging.NoLog{}, "db_internal", prometheus.NewRegistry())
+	default:
+		return nil, nil, fmt.Errorf("unknown database type %q", dbType)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s database at %s: %w", ctx.String(DBTypeFlag.Name), path, err)
+	}
+
+	blockchainID := ctx.String(BlockchainIDFlag.Name)
+	if blockchainID == "" {
+		return db, db.Close, nil
+	}
+	chainID, err := ids.FromString(blockchainID)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("invalid blockchain ID %q: %w", blockchainID, err)
+	}
+	// The node prefixes the database of each chain with its ID, and the
+	// database of the VM of the chain with the VM prefix.
+	vmDB := prefixdb.New(chains.VMDBPrefix, prefixdb.New(chainID[:], db))
+	return vmDB, db.Close, nil
+}
@@ -203,6 +203,19 @@ func updateLongWindow(window []byte, start uint64, gasConsumed uint64) {
 	binary.BigEndian.PutUint64(window[start:], totalGasConsumed)
 }
 
+// BlockGasCost returns the block gas cost required of a block at [timestamp]
+// built on [parent] under [feeConfig].
+func BlockGasCost(feeConfig commontype.FeeConfig, parent *types.Header, timestamp uint64) *big.Int {
+	return calcBlockGasCost(
+		feeConfig.TargetBlockRate,
+		feeConfig.MinBlockGasCost,
+		feeConfig.MaxBlockGasCost,
+		feeConfig.BlockGasCostStep,
+		parent.BlockGasCost,
+		parent.Time, timestamp,
+	)
+}
+
 // calcBlockGasCost calculates the required block gas cost. If [parentTime]
 // > [currentTime], the timeElapsed will be treated as 0.
 func calcBlockGasCost(
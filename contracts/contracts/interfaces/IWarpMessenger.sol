@@ -19,6 +19,8 @@ struct WarpBlockHash {
 interface IWarpMessenger {
   event SendWarpMessage(address indexed sender, bytes32 indexed messageID, bytes message);
 
+  event WarpMessageConsumed(bytes32 indexed messageID, address indexed consumer, bytes32 sourceChainID);
+
   // sendWarpMessage emits a request for the subnet to send a warp message from [msg.sender]
   // with the specified parameters.
   // This emits a SendWarpMessage log from the precompile. When the corresponding block is accepted
@@ -35,6 +37,18 @@ interface IWarpMessenger {
   // Otherwise, returns false and the empty value for the message.
   function getVerifiedWarpMessage(uint32 index) external view returns (WarpMessage calldata message, bool valid);
 
+  // consumeWarpMessage parses the pre-verified warp message in the predicate
+  // storage slots like getVerifiedWarpMessage, records that [msg.sender]
+  // consumed it and emits a WarpMessageConsumed log.
+  // Reverts if the message does not exist or fails verification, if
+  // [msg.sender] already consumed it, or if replay protection is not enabled
+  // in the warp precompile config.
+  function consumeWarpMessage(uint32 index) external returns (WarpMessage calldata message);
+
+  // isMessageConsumed returns true if [msg.sender] consumed the message with
+  // [messageID] through consumeWarpMessage.
+  function isMessageConsumed(bytes32 messageID) external view returns (bool consumed);
+
   // getVerifiedWarpBlockHash parses the pre-verified WarpBlockHash message in the
   // predicate storage slots as a WarpBlockHash message and returns it to the caller.
   // If the message exists and passes verification, returns the verified message
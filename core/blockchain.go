@@ -655,6 +655,12 @@ func (bc *BlockChain) DrainAcceptorQueue() {
 	bc.acceptorWg.Wait()
 }
 
+// AcceptorQueueLength returns the number of accepted blocks in [acceptorQueue]
+// that are waiting to be processed.
+func (bc *BlockChain) AcceptorQueueLength() int {
+	return len(bc.acceptorQueue)
+}
+
 // stopAcceptor sends a signal to the Acceptor to stop processing accepted
 // blocks. The Acceptor will exit once all items in [acceptorQueue] have been
 // processed.
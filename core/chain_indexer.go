@@ -427,6 +427,31 @@ func (c *ChainIndexer) processSection(section uint64, lastHead common.Hash) (com
 	return lastHead, nil
 }
 
+// Rebuild discards the stored sections and reprocesses the sections of the
+// canonical chain up to [head], returning the number of stored sections. It is
+// used to repair the index offline and must not be called on a started indexer.
+func (c *ChainIndexer) Rebuild(head uint64) (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.setValidSections(0)
+	var sections uint64
+	if head >= c.confirmsReq {
+		sections = (head + 1 - c.confirmsReq) / c.sectionSize
+	}
+	var lastHead common.Hash
+	for section := uint64(0); section < sections; section++ {
+		newHead, err := c.processSection(section, lastHead)
+		if err != nil {
+			return c.storedSections, err
+		}
+		c.setSectionHead(section, newHead)
+		c.setValidSections(section + 1)
+		lastHead = newHead
+	}
+	return c.storedSections, nil
+}
+
 // verifyLastHead compares last stored section head with the corresponding block hash in the
 // actual canonical chain and rolls back reorged sections if necessary to ensure that stored
 // sections are all valid
@@ -144,6 +144,42 @@ func testChainIndexer(t *testing.T, count int) {
 	}
 }
 
+// Tests that rebuilding an index reprocesses the confirmed sections of the
+// canonical chain.
+func TestChainIndexerRebuild(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	defer db.Close()
+
+	for i := uint64(0); i <= 20; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i)}
+		header.Bloom.SetBytes(big.NewInt(int64(i + 1)).Bytes())
+		if i > 0 {
+			header.ParentHash = rawdb.ReadCanonicalHash(db, i-1)
+		}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), i)
+	}
+	indexer := NewBloomIndexer(db, 8, 2)
+	defer indexer.Close()
+
+	sections, err := indexer.Rebuild(20)
+	if err != nil {
+		t.Fatalf("Failed to rebuild index: %v", err)
+	}
+	if sections != 2 {
+		t.Fatalf("Section count mismatch: have %d, want %d", sections, 2)
+	}
+	for section := uint64(0); section < sections; section++ {
+		head := indexer.SectionHead(section)
+		if want := rawdb.ReadCanonicalHash(db, (section+1)*8-1); head != want {
+			t.Fatalf("Section %d head mismatch: have %x, want %x", section, head, want)
+		}
+		if _, err := rawdb.ReadBloomBits(db, 0, section, head); err != nil {
+			t.Fatalf("Failed to read bloom bits of section %d: %v", section, err)
+		}
+	}
+}
+
 // testChainIndexBackend implements ChainIndexerBackend
 type testChainIndexBackend struct {
 	t                          *testing.T
@@ -41,6 +41,7 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core/types"
 	"github.com/shubhamdubey02/subnet-evm/core/vm"
 	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
 	"github.com/shubhamdubey02/subnet-evm/trie"
 )
 
@@ -249,7 +250,7 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		config = params.TestChainConfig
 	}
 	blocks, receipts := make(types.Blocks, n), make([]types.Receipts, n)
-	chainreader := &fakeChainReader{config: config}
+	chainreader := &fakeChainReader{config: config, db: db}
 	genblock := func(i int, parent *types.Block, statedb *state.StateDB) (*types.Block, types.Receipts, error) {
 		b := &BlockGen{i: i, chain: blocks, parent: parent, statedb: statedb, config: config, engine: engine}
 		b.header = makeHeader(chainreader, config, parent, gap, statedb, b.engine)
@@ -354,6 +355,9 @@ func makeHeader(chain consensus.ChainReader, config *params.ChainConfig, parent
 
 type fakeChainReader struct {
 	config *params.ChainConfig
+	// db holds the state of the generated blocks, used to read the fee config
+	// stored by the FeeManager precompile. It may be nil.
+	db ethdb.Database
 }
 
 // Config returns the chain configuration.
@@ -367,7 +371,14 @@ func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header
 func (cr *fakeChainReader) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
 func (cr *fakeChainReader) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
 func (cr *fakeChainReader) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error) {
-	return cr.config.FeeConfig, nil, nil
+	if cr.db == nil || !cr.config.IsPrecompileEnabled(feemanager.ContractAddress, parent.Time) {
+		return cr.config.FeeConfig, nil, nil
+	}
+	statedb, err := state.New(parent.Root, state.NewDatabase(cr.db), nil)
+	if err != nil {
+		return commontype.EmptyFeeConfig, nil, err
+	}
+	return feemanager.GetStoredFeeConfig(statedb), feemanager.GetFeeConfigLastChangedAt(statedb), nil
 }
 
 func (cr *fakeChainReader) GetCoinbaseAt(parent *types.Header) (common.Address, bool, error) {
@@ -20,6 +20,30 @@ var ErrMissingPredicateContext = errors.New("missing predicate context")
 
 // CheckPredicates verifies the predicates of [tx] and returns the result. Returning an error invalidates the block.
 func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.PredicateContext, tx *types.Transaction) (map[common.Address][]byte, error) {
+	predicateErrs, err := VerifyPredicates(rules, predicateContext, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	predicateResults := make(map[common.Address][]byte, len(predicateErrs))
+	for address, errs := range predicateErrs {
+		bitset := set.NewBits()
+		for i, err := range errs {
+			if err != nil {
+				bitset.Add(i)
+			}
+		}
+		res := bitset.Bytes()
+		log.Debug("predicate verify", "tx", tx.Hash(), "address", address, "res", res)
+		predicateResults[address] = res
+	}
+	return predicateResults, nil
+}
+
+// VerifyPredicates verifies the predicates of [tx] and returns, for each precompile address, the
+// verification error of each of its predicates (nil if the predicate passed verification).
+// Returning an error invalidates the block.
+func VerifyPredicates(rules params.Rules, predicateContext *precompileconfig.PredicateContext, tx *types.Transaction) (map[common.Address][]error, error) {
 	// Check that the transaction can cover its IntrinsicGas (including the gas required by the predicate) before
 	// verifying the predicate.
 	intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, rules)
@@ -30,10 +54,10 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 		return nil, fmt.Errorf("%w for predicate verification (%d) < intrinsic gas (%d)", ErrIntrinsicGas, tx.Gas(), intrinsicGas)
 	}
 
-	predicateResults := make(map[common.Address][]byte)
+	predicateErrs := make(map[common.Address][]error)
 	// Short circuit early if there are no precompile predicates to verify
 	if !rules.PredicatersExist() {
-		return predicateResults, nil
+		return predicateErrs, nil
 	}
 
 	// Prepare the predicate storage slots from the transaction's access list
@@ -42,7 +66,7 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 	// If there are no predicates to verify, return early and skip requiring the proposervm block
 	// context to be populated.
 	if len(predicateArguments) == 0 {
-		return predicateResults, nil
+		return predicateErrs, nil
 	}
 
 	if predicateContext == nil || predicateContext.ProposerVMBlockCtx == nil {
@@ -53,15 +77,11 @@ func CheckPredicates(rules params.Rules, predicateContext *precompileconfig.Pred
 		// Since [address] is only added to [predicateArguments] when there's a valid predicate in the ruleset
 		// there's no need to check if the predicate exists here.
 		predicaterContract := rules.Predicaters[address]
-		bitset := set.NewBits()
+		errs := make([]error, len(predicates))
 		for i, predicate := range predicates {
-			if err := predicaterContract.VerifyPredicate(predicateContext, predicate); err != nil {
-				bitset.Add(i)
-			}
+			errs[i] = predicaterContract.VerifyPredicate(predicateContext, predicate)
 		}
-		res := bitset.Bytes()
-		log.Debug("predicate verify", "tx", tx.Hash(), "address", address, "res", res)
-		predicateResults[address] = res
+		predicateErrs[address] = errs
 	}
-	return predicateResults, nil
+	return predicateErrs, nil
 }
@@ -459,3 +459,35 @@ func TestCheckPredicatesOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyPredicatesErrors(t *testing.T) {
+	require := require.New(t)
+
+	testErr := errors.New("test error")
+	addr1 := common.HexToAddress("0xaa")
+	validHash := common.Hash{1}
+	invalidHash := common.Hash{2}
+	predicateContext := &precompileconfig.PredicateContext{
+		ProposerVMBlockCtx: &block.Context{
+			PChainHeight: 10,
+		},
+	}
+
+	rules := params.TestChainConfig.Rules(common.Big0, 0)
+	predicater := precompileconfig.NewMockPredicater(gomock.NewController(t))
+	predicater.EXPECT().PredicateGas(gomock.Any()).Return(uint64(0), nil).Times(2)
+	predicater.EXPECT().VerifyPredicate(gomock.Any(), validHash[:]).Return(nil)
+	predicater.EXPECT().VerifyPredicate(gomock.Any(), invalidHash[:]).Return(testErr)
+	rules.Predicaters[addr1] = predicater
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		AccessList: types.AccessList{
+			{Address: addr1, StorageKeys: []common.Hash{validHash}},
+			{Address: addr1, StorageKeys: []common.Hash{invalidHash}},
+		},
+		Gas: 53000,
+	})
+	predicateErrs, err := VerifyPredicates(rules, predicateContext, tx)
+	require.NoError(err)
+	require.Equal(map[common.Address][]error{addr1: {nil, testErr}}, predicateErrs)
+}
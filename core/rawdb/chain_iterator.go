@@ -205,18 +205,18 @@ func indexTransactions(db ethdb.Database, from uint64, to uint64, interrupt chan
 	}
 }
 
-// // IndexTransactions creates txlookup indices of the specified block range. The from
-// // is included while to is excluded.
-// //
-// // This function iterates canonical chain in reverse order, it has one main advantage:
-// // We can write tx index tail flag periodically even without the whole indexing
-// // procedure is finished. So that we can resume indexing procedure next time quickly.
-// //
-// // There is a passed channel, the whole procedure will be interrupted if any
-// // signal received.
-// func IndexTransactions(db ethdb.Database, from uint64, to uint64, interrupt chan struct{}) {
-// 	indexTransactions(db, from, to, interrupt, nil)
-// }
+// IndexTransactions creates txlookup indices of the specified block range. The from
+// is included while to is excluded.
+//
+// This function iterates canonical chain in reverse order, it has one main advantage:
+// We can write tx index tail flag periodically even without the whole indexing
+// procedure is finished. So that we can resume indexing procedure next time quickly.
+//
+// There is a passed channel, the whole procedure will be interrupted if any
+// signal received.
+func IndexTransactions(db ethdb.Database, from uint64, to uint64, interrupt chan struct{}) {
+	indexTransactions(db, from, to, interrupt, nil)
+}
 
 // indexTransactionsForTesting is the internal debug version with an additional hook.
 func indexTransactionsForTesting(db ethdb.Database, from uint64, to uint64, interrupt chan struct{}, hook func(uint64) bool) {
@@ -0,0 +1,522 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/constants"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/modules"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+)
+
+// ErrMissingPreimages is returned by ExportGenesis if addresses or storage keys
+// of the state cannot be recovered from their hashes. Nodes only record the
+// preimages of the state if they run with preimages enabled.
+var ErrMissingPreimages = errors.New("missing preimages")
+
+// ExportGenesis writes to [w] the genesis of a successor chain whose genesis
+// state is the state of [header] in [db], and returns it without its alloc.
+// The accounts are written to [w] as the state trie is iterated, so the state
+// is not loaded in memory. The precompiles enabled at [header] become genesis
+// precompiles with configs reflecting their state, the fee config is the fee
+// config in effect at [header], and upgrades that are not activated yet are kept
+// in the upgrade config. The state root of the written genesis is verified to
+// match the root of [header]. If an error is returned, what was written to [w]
+// is not a valid genesis.
+func ExportGenesis(config *params.ChainConfig, db state.Database, header *types.Header, w io.Writer) (*Genesis, error) {
+	statedb, err := state.New(header.Root, db, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state %s: %w", header.Root, err)
+	}
+	accountTrie, err := db.OpenTrie(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	preimages := newPreimageResolver(config)
+
+	// The precompile configs reflect the storage of the precompiles, which is
+	// exported before the other accounts to write the genesis config first.
+	precompiles, missingSlots, err := exportPrecompiles(statedb, accountTrie, preimages)
+	if err != nil {
+		return nil, err
+	}
+	genesisConfig, err := regenesisConfig(config, statedb, precompiles, header.Time)
+	if err != nil {
+		return nil, err
+	}
+	genesis := &Genesis{
+		Config:     genesisConfig,
+		Timestamp:  header.Time,
+		GasLimit:   genesisConfig.FeeConfig.GasLimit.Uint64(),
+		Difficulty: new(big.Int),
+	}
+	if err := genesis.Verify(); err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
+	if err := verifyPrecompileConfigs(genesis, precompiles); err != nil {
+		return nil, err
+	}
+
+	// The genesis is encoded with an empty alloc, which the accounts are then
+	// streamed into.
+	genesis.Alloc = GenesisAlloc{}
+	encoded, err := json.MarshalIndent(genesis, "", "  ")
+	genesis.Alloc = nil
+	if err != nil {
+		return nil, err
+	}
+	prefix, suffix, ok := bytes.Cut(encoded, []byte(`"alloc": {}`))
+	if !ok {
+		return nil, errors.New("alloc not found in encoded genesis")
+	}
+	var (
+		out             = bufio.NewWriter(w)
+		stateTrie       = trie.NewStackTrie(nil)
+		accounts        int
+		missingAccounts int
+		start           = time.Now()
+		logged          = time.Now()
+	)
+	out.Write(prefix)
+	out.WriteString(`"alloc": {`)
+
+	nodeIt, err := accountTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		key := accountTrie.GetKey(it.Key)
+		if key == nil {
+			key = preimages.lookup(it.Key)
+		}
+		if key == nil {
+			missingAccounts++
+			continue
+		}
+		address := common.BytesToAddress(key)
+		account, ok := precompiles[address]
+		if !ok {
+			storage, missing, err := exportStorage(statedb, address, preimages)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export storage of %s: %w", address, err)
+			}
+			missingSlots += len(missing)
+			account = exportAccount(statedb, address, storage)
+		}
+		if err := writeAllocEntry(out, address, account, accounts == 0); err != nil {
+			return nil, err
+		}
+		encodedAccount, err := rlp.EncodeToBytes(&types.StateAccount{
+			Nonce:    account.Nonce,
+			Balance:  account.Balance,
+			Root:     storageRoot(account.Storage),
+			CodeHash: crypto.Keccak256(account.Code),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := stateTrie.Update(crypto.Keccak256(address.Bytes()), encodedAccount); err != nil {
+			return nil, err
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state", "accounts", accounts, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	if missingAccounts > 0 || missingSlots > 0 {
+		return nil, fmt.Errorf("%w: %d accounts and %d storage slots", ErrMissingPreimages, missingAccounts, missingSlots)
+	}
+	if root := stateTrie.Hash(); root != header.Root {
+		return nil, fmt.Errorf("genesis state root %s does not match state root %s", root, header.Root)
+	}
+	if accounts > 0 {
+		out.WriteString("\n  ")
+	}
+	out.WriteString("}")
+	out.Write(suffix)
+	out.WriteString("\n")
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+	log.Info("Exported state", "root", header.Root, "accounts", accounts, "elapsed", common.PrettyDuration(time.Since(start)))
+	return genesis, nil
+}
+
+// exportPrecompiles returns the accounts of the precompiles in [statedb], along
+// with the number of storage slots whose key could not be recovered. The keys
+// of their storage that have no preimage are recovered from the addresses of
+// the state, iterated from [accountTrie].
+func exportPrecompiles(statedb *state.StateDB, accountTrie state.Trie, preimages *preimageResolver) (GenesisAlloc, int, error) {
+	var (
+		alloc   = make(GenesisAlloc)
+		missing = make(map[common.Hash]struct{})
+	)
+	for _, module := range modules.RegisteredModules() {
+		if !statedb.Exist(module.Address) {
+			continue
+		}
+		storage, missingKeys, err := exportStorage(statedb, module.Address, preimages)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to export storage of %s: %w", module.Address, err)
+		}
+		for _, hash := range missingKeys {
+			missing[hash] = struct{}{}
+		}
+		alloc[module.Address] = exportAccount(statedb, module.Address, storage)
+	}
+	if len(missing) == 0 {
+		return alloc, 0, nil
+	}
+
+	// Allow list roles are stored at the hash of the address they are granted
+	// to, which may not have a preimage if it was only written by a precompile.
+	// Only the addresses recovering missing keys are kept by the resolver.
+	nodeIt, err := accountTrie.NodeIterator(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		key := accountTrie.GetKey(it.Key)
+		if key == nil {
+			key = preimages.lookup(it.Key)
+		}
+		if key == nil {
+			continue
+		}
+		slot := common.BytesToAddress(key).Hash()
+		if _, ok := missing[crypto.Keccak256Hash(slot.Bytes())]; ok {
+			preimages.add(slot.Bytes())
+		}
+	}
+	if it.Err != nil {
+		return nil, 0, it.Err
+	}
+	var missingSlots int
+	for address, account := range alloc {
+		storage, missingKeys, err := exportStorage(statedb, address, preimages)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to export storage of %s: %w", address, err)
+		}
+		missingSlots += len(missingKeys)
+		account.Storage = storage
+		alloc[address] = account
+	}
+	return alloc, missingSlots, nil
+}
+
+// exportAccount returns the account of [address] in [statedb] with [storage].
+func exportAccount(statedb *state.StateDB, address common.Address, storage map[common.Hash]common.Hash) GenesisAccount {
+	account := GenesisAccount{
+		Balance: new(big.Int).Set(statedb.GetBalance(address)),
+		Nonce:   statedb.GetNonce(address),
+		Code:    statedb.GetCode(address),
+	}
+	if len(storage) > 0 {
+		account.Storage = storage
+	}
+	return account
+}
+
+// exportStorage returns the storage of [address] in [statedb], along with the
+// hashes of the storage keys that could not be recovered.
+func exportStorage(statedb *state.StateDB, address common.Address, preimages *preimageResolver) (map[common.Hash]common.Hash, []common.Hash, error) {
+	tr, err := statedb.StorageTrie(address)
+	if tr == nil || err != nil {
+		return nil, nil, err
+	}
+	nodeIt, err := tr.NodeIterator(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		storage = make(map[common.Hash]common.Hash)
+		missing []common.Hash
+		it      = trie.NewIterator(nodeIt)
+	)
+	for it.Next() {
+		key := tr.GetKey(it.Key)
+		if key == nil {
+			key = preimages.lookup(it.Key)
+		}
+		if key == nil {
+			missing = append(missing, common.BytesToHash(it.Key))
+			continue
+		}
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		storage[common.BytesToHash(key)] = common.BytesToHash(content)
+	}
+	return storage, missing, it.Err
+}
+
+// storageRoot returns the root of the storage trie holding [storage].
+func storageRoot(storage map[common.Hash]common.Hash) common.Hash {
+	hashes := make([]common.Hash, 0, len(storage))
+	values := make(map[common.Hash][]byte, len(storage))
+	for key, value := range storage {
+		hash := crypto.Keccak256Hash(key.Bytes())
+		hashes = append(hashes, hash)
+		// The encoding of a trimmed hash cannot fail.
+		values[hash], _ = rlp.EncodeToBytes(common.TrimLeftZeroes(value.Bytes()))
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i].Bytes(), hashes[j].Bytes()) < 0
+	})
+	st := trie.NewStackTrie(nil)
+	for _, hash := range hashes {
+		st.MustUpdate(hash.Bytes(), values[hash])
+	}
+	return st.Hash()
+}
+
+// writeAllocEntry writes [account] of [address] as an entry of the alloc of an
+// indented genesis to [out]. The first entry is not preceded by a comma.
+func writeAllocEntry(out *bufio.Writer, address common.Address, account GenesisAccount, first bool) error {
+	key, err := json.Marshal(common.UnprefixedAddress(address))
+	if err != nil {
+		return err
+	}
+	value, err := json.MarshalIndent(account, "    ", "  ")
+	if err != nil {
+		return err
+	}
+	if !first {
+		out.WriteString(",")
+	}
+	out.WriteString("\n    ")
+	out.Write(key)
+	out.WriteString(": ")
+	_, err = out.Write(value)
+	return err
+}
+
+// verifyPrecompileConfigs verifies that configuring the precompiles of
+// [genesis] does not change the state of the precompiles in [precompiles], so
+// that the state root of the genesis is the root of its alloc.
+func verifyPrecompileConfigs(genesis *Genesis, precompiles GenesisAlloc) error {
+	withConfigs := *genesis
+	withConfigs.Alloc = precompiles
+	withoutConfigs := withConfigs
+	configCopy := *genesis.Config
+	configCopy.GenesisPrecompiles = nil
+	withoutConfigs.Config = &configCopy
+	if root, expected := withConfigs.ToBlock().Root(), withoutConfigs.ToBlock().Root(); root != expected {
+		return fmt.Errorf("precompile configs change the precompile state root from %s to %s", expected, root)
+	}
+	return nil
+}
+
+// regenesisConfig returns a copy of [config] for a successor chain starting at
+// [timestamp] from the state [statedb], whose precompile accounts are
+// [precompiles].
+func regenesisConfig(config *params.ChainConfig, statedb *state.StateDB, precompiles GenesisAlloc, timestamp uint64) (*params.ChainConfig, error) {
+	genesisConfig := *config
+	genesisConfig.GenesisPrecompiles = make(params.Precompiles)
+	genesisConfig.UpgradeConfig = params.UpgradeConfig{
+		NetworkUpgradeOverrides: config.NetworkUpgradeOverrides,
+	}
+
+	if config.IsPrecompileEnabled(feemanager.ContractAddress, timestamp) {
+		if feeConfig := feemanager.GetStoredFeeConfig(statedb); feeConfig != commontype.EmptyFeeConfig {
+			genesisConfig.FeeConfig = feeConfig
+		}
+	}
+	for key, precompileConfig := range config.EnabledStatefulPrecompiles(timestamp) {
+		module, ok := modules.GetPrecompileModule(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown precompile config: %s", key)
+		}
+		genesisPrecompile, err := regenesisPrecompileConfig(module, precompileConfig, statedb, precompiles[module.Address].Storage, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export config of precompile %s: %w", key, err)
+		}
+		genesisConfig.GenesisPrecompiles[key] = genesisPrecompile
+	}
+
+	// Upgrades activated by the state are part of the genesis, the others are
+	// kept to activate on the successor chain.
+	for _, upgrade := range config.PrecompileUpgrades {
+		if *upgrade.Timestamp() > timestamp {
+			genesisConfig.PrecompileUpgrades = append(genesisConfig.PrecompileUpgrades, upgrade)
+		}
+	}
+	for _, upgrade := range config.StateUpgrades {
+		if *upgrade.BlockTimestamp > timestamp {
+			genesisConfig.StateUpgrades = append(genesisConfig.StateUpgrades, upgrade)
+		}
+	}
+	return &genesisConfig, nil
+}
+
+// regenesisPrecompileConfig returns the config of the precompile of [module]
+// activating at [timestamp] with its state in [statedb], whose storage is
+// [storage]. Configuring it in the genesis produces the same storage.
+func regenesisPrecompileConfig(module modules.Module, config precompileconfig.Config, statedb *state.StateDB, storage map[common.Hash]common.Hash, timestamp uint64) (precompileconfig.Config, error) {
+	switch config.(type) {
+	case *deployerallowlist.Config:
+		admins, managers, enableds, err := allowListRoles(storage, nil)
+		return deployerallowlist.NewConfig(&timestamp, admins, enableds, managers), err
+	case *txallowlist.Config:
+		admins, managers, enableds, err := allowListRoles(storage, nil)
+		return txallowlist.NewConfig(&timestamp, admins, enableds, managers), err
+	case *nativeminter.Config:
+		// Minted coins are part of the balances of the state.
+		admins, managers, enableds, err := allowListRoles(storage, nil)
+		return nativeminter.NewConfig(&timestamp, admins, enableds, managers, nil), err
+	case *feemanager.Config:
+		admins, managers, enableds, err := allowListRoles(storage, feemanager.StorageSlots())
+		var initialFeeConfig *commontype.FeeConfig
+		if feeConfig := feemanager.GetStoredFeeConfig(statedb); feeConfig != commontype.EmptyFeeConfig {
+			initialFeeConfig = &feeConfig
+		}
+		return feemanager.NewConfig(&timestamp, admins, enableds, managers, initialFeeConfig), err
+	case *rewardmanager.Config:
+		admins, managers, enableds, err := allowListRoles(storage, rewardmanager.StorageSlots())
+		rewardAddress, allowFeeRecipients := rewardmanager.GetStoredRewardAddress(statedb)
+		initialRewardConfig := &rewardmanager.InitialRewardConfig{AllowFeeRecipients: allowFeeRecipients}
+		if !allowFeeRecipients && rewardAddress != constants.BlackholeAddr {
+			initialRewardConfig.RewardAddress = rewardAddress
+		}
+		return rewardmanager.NewConfig(&timestamp, admins, enableds, managers, initialRewardConfig), err
+	default:
+		// The state of other precompiles is not reflected in their config, only
+		// their activation timestamp is changed.
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		if fields["blockTimestamp"], err = json.Marshal(timestamp); err != nil {
+			return nil, err
+		}
+		if raw, err = json.Marshal(fields); err != nil {
+			return nil, err
+		}
+		genesisConfig := module.MakeConfig()
+		if err := json.Unmarshal(raw, genesisConfig); err != nil {
+			return nil, err
+		}
+		return genesisConfig, nil
+	}
+}
+
+// allowListRoles returns the addresses holding each role in the allow list
+// stored in [storage], skipping the storage slots in [otherSlots] that the
+// precompile uses for other state. The addresses are sorted.
+func allowListRoles(storage map[common.Hash]common.Hash, otherSlots []common.Hash) (admins, managers, enableds []common.Address, err error) {
+	skip := make(map[common.Hash]struct{}, len(otherSlots))
+	for _, slot := range otherSlots {
+		skip[slot] = struct{}{}
+	}
+	for slot, value := range storage {
+		if _, ok := skip[slot]; ok {
+			continue
+		}
+		address := common.BytesToAddress(slot.Bytes())
+		switch allowlist.Role(value) {
+		case allowlist.AdminRole:
+			admins = append(admins, address)
+		case allowlist.ManagerRole:
+			managers = append(managers, address)
+		case allowlist.EnabledRole:
+			enableds = append(enableds, address)
+		default:
+			return nil, nil, nil, fmt.Errorf("invalid role %s of %s", value, address)
+		}
+	}
+	for _, addresses := range [][]common.Address{admins, managers, enableds} {
+		sort.Slice(addresses, func(i, j int) bool {
+			return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+		})
+	}
+	return admins, managers, enableds, nil
+}
+
+// preimageResolver recovers keys from their hashes when the preimage store
+// does not have them, by hashing the keys the chain is known to use: the
+// addresses of the precompiles and of the precompile configs, the storage
+// slots of the precompiles, and the hashes of the addresses of the state.
+type preimageResolver struct {
+	keys map[common.Hash][]byte
+}
+
+func newPreimageResolver(config *params.ChainConfig) *preimageResolver {
+	r := &preimageResolver{keys: make(map[common.Hash][]byte)}
+	for _, module := range modules.RegisteredModules() {
+		r.add(module.Address.Bytes())
+	}
+	for _, slot := range append(feemanager.StorageSlots(), rewardmanager.StorageSlots()...) {
+		r.add(slot.Bytes())
+	}
+	configs := make([]precompileconfig.Config, 0, len(config.GenesisPrecompiles)+len(config.PrecompileUpgrades))
+	for _, precompileConfig := range config.GenesisPrecompiles {
+		configs = append(configs, precompileConfig)
+	}
+	for _, upgrade := range config.PrecompileUpgrades {
+		configs = append(configs, upgrade.Config)
+	}
+	for _, precompileConfig := range configs {
+		// Allow list configs have no common interface, so their addresses are
+		// read from their JSON encoding.
+		var addresses struct {
+			Admins   []common.Address `json:"adminAddresses"`
+			Managers []common.Address `json:"managerAddresses"`
+			Enableds []common.Address `json:"enabledAddresses"`
+		}
+		raw, err := json.Marshal(precompileConfig)
+		if err != nil || json.Unmarshal(raw, &addresses) != nil {
+			continue
+		}
+		for _, address := range append(append(addresses.Admins, addresses.Managers...), addresses.Enableds...) {
+			r.add(address.Bytes())
+			r.add(address.Hash().Bytes())
+		}
+	}
+	return r
+}
+
+// add adds [key] to the keys recovered by the resolver.
+func (r *preimageResolver) add(key []byte) {
+	r.keys[crypto.Keccak256Hash(key)] = key
+}
+
+// lookup returns the key hashing to [hash], or nil if it is unknown.
+func (r *preimageResolver) lookup(hash []byte) []byte {
+	return r.keys[common.BytesToHash(hash)]
+}
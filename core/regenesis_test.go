@@ -0,0 +1,181 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/consensus/dummy"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	regenesisAdminKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	regenesisUserKey, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	regenesisAdmin       = crypto.PubkeyToAddress(regenesisAdminKey.PublicKey)
+	regenesisUser        = crypto.PubkeyToAddress(regenesisUserKey.PublicKey)
+	regenesisRecipient   = common.Address{0xaa}
+	// regenesisContract stores 42 at slot 0 when called.
+	regenesisContract = crypto.CreateAddress(regenesisAdmin, 2)
+)
+
+// newRegenesisTestChain returns a chain of 4 blocks exercising the precompiles,
+// with preimages recorded if [preimages] is true.
+func newRegenesisTestChain(t *testing.T, preimages bool) *BlockChain {
+	require := require.New(t)
+
+	config := *params.TestChainConfig
+	config.GenesisPrecompiles = params.Precompiles{
+		txallowlist.ConfigKey:  txallowlist.NewConfig(utils.NewUint64(0), []common.Address{regenesisAdmin}, nil, nil),
+		feemanager.ConfigKey:   feemanager.NewConfig(utils.NewUint64(0), []common.Address{regenesisAdmin}, nil, nil, nil),
+		nativeminter.ConfigKey: nativeminter.NewConfig(utils.NewUint64(0), []common.Address{regenesisAdmin}, nil, nil, nil),
+	}
+	config.UpgradeConfig = params.UpgradeConfig{
+		PrecompileUpgrades: []params.PrecompileUpgrade{
+			{Config: deployerallowlist.NewConfig(utils.NewUint64(4), []common.Address{regenesisAdmin}, nil, nil)},
+			{Config: rewardmanager.NewConfig(utils.NewUint64(1000), []common.Address{regenesisAdmin}, nil, nil, nil)},
+		},
+	}
+	gspec := &Genesis{
+		Config:   &config,
+		Alloc:    GenesisAlloc{regenesisAdmin: {Balance: big.NewInt(params.Ether)}},
+		GasLimit: config.FeeConfig.GasLimit.Uint64(),
+	}
+
+	var (
+		signer = types.LatestSigner(&config)
+		errs   []error
+	)
+	addTx := func(b *BlockGen, key *ecdsa.PrivateKey, to common.Address, value *big.Int, data []byte) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     b.TxNonce(crypto.PubkeyToAddress(key.PublicKey)),
+			GasFeeCap: new(big.Int).Mul(b.BaseFee(), common.Big2),
+			Gas:       1_000_000,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		})
+		errs = append(errs, err)
+		b.AddTx(tx)
+	}
+	_, blocks, _, err := GenerateChainWithGenesis(gspec, dummy.NewCoinbaseFaker(), 4, 2, func(i int, b *BlockGen) {
+		switch i {
+		case 0:
+			data, err := allowlist.PackModifyAllowList(regenesisUser, allowlist.EnabledRole)
+			errs = append(errs, err)
+			addTx(b, regenesisAdminKey, txallowlist.ContractAddress, nil, data)
+			data, err = nativeminter.PackMintNativeCoin(regenesisUser, big.NewInt(params.Ether))
+			errs = append(errs, err)
+			addTx(b, regenesisAdminKey, nativeminter.ContractAddress, nil, data)
+		case 1:
+			addTx(b, regenesisUserKey, regenesisRecipient, big.NewInt(1000), nil)
+			// PUSH1 42 PUSH1 0 SSTORE STOP, returned by the init code.
+			tx, err := types.SignNewTx(regenesisAdminKey, signer, &types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     b.TxNonce(regenesisAdmin),
+				GasFeeCap: new(big.Int).Mul(b.BaseFee(), common.Big2),
+				Gas:       1_000_000,
+				Data:      common.FromHex("0x6006600c60003960066000f3602a60005500"),
+			})
+			errs = append(errs, err)
+			b.AddTx(tx)
+		case 2:
+			addTx(b, regenesisUserKey, regenesisContract, nil, nil)
+			feeConfig := params.DefaultFeeConfig
+			feeConfig.MinBaseFee = big.NewInt(params.GWei)
+			data, err := feemanager.PackSetFeeConfig(feeConfig)
+			errs = append(errs, err)
+			addTx(b, regenesisAdminKey, feemanager.ContractAddress, nil, data)
+		}
+	})
+	require.NoError(err)
+	for _, err := range errs {
+		require.NoError(err)
+	}
+
+	cacheConfig := *DefaultCacheConfig
+	cacheConfig.Preimages = preimages
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), &cacheConfig, gspec, dummy.NewCoinbaseFaker(), vm.Config{}, common.Hash{}, false)
+	require.NoError(err)
+	t.Cleanup(chain.Stop)
+	_, err = chain.InsertChain(blocks)
+	require.NoError(err)
+	return chain
+}
+
+func TestExportGenesis(t *testing.T) {
+	require := require.New(t)
+	chain := newRegenesisTestChain(t, true)
+	head := chain.CurrentBlock()
+
+	var out bytes.Buffer
+	exported, err := ExportGenesis(chain.Config(), chain.StateCache(), head, &out)
+	require.NoError(err)
+	require.Nil(exported.Alloc)
+
+	// The genesis is decoded from the streamed JSON.
+	genesis := new(Genesis)
+	require.NoError(json.Unmarshal(out.Bytes(), genesis))
+	require.Equal(head.Time, genesis.Timestamp)
+
+	// The state is exported.
+	require.Equal(big.NewInt(1000), genesis.Alloc[regenesisRecipient].Balance)
+	require.Equal(uint64(2), genesis.Alloc[regenesisUser].Nonce)
+	require.Equal(common.FromHex("0x602a60005500"), genesis.Alloc[regenesisContract].Code)
+	require.Equal(common.BigToHash(big.NewInt(42)), genesis.Alloc[regenesisContract].Storage[common.Hash{}])
+
+	// The precompile configs reflect the state.
+	config := exported.Config
+	require.Equal(exported.Config.FeeConfig, genesis.Config.FeeConfig)
+	require.Equal(big.NewInt(params.GWei), config.FeeConfig.MinBaseFee)
+	require.Equal(
+		txallowlist.NewConfig(&head.Time, []common.Address{regenesisAdmin}, []common.Address{regenesisUser}, nil),
+		config.GenesisPrecompiles[txallowlist.ConfigKey],
+	)
+	require.Equal(
+		feemanager.NewConfig(&head.Time, []common.Address{regenesisAdmin}, nil, nil, &config.FeeConfig),
+		config.GenesisPrecompiles[feemanager.ConfigKey],
+	)
+	require.Equal(
+		deployerallowlist.NewConfig(&head.Time, []common.Address{regenesisAdmin}, nil, nil),
+		config.GenesisPrecompiles[deployerallowlist.ConfigKey],
+	)
+	require.Contains(config.GenesisPrecompiles, nativeminter.ConfigKey)
+	require.NotContains(config.GenesisPrecompiles, rewardmanager.ConfigKey)
+	// Only the upgrade that is not activated yet is kept.
+	require.Len(config.PrecompileUpgrades, 1)
+	require.Equal(rewardmanager.ConfigKey, config.PrecompileUpgrades[0].Key())
+	// The source config is not modified.
+	require.Len(chain.Config().PrecompileUpgrades, 2)
+
+	// The decoded genesis has the same state root.
+	genesis.Config.AvalancheContext = config.AvalancheContext
+	genesis.Config.UpgradeConfig = config.UpgradeConfig
+	require.Equal(head.Root, genesis.ToBlock().Root())
+}
+
+func TestExportGenesisMissingPreimages(t *testing.T) {
+	chain := newRegenesisTestChain(t, false)
+	_, err := ExportGenesis(chain.Config(), chain.StateCache(), chain.CurrentBlock(), io.Discard)
+	require.ErrorIs(t, err, ErrMissingPreimages)
+}
@@ -4,6 +4,8 @@
 package vm
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/shubhamdubey02/subnet-evm/precompile/contract"
 )
@@ -29,3 +31,55 @@ func (w *wrappedPrecompiledContract) Run(accessibleState contract.AccessibleStat
 func RunStatefulPrecompiledContract(precompile contract.StatefulPrecompiledContract, accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
 	return precompile.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
 }
+
+// tracingAccessibleState wraps an AccessibleState so that the state accesses
+// performed by a stateful precompile are reported to a PrecompileLogger.
+type tracingAccessibleState struct {
+	contract.AccessibleState
+	stateDB *tracingStateDB
+}
+
+// newTracingAccessibleState returns an AccessibleState that reports the state
+// accesses of the precompile at [precompile] to [logger].
+func newTracingAccessibleState(accessibleState contract.AccessibleState, precompile common.Address, logger PrecompileLogger) contract.AccessibleState {
+	return &tracingAccessibleState{
+		AccessibleState: accessibleState,
+		stateDB: &tracingStateDB{
+			StateDB:    accessibleState.GetStateDB(),
+			precompile: precompile,
+			logger:     logger,
+		},
+	}
+}
+
+// GetStateDB implements the AccessibleState interface
+func (t *tracingAccessibleState) GetStateDB() contract.StateDB { return t.stateDB }
+
+// tracingStateDB wraps a StateDB and reports reads, writes, balance changes and
+// logs to [logger] before forwarding them to the underlying StateDB.
+type tracingStateDB struct {
+	contract.StateDB
+	precompile common.Address
+	logger     PrecompileLogger
+}
+
+func (t *tracingStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	value := t.StateDB.GetState(addr, key)
+	t.logger.CapturePrecompileStateRead(t.precompile, addr, key, value)
+	return value
+}
+
+func (t *tracingStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	t.logger.CapturePrecompileStateWrite(t.precompile, addr, key, t.StateDB.GetState(addr, key), value)
+	t.StateDB.SetState(addr, key, value)
+}
+
+func (t *tracingStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	t.logger.CapturePrecompileBalanceChange(t.precompile, addr, t.StateDB.GetBalance(addr), amount)
+	t.StateDB.AddBalance(addr, amount)
+}
+
+func (t *tracingStateDB) AddLog(addr common.Address, topics []common.Hash, data []byte, blockNumber uint64) {
+	t.logger.CapturePrecompileLog(t.precompile, topics, data)
+	t.StateDB.AddLog(addr, topics, data, blockNumber)
+}
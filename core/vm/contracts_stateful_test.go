@@ -0,0 +1,78 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/stretchr/testify/require"
+)
+
+// precompileRecorder records the events reported through PrecompileLogger.
+// EVMLogger is embedded so the recorder can be set as the tracer of an EVM
+// without running any opcodes.
+type precompileRecorder struct {
+	EVMLogger
+	events []string
+}
+
+func (r *precompileRecorder) CapturePrecompileStateRead(precompile common.Address, addr common.Address, key common.Hash, value common.Hash) {
+	r.events = append(r.events, "read")
+}
+
+func (r *precompileRecorder) CapturePrecompileStateWrite(precompile common.Address, addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	r.events = append(r.events, "write "+prev.Hex()+" -> "+value.Hex())
+}
+
+func (r *precompileRecorder) CapturePrecompileBalanceChange(precompile common.Address, addr common.Address, prev *big.Int, amount *big.Int) {
+	r.events = append(r.events, "balance "+prev.String()+" + "+amount.String())
+}
+
+func (r *precompileRecorder) CapturePrecompileLog(precompile common.Address, topics []common.Hash, data []byte) {
+	r.events = append(r.events, "log")
+}
+
+func TestPrecompileAccessibleState(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		precompile = common.HexToAddress("0x0200000000000000000000000000000000000001")
+		addr       = common.HexToAddress("0x1234")
+		key        = common.Hash{1}
+		value      = common.Hash{2}
+	)
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(err)
+
+	// Without a PrecompileLogger the EVM is used directly.
+	evm := NewEVM(BlockContext{}, TxContext{}, statedb, params.TestChainConfig, Config{})
+	require.Equal(evm, evm.precompileAccessibleState(precompile))
+
+	recorder := &precompileRecorder{}
+	evm = NewEVM(BlockContext{}, TxContext{}, statedb, params.TestChainConfig, Config{Tracer: recorder})
+	stateDB := evm.precompileAccessibleState(precompile).GetStateDB()
+
+	stateDB.SetState(addr, key, value)
+	require.Equal(value, stateDB.GetState(addr, key))
+	stateDB.AddBalance(addr, big.NewInt(5))
+	stateDB.AddBalance(addr, big.NewInt(3))
+	stateDB.AddLog(precompile, nil, nil, 0)
+
+	require.Equal([]string{
+		"write " + (common.Hash{}).Hex() + " -> " + value.Hex(),
+		"read",
+		"balance 0 + 5",
+		"balance 5 + 3",
+		"log",
+	}, recorder.events)
+	// The accesses are applied to the underlying StateDB.
+	require.Equal(value, statedb.GetState(addr, key))
+	require.Equal(big.NewInt(8), statedb.GetBalance(addr))
+}
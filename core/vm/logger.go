@@ -51,3 +51,21 @@ type EVMLogger interface {
 	CaptureState(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, rData []byte, depth int, err error)
 	CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error)
 }
+
+// PrecompileLogger is an optional interface that an EVMLogger may implement to
+// observe the state accessed by stateful precompiles. Stateful precompiles read
+// and write state directly instead of executing opcodes, so these accesses are
+// otherwise invisible to CaptureState.
+//
+// The Capture methods are invoked before the access is applied to the StateDB,
+// so a tracer may still read the previous value from the StateDB.
+type PrecompileLogger interface {
+	// CapturePrecompileStateRead is called when [precompile] reads storage slot [key] of [addr].
+	CapturePrecompileStateRead(precompile common.Address, addr common.Address, key common.Hash, value common.Hash)
+	// CapturePrecompileStateWrite is called when [precompile] writes [value] to storage slot [key] of [addr].
+	CapturePrecompileStateWrite(precompile common.Address, addr common.Address, key common.Hash, prev common.Hash, value common.Hash)
+	// CapturePrecompileBalanceChange is called when [precompile] adds [amount] to the balance of [addr].
+	CapturePrecompileBalanceChange(precompile common.Address, addr common.Address, prev *big.Int, amount *big.Int)
+	// CapturePrecompileLog is called when [precompile] emits a log.
+	CapturePrecompileLog(precompile common.Address, topics []common.Hash, data []byte)
+}
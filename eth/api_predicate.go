@@ -0,0 +1,205 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+)
+
+var errMissingSnowContext = errors.New("chain config has no snow context")
+
+// PredicateResult is the verification result of the predicates of a transaction
+// for a single precompile.
+type PredicateResult struct {
+	Address common.Address `json:"address"`
+	// Results is the bitset of failed predicate indices as stored in the block.
+	Results hexutil.Bytes `json:"results"`
+	// FailedIndices are the indices of the predicates of [Address] that failed
+	// verification, in the order they appear in the access list.
+	FailedIndices []int `json:"failedIndices"`
+}
+
+// TxPredicateResults are the predicate results of a transaction.
+type TxPredicateResults struct {
+	TxHash  common.Hash        `json:"transactionHash"`
+	TxIndex hexutil.Uint       `json:"transactionIndex"`
+	Results []*PredicateResult `json:"results"`
+}
+
+// PredicateVerification is the verification result of a single predicate.
+type PredicateVerification struct {
+	Index int    `json:"index"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// PrecompilePredicateVerifications are the verification results of the
+// predicates of a transaction for a single precompile.
+type PrecompilePredicateVerifications struct {
+	Address    common.Address           `json:"address"`
+	Predicates []*PredicateVerification `json:"predicates"`
+}
+
+// PredicatesVerification is the result of re-verifying the predicates of a
+// transaction.
+type PredicatesVerification struct {
+	BlockNumber  hexutil.Uint64                      `json:"blockNumber"`
+	PChainHeight hexutil.Uint64                      `json:"pChainHeight"`
+	Precompiles  []*PrecompilePredicateVerifications `json:"precompiles"`
+}
+
+// PredicateBlockContext is the context predicates are verified against.
+type PredicateBlockContext struct {
+	// BlockNumberOrHash is the block whose rules apply. Defaults to the last
+	// accepted block.
+	BlockNumberOrHash *rpc.BlockNumberOrHash `json:"blockNumberOrHash"`
+	// PChainHeight is the P-Chain height validator sets are read at. Defaults
+	// to the current P-Chain height.
+	PChainHeight *hexutil.Uint64 `json:"pChainHeight"`
+}
+
+// GetPredicateResults decodes the predicate results stored in the header of the
+// block with [blockHash], ordered by transaction index. Transactions without
+// predicates are omitted.
+func (api *DebugAPI) GetPredicateResults(ctx context.Context, blockHash common.Hash) ([]*TxPredicateResults, error) {
+	blk := api.eth.blockchain.GetBlockByHash(blockHash)
+	if blk == nil {
+		return nil, fmt.Errorf("block %s not found", blockHash)
+	}
+	resultsBytes, ok := predicate.GetPredicateResultBytes(blk.Extra())
+	if !ok {
+		// Blocks before Durango do not contain predicate results.
+		return []*TxPredicateResults{}, nil
+	}
+	results, err := predicate.ParseResults(resultsBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	txResults := make([]*TxPredicateResults, 0, len(results.Results))
+	for i, tx := range blk.Transactions() {
+		precompileResults, ok := results.Results[tx.Hash()]
+		if !ok {
+			continue
+		}
+		txResult := &TxPredicateResults{
+			TxHash:  tx.Hash(),
+			TxIndex: hexutil.Uint(i),
+			Results: make([]*PredicateResult, 0, len(precompileResults)),
+		}
+		for address, bitsetBytes := range precompileResults {
+			txResult.Results = append(txResult.Results, &PredicateResult{
+				Address:       address,
+				Results:       bitsetBytes,
+				FailedIndices: failedIndices(bitsetBytes),
+			})
+		}
+		sort.Slice(txResult.Results, func(i, j int) bool {
+			return txResult.Results[i].Address.Cmp(txResult.Results[j].Address) < 0
+		})
+		txResults = append(txResults, txResult)
+	}
+	return txResults, nil
+}
+
+// VerifyPredicates re-verifies the predicates of the binary encoded transaction
+// [input] against [blockContext] and reports why each failing predicate failed.
+func (api *DebugAPI) VerifyPredicates(ctx context.Context, input hexutil.Bytes, blockContext *PredicateBlockContext) (*PredicatesVerification, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	if blockContext == nil {
+		blockContext = &PredicateBlockContext{}
+	}
+
+	header := api.eth.LastAcceptedBlock().Header()
+	if blockContext.BlockNumberOrHash != nil {
+		blk, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, *blockContext.BlockNumberOrHash)
+		if err != nil {
+			return nil, err
+		}
+		if blk == nil {
+			return nil, errors.New("block not found")
+		}
+		header = blk.Header()
+	}
+
+	config := api.eth.blockchain.Config()
+	snowCtx := config.SnowCtx
+	if snowCtx == nil {
+		return nil, errMissingSnowContext
+	}
+	var pChainHeight uint64
+	switch {
+	case blockContext.PChainHeight != nil:
+		pChainHeight = uint64(*blockContext.PChainHeight)
+	case snowCtx.ValidatorState != nil:
+		height, err := snowCtx.ValidatorState.GetCurrentHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current P-Chain height: %w", err)
+		}
+		pChainHeight = height
+	default:
+		return nil, errors.New("pChainHeight must be specified")
+	}
+
+	rules := config.Rules(header.Number, header.Time)
+	predicateErrs, err := core.VerifyPredicates(rules, &precompileconfig.PredicateContext{
+		SnowCtx:            snowCtx,
+		ProposerVMBlockCtx: &block.Context{PChainHeight: pChainHeight},
+	}, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PredicatesVerification{
+		BlockNumber:  hexutil.Uint64(header.Number.Uint64()),
+		PChainHeight: hexutil.Uint64(pChainHeight),
+		Precompiles:  make([]*PrecompilePredicateVerifications, 0, len(predicateErrs)),
+	}
+	for address, errs := range predicateErrs {
+		precompile := &PrecompilePredicateVerifications{
+			Address:    address,
+			Predicates: make([]*PredicateVerification, len(errs)),
+		}
+		for i, err := range errs {
+			verification := &PredicateVerification{Index: i, Valid: err == nil}
+			if err != nil {
+				verification.Error = err.Error()
+			}
+			precompile.Predicates[i] = verification
+		}
+		result.Precompiles = append(result.Precompiles, precompile)
+	}
+	sort.Slice(result.Precompiles, func(i, j int) bool {
+		return result.Precompiles[i].Address.Cmp(result.Precompiles[j].Address) < 0
+	})
+	return result, nil
+}
+
+// failedIndices returns the indices set in the predicate results bitset [b].
+func failedIndices(b []byte) []int {
+	bits := set.BitsFromBytes(b)
+	indices := make([]int, 0, bits.Len())
+	for i := 0; i < bits.BitLen(); i++ {
+		if bits.Contains(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
@@ -288,7 +288,7 @@ func (s *Ethereum) APIs() []rpc.API {
 	apis := ethapi.GetAPIs(s.APIBackend)
 
 	// Append tracing APIs
-	apis = append(apis, tracers.APIs(s.APIBackend)...)
+	apis = append(apis, tracers.APIs(s.APIBackend, s.config.TraceOutputDir)...)
 
 	// Add the APIs from the node
 	apis = append(apis, s.stackRPCs...)
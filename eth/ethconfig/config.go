@@ -134,6 +134,10 @@ type Config struct {
 	// AllowUnfinalizedQueries allow unfinalized queries
 	AllowUnfinalizedQueries bool
 
+	// TraceOutputDir is the directory block range traces are written under.
+	// If empty, they are written under the temporary directory.
+	TraceOutputDir string
+
 	// AllowUnprotectedTxs allow unprotected transactions to be locally issued.
 	// Unprotected transactions are transactions that are signed without EIP-155
 	// replay protection.
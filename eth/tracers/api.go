@@ -120,12 +120,18 @@ func NewAPI(backend Backend) *API {
 // debugging endpoint that log their output to a file.
 type FileTracerAPI struct {
 	baseAPI
+	// outputDir is the directory TraceBlockRangeToFiles writes to.
+	outputDir string
 }
 
 // NewFileTracerAPI creates a new API definition for the tracing methods of the Ethererum
-// service that log their output to a file.
-func NewFileTracerAPI(backend Backend) *FileTracerAPI {
-	return &FileTracerAPI{baseAPI{backend: backend}}
+// service that log their output to a file. Block range traces are written under
+// [outputDir], or under the temporary directory if it is empty.
+func NewFileTracerAPI(backend Backend, outputDir string) *FileTracerAPI {
+	if outputDir == "" {
+		outputDir = os.TempDir()
+	}
+	return &FileTracerAPI{baseAPI: baseAPI{backend: backend}, outputDir: outputDir}
 }
 
 // chainContext constructs the context reader which is used by the evm for reading
@@ -272,7 +278,7 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 // the end block but excludes the start one. The return value will be one item per
 // transaction, dependent on the requested tracer.
 // The tracing procedure should be aborted in case the closed signal is received.
-func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed <-chan interface{}) chan *blockTraceResult {
+func (api *baseAPI) traceChain(start, end *types.Block, config *TraceConfig, closed <-chan interface{}) chan *blockTraceResult {
 	reexec := defaultTraceReexec
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
@@ -1025,7 +1031,8 @@ func (api *baseAPI) traceTx(ctx context.Context, message *core.Message, txctx *C
 }
 
 // APIs return the collection of RPC services the tracer package offers.
-func APIs(backend Backend) []rpc.API {
+// [traceOutputDir] is the directory block range traces are written under.
+func APIs(backend Backend, traceOutputDir string) []rpc.API {
 	// Append all the local APIs and return
 	return []rpc.API{
 		{
@@ -1035,7 +1042,7 @@ func APIs(backend Backend) []rpc.API {
 		},
 		{
 			Namespace: "debug",
-			Service:   NewFileTracerAPI(backend),
+			Service:   NewFileTracerAPI(backend, traceOutputDir),
 			Name:      "debug-file-tracer",
 		},
 	}
@@ -0,0 +1,412 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+)
+
+const (
+	// defaultBlocksPerShard is the number of blocks written to a single shard
+	// by TraceBlockRangeToFiles if not specified otherwise.
+	defaultBlocksPerShard = uint64(1000)
+
+	// blockRangeManifestFile is the name of the manifest written to the output
+	// directory of TraceBlockRangeToFiles.
+	blockRangeManifestFile = "manifest.json"
+)
+
+var (
+	errManifestMismatch = errors.New("existing manifest does not match the requested trace")
+	errInvalidTraceDir  = errors.New("trace directory must be a relative path within the trace output directory")
+)
+
+// BlockRangeTraceConfig holds extra parameters to TraceBlockRangeToFiles.
+type BlockRangeTraceConfig struct {
+	TraceConfig
+	// Dir is the directory the shards and the manifest are written to,
+	// relative to the trace output directory of the node. If it already
+	// contains a manifest for the same request, tracing resumes after the last
+	// completed shard. If empty, a new directory is created.
+	Dir string
+	// BlocksPerShard is the number of blocks written to each shard.
+	BlocksPerShard *uint64
+}
+
+// blockRangeManifest describes the shards written by TraceBlockRangeToFiles.
+// It is rewritten after each completed shard, so it can be used to resume an
+// interrupted trace.
+type blockRangeManifest struct {
+	Start          uint64            `json:"start"`
+	End            uint64            `json:"end"`
+	Tracer         string            `json:"tracer"`
+	TracerConfig   json.RawMessage   `json:"tracerConfig,omitempty"`
+	BlocksPerShard uint64            `json:"blocksPerShard"`
+	Shards         []blockRangeShard `json:"shards"`
+	Complete       bool              `json:"complete"`
+}
+
+// blockRangeShard is a single gzip compressed JSONL file holding the traces of
+// all transactions in the blocks [From, To].
+type blockRangeShard struct {
+	File         string `json:"file"`
+	From         uint64 `json:"from"`
+	To           uint64 `json:"to"`
+	Transactions uint64 `json:"transactions"`
+}
+
+// blockRangeTraceLine is a single line of a shard.
+type blockRangeTraceLine struct {
+	Block  hexutil.Uint64 `json:"block"`
+	Hash   common.Hash    `json:"hash"`
+	TxHash common.Hash    `json:"txHash"`
+	Result interface{}    `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// BlockRangeTraceResult is the result of TraceBlockRangeToFiles.
+type BlockRangeTraceResult struct {
+	Dir      string   `json:"dir"`
+	Manifest string   `json:"manifest"`
+	Files    []string `json:"files"`
+	Complete bool     `json:"complete"`
+}
+
+// nextBlock returns the first block not covered by a completed shard.
+func (m *blockRangeManifest) nextBlock() uint64 {
+	if len(m.Shards) == 0 {
+		return m.Start
+	}
+	return m.Shards[len(m.Shards)-1].To + 1
+}
+
+// matches returns true if [m] was written for the same request as [other].
+func (m *blockRangeManifest) matches(other *blockRangeManifest) bool {
+	return m.Start == other.Start && m.End == other.End && m.Tracer == other.Tracer &&
+		m.BlocksPerShard == other.BlocksPerShard && bytes.Equal(compactJSON(m.TracerConfig), compactJSON(other.TracerConfig))
+}
+
+// compactJSON returns [raw] with insignificant whitespace removed, or [raw]
+// itself if it is not valid JSON.
+func compactJSON(raw json.RawMessage) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// TraceBlockRangeToFiles traces all transactions in the blocks [start, end]
+// with the configured tracer and writes the results to gzip compressed JSONL
+// shards in the configured directory under the trace output directory of the
+// node, along with a manifest describing them.
+// Blocks are traced in parallel as in TraceChain. If the call is interrupted, it
+// can be repeated with the same directory to resume after the last completed shard.
+func (api *FileTracerAPI) TraceBlockRangeToFiles(ctx context.Context, start, end rpc.BlockNumber, config *BlockRangeTraceConfig) (*BlockRangeTraceResult, error) {
+	if config == nil {
+		config = &BlockRangeTraceConfig{}
+	}
+	first, err := api.blockByNumber(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	last, err := api.blockByNumber(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+	if first.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	if first.NumberU64() > last.NumberU64() {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end, start)
+	}
+
+	manifest := &blockRangeManifest{
+		Start:          first.NumberU64(),
+		End:            last.NumberU64(),
+		TracerConfig:   config.TracerConfig,
+		BlocksPerShard: defaultBlocksPerShard,
+	}
+	if config.Tracer != nil {
+		manifest.Tracer = *config.Tracer
+	}
+	if config.BlocksPerShard != nil {
+		if *config.BlocksPerShard == 0 {
+			return nil, errors.New("blocksPerShard must be positive")
+		}
+		manifest.BlocksPerShard = *config.BlocksPerShard
+	}
+	dir, err := api.blockRangeDir(config.Dir, manifest)
+	if err != nil {
+		return nil, err
+	}
+	manifestPath := filepath.Join(dir, blockRangeManifestFile)
+
+	// Resume from an existing manifest if it was written for the same request
+	if existing, err := readBlockRangeManifest(manifestPath); err != nil {
+		return nil, err
+	} else if existing != nil {
+		if !existing.matches(manifest) {
+			return nil, fmt.Errorf("%w: %s", errManifestMismatch, manifestPath)
+		}
+		manifest = existing
+		log.Info("Resuming block range trace", "dir", dir, "start", manifest.Start, "end", manifest.End, "next", manifest.nextBlock())
+	}
+	if err := api.traceBlockRangeToFiles(ctx, dir, manifest, &config.TraceConfig); err != nil {
+		return nil, err
+	}
+	result := &BlockRangeTraceResult{
+		Dir:      dir,
+		Manifest: manifestPath,
+		Complete: manifest.Complete,
+	}
+	for _, shard := range manifest.Shards {
+		result.Files = append(result.Files, filepath.Join(dir, shard.File))
+	}
+	return result, nil
+}
+
+// blockRangeDir creates and returns the directory [dir] under the output
+// directory of [api], or a new directory if [dir] is empty. [dir] is supplied by
+// RPC callers, so it must be a relative path that does not escape the output
+// directory.
+func (api *FileTracerAPI) blockRangeDir(dir string, manifest *blockRangeManifest) (string, error) {
+	if dir == "" {
+		return os.MkdirTemp(api.outputDir, fmt.Sprintf("trace_%d-%d-", manifest.Start, manifest.End))
+	}
+	if !filepath.IsLocal(dir) {
+		return "", fmt.Errorf("%w: %s", errInvalidTraceDir, dir)
+	}
+	dir = filepath.Join(api.outputDir, dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// traceBlockRangeToFiles traces the blocks not yet covered by a completed shard
+// of [manifest], writing new shards to [dir] and updating the manifest after
+// each of them. It returns without error if [ctx] is cancelled, leaving the
+// manifest describing the completed shards.
+func (api *FileTracerAPI) traceBlockRangeToFiles(ctx context.Context, dir string, manifest *blockRangeManifest, config *TraceConfig) error {
+	manifestPath := filepath.Join(dir, blockRangeManifestFile)
+	next := manifest.nextBlock()
+	if next > manifest.End {
+		manifest.Complete = true
+		return writeBlockRangeManifest(manifestPath, manifest)
+	}
+	// traceChain excludes the start block, so begin from the parent of [next].
+	from, err := api.blockByNumber(ctx, rpc.BlockNumber(next-1))
+	if err != nil {
+		return err
+	}
+	to, err := api.blockByNumber(ctx, rpc.BlockNumber(manifest.End))
+	if err != nil {
+		return err
+	}
+
+	closed := make(chan interface{})
+	resCh := api.traceChain(from, to, config, closed)
+	defer func() {
+		// Abort tracing and drain any results still being delivered.
+		close(closed)
+		go func() {
+			for range resCh {
+			}
+		}()
+	}()
+
+	var (
+		shard  *shardWriter
+		traced = next - 1 // last block received from the tracer
+	)
+	// Make sure a partially written shard is removed on any exit path.
+	defer func() {
+		if shard != nil {
+			shard.abort()
+		}
+	}()
+	for {
+		var (
+			res *blockTraceResult
+			ok  bool
+		)
+		select {
+		case res, ok = <-resCh:
+		case <-ctx.Done():
+			log.Warn("Block range trace interrupted", "dir", dir, "traced", traced, "err", ctx.Err())
+			return writeBlockRangeManifest(manifestPath, manifest)
+		}
+		if !ok {
+			break
+		}
+		number := uint64(res.Block)
+		// Blocks without transactions are not emitted by traceChain, so complete
+		// all the shards ending before [number].
+		for shard != nil && number > shard.info.To {
+			if err := api.completeShard(manifestPath, manifest, shard); err != nil {
+				return err
+			}
+			shard = nil
+		}
+		for shard == nil {
+			if shard, err = newShardWriter(dir, manifest.nextBlock(), manifest); err != nil {
+				return err
+			}
+			if number > shard.info.To {
+				if err := api.completeShard(manifestPath, manifest, shard); err != nil {
+					return err
+				}
+				shard = nil
+			}
+		}
+		for _, trace := range res.Traces {
+			if trace == nil {
+				continue
+			}
+			line := blockRangeTraceLine{
+				Block:  res.Block,
+				Hash:   res.Hash,
+				TxHash: trace.TxHash,
+				Result: trace.Result,
+				Error:  trace.Error,
+			}
+			if err := shard.write(&line); err != nil {
+				return err
+			}
+		}
+		traced = number
+	}
+	// traceChain always emits the end block unless tracing failed.
+	if traced != manifest.End {
+		return fmt.Errorf("tracing aborted after block %d, see logs for details", traced)
+	}
+	if shard != nil {
+		if err := api.completeShard(manifestPath, manifest, shard); err != nil {
+			return err
+		}
+		shard = nil
+	}
+	manifest.Complete = true
+	return writeBlockRangeManifest(manifestPath, manifest)
+}
+
+// completeShard finalizes [shard] and records it in [manifest].
+func (api *FileTracerAPI) completeShard(manifestPath string, manifest *blockRangeManifest, shard *shardWriter) error {
+	if err := shard.close(); err != nil {
+		return err
+	}
+	manifest.Shards = append(manifest.Shards, shard.info)
+	return writeBlockRangeManifest(manifestPath, manifest)
+}
+
+// shardWriter writes a single shard to a temporary file, which is renamed to
+// its final name once the shard is complete.
+type shardWriter struct {
+	info blockRangeShard
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	enc  *json.Encoder
+}
+
+// newShardWriter creates the shard starting at block [from] in [dir].
+func newShardWriter(dir string, from uint64, manifest *blockRangeManifest) (*shardWriter, error) {
+	to := from + manifest.BlocksPerShard - 1
+	if to > manifest.End {
+		to = manifest.End
+	}
+	name := fmt.Sprintf("traces_%010d-%010d.jsonl.gz", from, to)
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	buf := bufio.NewWriter(gz)
+	return &shardWriter{
+		info: blockRangeShard{File: name, From: from, To: to},
+		path: path,
+		file: file,
+		gz:   gz,
+		buf:  buf,
+		enc:  json.NewEncoder(buf),
+	}, nil
+}
+
+// write appends [line] to the shard.
+func (s *shardWriter) write(line *blockRangeTraceLine) error {
+	s.info.Transactions++
+	return s.enc.Encode(line)
+}
+
+// close flushes the shard to disk and moves it to its final name.
+func (s *shardWriter) close() error {
+	if err := s.buf.Flush(); err != nil {
+		s.abort()
+		return err
+	}
+	if err := s.gz.Close(); err != nil {
+		s.abort()
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		s.abort()
+		return err
+	}
+	if err := s.file.Close(); err != nil {
+		os.Remove(s.file.Name())
+		return err
+	}
+	return os.Rename(s.file.Name(), s.path)
+}
+
+// abort removes the partially written shard.
+func (s *shardWriter) abort() {
+	s.file.Close()
+	os.Remove(s.file.Name())
+}
+
+// readBlockRangeManifest reads the manifest at [path]. It returns nil if the
+// manifest does not exist.
+func readBlockRangeManifest(path string) (*blockRangeManifest, error) {
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest blockRangeManifest
+	if err := json.Unmarshal(blob, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// writeBlockRangeManifest atomically replaces the manifest at [path].
+func writeBlockRangeManifest(path string, manifest *blockRangeManifest) error {
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", blob, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,119 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceBlockRangeToFiles(t *testing.T) {
+	require := require.New(t)
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(5 * params.Ether)},
+		},
+	}
+	var (
+		signer = types.HomesteadSigner{}
+		nonce  uint64
+		txs    = make(map[uint64]int)
+	)
+	backend := newTestBackend(t, 10, genesis, func(i int, b *core.BlockGen) {
+		// Leave every third block empty
+		if i%3 == 2 {
+			return
+		}
+		for j := 0; j < i+1; j++ {
+			tx, _ := types.SignTx(types.NewTransaction(nonce, accounts[1].addr, big.NewInt(1000), params.TxGas, b.BaseFee(), nil), signer, accounts[0].key)
+			b.AddTx(tx)
+			nonce++
+			txs[b.Number().Uint64()]++
+		}
+	})
+	defer backend.teardown()
+	outputDir := t.TempDir()
+	api := NewFileTracerAPI(backend, outputDir)
+
+	var (
+		blocksPerShard = uint64(3)
+		config         = &BlockRangeTraceConfig{
+			Dir:            "range",
+			BlocksPerShard: &blocksPerShard,
+		}
+	)
+
+	result, err := api.TraceBlockRangeToFiles(context.Background(), 1, 10, config)
+	require.NoError(err)
+	require.Equal(filepath.Join(outputDir, "range"), result.Dir)
+	require.True(result.Complete)
+	require.Len(result.Files, 4) // [1, 3], [4, 6], [7, 9], [10, 10]
+	require.Equal(txs, readShardLines(t, result.Files))
+
+	// Drop the last two shards from the manifest to simulate an interruption
+	// and make sure tracing resumes from the first missing shard.
+	manifest, err := readBlockRangeManifest(result.Manifest)
+	require.NoError(err)
+	manifest.Shards = manifest.Shards[:2]
+	manifest.Complete = false
+	require.NoError(writeBlockRangeManifest(result.Manifest, manifest))
+	require.NoError(os.Remove(result.Files[3]))
+
+	resumed, err := api.TraceBlockRangeToFiles(context.Background(), 1, 10, config)
+	require.NoError(err)
+	require.True(resumed.Complete)
+	require.Equal(result.Files, resumed.Files)
+	require.Equal(txs, readShardLines(t, resumed.Files))
+
+	// A different request must not reuse the directory.
+	_, err = api.TraceBlockRangeToFiles(context.Background(), 2, 10, config)
+	require.ErrorIs(err, errManifestMismatch)
+
+	// Directories outside of the output directory are rejected.
+	for _, dir := range []string{t.TempDir(), "../range", "range/../../range"} {
+		_, err = api.TraceBlockRangeToFiles(context.Background(), 1, 10, &BlockRangeTraceConfig{Dir: dir})
+		require.ErrorIs(err, errInvalidTraceDir)
+	}
+
+	// Without a directory, a new one is created in the output directory.
+	result, err = api.TraceBlockRangeToFiles(context.Background(), 1, 1, nil)
+	require.NoError(err)
+	require.Equal(outputDir, filepath.Dir(result.Dir))
+}
+
+// readShardLines returns the number of traced transactions per block in [files].
+func readShardLines(t *testing.T, files []string) map[uint64]int {
+	lines := make(map[uint64]int)
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		defer f.Close()
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			var line blockRangeTraceLine
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			require.Empty(t, line.Error)
+			lines[uint64(line.Block)]++
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, ".gz", filepath.Ext(file))
+	}
+	return lines
+}
@@ -74,7 +74,9 @@ type callTrace struct {
 	RevertReason string          `json:"revertReason,omitempty"`
 	Calls        []callTrace     `json:"calls,omitempty"`
 	Logs         []callLog       `json:"logs,omitempty"`
-	Value        *hexutil.Big    `json:"value,omitempty"`
+	// PrecompileState is compared verbatim against the tracer output
+	PrecompileState json.RawMessage `json:"precompileState,omitempty"`
+	Value           *hexutil.Big    `json:"value,omitempty"`
 	// Gencodec adds overridden fields at the end
 	Type string `json:"type"`
 }
@@ -0,0 +1,213 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/eth/tracers"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/tests"
+	"github.com/stretchr/testify/require"
+)
+
+// gasProfile is the result of a gasProfilerTracer run.
+type gasProfile struct {
+	GasUsed      uint64 `json:"gasUsed"`
+	IntrinsicGas uint64 `json:"intrinsicGas"`
+	Refund       uint64 `json:"refund"`
+	Contracts    map[common.Address]struct {
+		GasUsed   uint64 `json:"gasUsed"`
+		Functions map[string]struct {
+			GasUsed uint64 `json:"gasUsed"`
+		} `json:"functions"`
+	} `json:"contracts"`
+	Opcodes map[string]uint64 `json:"opcodes"`
+	Storage map[common.Address]map[common.Hash]struct {
+		SloadCold  uint64 `json:"sloadCold"`
+		SloadWarm  uint64 `json:"sloadWarm"`
+		SstoreCold uint64 `json:"sstoreCold"`
+		SstoreWarm uint64 `json:"sstoreWarm"`
+		GasUsed    uint64 `json:"gasUsed"`
+	} `json:"storage"`
+	FlameGraph []string `json:"flameGraph"`
+}
+
+// TestGasProfilerTracer runs the gasProfilerTracer against the call tracer
+// test suite and checks that the gas attributed to contracts, functions and
+// the flame graph adds up to the gas used by the transaction.
+func TestGasProfilerTracer(t *testing.T) {
+	files, err := os.ReadDir(filepath.Join("testdata", "call_tracer"))
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		file := file // capture range variable
+		t.Run(camel(strings.TrimSuffix(file.Name(), ".json")), func(t *testing.T) {
+			t.Parallel()
+			require := require.New(t)
+
+			var (
+				test = new(callTracerTest)
+				tx   = new(types.Transaction)
+			)
+			blob, err := os.ReadFile(filepath.Join("testdata", "call_tracer", file.Name()))
+			require.NoError(err)
+			require.NoError(json.Unmarshal(blob, test))
+			require.NoError(tx.UnmarshalBinary(common.FromHex(test.Input)))
+
+			var (
+				signer    = types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)), uint64(test.Context.Time))
+				origin, _ = signer.Sender(tx)
+				txContext = vm.TxContext{
+					Origin:   origin,
+					GasPrice: tx.GasPrice(),
+				}
+				context = vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					Coinbase:    test.Context.Miner,
+					BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
+					Time:        uint64(test.Context.Time),
+					Difficulty:  (*big.Int)(test.Context.Difficulty),
+					GasLimit:    uint64(test.Context.GasLimit),
+					BaseFee:     test.Genesis.BaseFee,
+				}
+				_, statedb = tests.MakePreState(rawdb.NewMemoryDatabase(), test.Genesis.Alloc, false)
+			)
+			tracer, err := tracers.DefaultDirectory.New("gasProfilerTracer", new(tracers.Context), nil)
+			require.NoError(err)
+			evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Tracer: tracer})
+			msg, err := core.TransactionToMessage(tx, signer, nil)
+			require.NoError(err)
+			vmRet, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+			require.NoError(err)
+
+			res, err := tracer.GetResult()
+			require.NoError(err)
+			var profile gasProfile
+			require.NoError(json.Unmarshal(res, &profile))
+
+			require.Equal(vmRet.UsedGas, profile.GasUsed)
+			executed := profile.GasUsed + profile.Refund
+
+			var contracts uint64
+			for _, contract := range profile.Contracts {
+				var functions uint64
+				for _, function := range contract.Functions {
+					functions += function.GasUsed
+				}
+				require.Equal(contract.GasUsed, functions)
+				contracts += contract.GasUsed
+			}
+			require.Equal(executed, contracts+profile.IntrinsicGas)
+
+			var flame uint64
+			for _, line := range profile.FlameGraph {
+				idx := strings.LastIndexByte(line, ' ')
+				require.Positive(idx)
+				gas, err := strconv.ParseUint(line[idx+1:], 10, 64)
+				require.NoError(err)
+				flame += gas
+			}
+			require.Equal(executed, flame)
+		})
+	}
+}
+
+// TestGasProfilerTracerOpcodes checks the gas attributed to opcode classes and
+// the cold and warm accesses counted for a storage slot read twice and written.
+func TestGasProfilerTracerOpcodes(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		to     = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+		origin = common.HexToAddress("0x00000000000000000000000000000000feed")
+		slot   = common.BigToHash(big.NewInt(1))
+		code   = []byte{
+			byte(vm.PUSH1), 0x1,
+			byte(vm.SLOAD), // cold
+			byte(vm.POP),
+			byte(vm.PUSH1), 0x1,
+			byte(vm.SLOAD), // warm
+			byte(vm.POP),
+			byte(vm.PUSH1), 0x2a,
+			byte(vm.PUSH1), 0x1,
+			byte(vm.SSTORE), // warm, sets a zero slot
+			byte(vm.STOP),
+		}
+		txContext = vm.TxContext{
+			Origin:   origin,
+			GasPrice: big.NewInt(0),
+		}
+		context = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: big.NewInt(1),
+			Time:        1,
+			Difficulty:  big.NewInt(0),
+			GasLimit:    uint64(8000000),
+			BaseFee:     big.NewInt(0),
+		}
+	)
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(),
+		core.GenesisAlloc{
+			to:     core.GenesisAccount{Code: code, Balance: big.NewInt(0)},
+			origin: core.GenesisAccount{Balance: big.NewInt(0)},
+		}, false)
+	tracer, err := tracers.DefaultDirectory.New("gasProfilerTracer", new(tracers.Context), nil)
+	require.NoError(err)
+	evm := vm.NewEVM(context, txContext, statedb, params.TestChainConfig, vm.Config{Tracer: tracer})
+	msg := &core.Message{
+		To:        &to,
+		From:      origin,
+		Value:     big.NewInt(0),
+		GasLimit:  100000,
+		GasPrice:  big.NewInt(0),
+		GasFeeCap: big.NewInt(0),
+		GasTipCap: big.NewInt(0),
+	}
+	vmRet, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit))
+	require.NoError(err)
+	require.NoError(vmRet.Err)
+
+	res, err := tracer.GetResult()
+	require.NoError(err)
+	var profile gasProfile
+	require.NoError(json.Unmarshal(res, &profile))
+
+	const (
+		storageGas = params.ColdSloadCostEIP2929 + params.WarmStorageReadCostEIP2929 + params.SstoreSetGasEIP2200
+		stackGas   = 4*vm.GasFastestStep + 2*vm.GasQuickStep
+	)
+	require.Equal(map[string]uint64{
+		"intrinsic": params.TxGas,
+		"storage":   storageGas,
+		"stack":     stackGas,
+		"control":   0,
+	}, profile.Opcodes)
+	require.Equal(params.TxGas+storageGas+stackGas, profile.GasUsed)
+	require.Equal(vmRet.UsedGas, profile.GasUsed)
+
+	stats := profile.Storage[to][slot]
+	require.EqualValues(1, stats.SloadCold)
+	require.EqualValues(1, stats.SloadWarm)
+	require.EqualValues(0, stats.SstoreCold)
+	require.EqualValues(1, stats.SstoreWarm)
+	require.Equal(storageGas, stats.GasUsed)
+}
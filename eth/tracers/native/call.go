@@ -65,6 +65,9 @@ type callFrame struct {
 	RevertReason string          `json:"revertReason,omitempty"`
 	Calls        []callFrame     `json:"calls,omitempty" rlp:"optional"`
 	Logs         []callLog       `json:"logs,omitempty" rlp:"optional"`
+	// PrecompileState contains the state accessed by a stateful precompile
+	// executed in this frame.
+	PrecompileState []precompileAccess `json:"precompileState,omitempty" rlp:"-"`
 	// Placed at end on purpose. The RLP will be decoded to 0 instead of
 	// nil if there are non-empty elements after in the struct.
 	Value *big.Int `json:"value,omitempty" rlp:"optional"`
@@ -121,6 +124,8 @@ type callTracer struct {
 type callTracerConfig struct {
 	OnlyTopCall bool `json:"onlyTopCall"` // If true, call tracer won't collect any subcalls
 	WithLog     bool `json:"withLog"`     // If true, call tracer will collect event logs
+	// If true, call tracer will collect the state accessed by stateful precompiles
+	WithPrecompileState bool `json:"withPrecompileState"`
 }
 
 // newCallTracer returns a native go tracer which tracks
@@ -246,6 +251,77 @@ func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
 	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
 }
 
+// CapturePrecompileStateRead implements the vm.PrecompileLogger interface to
+// record storage slots read by stateful precompiles.
+func (t *callTracer) CapturePrecompileStateRead(precompile common.Address, addr common.Address, key common.Hash, value common.Hash) {
+	keyCopy, valueCopy := key, value
+	t.addPrecompileAccess(precompileAccess{
+		Precompile: precompile,
+		Type:       "read",
+		Address:    addr,
+		Key:        &keyCopy,
+		Value:      &valueCopy,
+	})
+}
+
+// CapturePrecompileStateWrite implements the vm.PrecompileLogger interface to
+// record storage slots written by stateful precompiles.
+func (t *callTracer) CapturePrecompileStateWrite(precompile common.Address, addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	keyCopy, prevCopy, valueCopy := key, prev, value
+	t.addPrecompileAccess(precompileAccess{
+		Precompile: precompile,
+		Type:       "write",
+		Address:    addr,
+		Key:        &keyCopy,
+		Prev:       &prevCopy,
+		Value:      &valueCopy,
+	})
+}
+
+// CapturePrecompileBalanceChange implements the vm.PrecompileLogger interface to
+// record balance changes made by stateful precompiles.
+func (t *callTracer) CapturePrecompileBalanceChange(precompile common.Address, addr common.Address, prev *big.Int, amount *big.Int) {
+	t.addPrecompileAccess(precompileAccess{
+		Precompile: precompile,
+		Type:       "balance",
+		Address:    addr,
+		Amount:     (*hexutil.Big)(new(big.Int).Set(amount)),
+	})
+}
+
+// CapturePrecompileLog implements the vm.PrecompileLogger interface to record
+// the logs emitted by stateful precompiles.
+func (t *callTracer) CapturePrecompileLog(precompile common.Address, topics []common.Hash, data []byte) {
+	if !t.config.WithLog || !t.inPrecompileFrame(precompile) {
+		return
+	}
+	log := callLog{Address: precompile, Topics: append([]common.Hash(nil), topics...), Data: common.CopyBytes(data)}
+	t.callstack[len(t.callstack)-1].Logs = append(t.callstack[len(t.callstack)-1].Logs, log)
+}
+
+// addPrecompileAccess records [access] in the current call frame.
+func (t *callTracer) addPrecompileAccess(access precompileAccess) {
+	if !t.config.WithPrecompileState || !t.inPrecompileFrame(access.Precompile) {
+		return
+	}
+	if access.Key != nil && access.Value != nil {
+		access.Decoded, _ = decodePrecompileSlot(access.Address, *access.Key, *access.Value)
+	}
+	frame := &t.callstack[len(t.callstack)-1]
+	frame.PrecompileState = append(frame.PrecompileState, access)
+}
+
+// inPrecompileFrame returns true if the current call frame is the execution
+// of [precompile]. This is not the case if the precompile was called from a
+// nested frame while only the top call is being traced.
+func (t *callTracer) inPrecompileFrame(precompile common.Address) bool {
+	if t.interrupt.Load() {
+		return false
+	}
+	to := t.callstack[len(t.callstack)-1].To
+	return to != nil && *to == precompile
+}
+
 func (t *callTracer) CaptureTxStart(gasLimit uint64) {
 	t.gasLimit = gasLimit
 }
@@ -0,0 +1,344 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/eth/tracers"
+	"github.com/shubhamdubey02/subnet-evm/params"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("gasProfilerTracer", newGasProfilerTracer, false)
+}
+
+const (
+	// gasProfilerIntrinsic is the flame graph frame and opcode class used for
+	// the intrinsic gas of the transaction.
+	gasProfilerIntrinsic = "intrinsic"
+	// gasProfilerPrecompile is the opcode class used for the gas consumed by
+	// precompiles, which do not execute opcodes.
+	gasProfilerPrecompile = "precompile"
+)
+
+// gasProfile is the result of the gasProfilerTracer.
+type gasProfile struct {
+	GasUsed      uint64                                             `json:"gasUsed"`
+	IntrinsicGas uint64                                             `json:"intrinsicGas"`
+	Refund       uint64                                             `json:"refund"`
+	Contracts    map[common.Address]*contractGasProfile             `json:"contracts"`
+	Opcodes      map[string]uint64                                  `json:"opcodes"`
+	Storage      map[common.Address]map[common.Hash]*slotGasProfile `json:"storage,omitempty"`
+	// FlameGraph holds the self gas of each call stack in the folded format
+	// consumed by flame graph tools: "frame;frame;frame gas".
+	FlameGraph []string `json:"flameGraph"`
+}
+
+// contractGasProfile is the gas used by the code of a single contract,
+// excluding the gas used by the contracts it calls.
+type contractGasProfile struct {
+	GasUsed   uint64                         `json:"gasUsed"`
+	Calls     uint64                         `json:"calls"`
+	Functions map[string]*functionGasProfile `json:"functions"`
+}
+
+// functionGasProfile is the gas used by calls to a single function selector.
+type functionGasProfile struct {
+	GasUsed uint64 `json:"gasUsed"`
+	Calls   uint64 `json:"calls"`
+}
+
+// slotGasProfile is the gas used accessing a single storage slot.
+type slotGasProfile struct {
+	SloadCold  uint64 `json:"sloadCold"`
+	SloadWarm  uint64 `json:"sloadWarm"`
+	SstoreCold uint64 `json:"sstoreCold"`
+	SstoreWarm uint64 `json:"sstoreWarm"`
+	GasUsed    uint64 `json:"gasUsed"`
+}
+
+// gasFrame is a call frame tracked by the gasProfilerTracer.
+type gasFrame struct {
+	address    common.Address
+	selector   string
+	stack      string // folded flame graph stack including this frame
+	childGas   uint64 // gas used by the direct children of this frame
+	hasOpcodes bool   // whether this frame executed any opcodes
+}
+
+// gasProfilerTracer is a native go tracer which attributes the gas used by a
+// transaction to contracts, function selectors, opcode classes and storage
+// slots. Blocks can be profiled with debug_traceBlock*, which returns one
+// profile per transaction.
+type gasProfilerTracer struct {
+	noopTracer
+	profile   gasProfile
+	frames    []*gasFrame
+	flame     map[string]uint64
+	gasLimit  uint64
+	startGas  uint64
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newGasProfilerTracer returns a native go tracer which profiles the gas used
+// by a tx, and implements vm.EVMLogger.
+func newGasProfilerTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &gasProfilerTracer{
+		profile: gasProfile{
+			Contracts: make(map[common.Address]*contractGasProfile),
+			Opcodes:   make(map[string]uint64),
+			Storage:   make(map[common.Address]map[common.Hash]*slotGasProfile),
+		},
+		flame: make(map[string]uint64),
+	}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface to record the gas limit of the tx.
+func (t *gasProfilerTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *gasProfilerTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.startGas = gas
+	t.pushFrame(to, input, create)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *gasProfilerTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.popFrame(gasUsed)
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *gasProfilerTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	// The cost of the call opcodes includes the gas forwarded to the callee,
+	// which is attributed to the callee instead. The call stipend is granted
+	// on top of the forwarded gas and was not charged to the caller.
+	switch typ {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		forwarded := gas
+		if (typ == vm.CALL || typ == vm.CALLCODE) && value != nil && value.Sign() != 0 {
+			forwarded -= params.CallStipend
+		}
+		class := opcodeClass(typ)
+		if t.profile.Opcodes[class] >= forwarded {
+			t.profile.Opcodes[class] -= forwarded
+		}
+	}
+	t.pushFrame(to, input, typ == vm.CREATE || typ == vm.CREATE2)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *gasProfilerTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.popFrame(gasUsed)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *gasProfilerTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	t.frames[len(t.frames)-1].hasOpcodes = true
+	t.profile.Opcodes[opcodeClass(op)] += cost
+
+	if op != vm.SLOAD && op != vm.SSTORE {
+		return
+	}
+	stackData := scope.Stack.Data()
+	if len(stackData) == 0 {
+		return
+	}
+	var (
+		addr = scope.Contract.Address()
+		slot = common.Hash(stackData[len(stackData)-1].Bytes32())
+	)
+	slots, ok := t.profile.Storage[addr]
+	if !ok {
+		slots = make(map[common.Hash]*slotGasProfile)
+		t.profile.Storage[addr] = slots
+	}
+	stats, ok := slots[slot]
+	if !ok {
+		stats = &slotGasProfile{}
+		slots[slot] = stats
+	}
+	stats.GasUsed += cost
+	cold := isColdStorageAccess(op, cost)
+	switch {
+	case op == vm.SLOAD && cold:
+		stats.SloadCold++
+	case op == vm.SLOAD:
+		stats.SloadWarm++
+	case cold:
+		stats.SstoreCold++
+	default:
+		stats.SstoreWarm++
+	}
+}
+
+// CaptureTxEnd implements the EVMLogger interface to finalize the profile.
+func (t *gasProfilerTracer) CaptureTxEnd(restGas uint64) {
+	t.profile.GasUsed = t.gasLimit - restGas
+	t.profile.IntrinsicGas = t.gasLimit - t.startGas
+	t.profile.Opcodes[gasProfilerIntrinsic] += t.profile.IntrinsicGas
+	t.flame[gasProfilerIntrinsic] += t.profile.IntrinsicGas
+
+	var executed uint64
+	for _, gas := range t.flame {
+		executed += gas
+	}
+	if executed > t.profile.GasUsed {
+		t.profile.Refund = executed - t.profile.GasUsed
+	}
+}
+
+// GetResult returns the json-encoded gas profile, and any error arising from
+// the encoding or forceful termination (via `Stop`).
+func (t *gasProfilerTracer) GetResult() (json.RawMessage, error) {
+	t.profile.FlameGraph = make([]string, 0, len(t.flame))
+	for stack, gas := range t.flame {
+		if gas == 0 {
+			continue
+		}
+		t.profile.FlameGraph = append(t.profile.FlameGraph, fmt.Sprintf("%s %d", stack, gas))
+	}
+	sort.Strings(t.profile.FlameGraph)
+
+	res, err := json.Marshal(t.profile)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *gasProfilerTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// pushFrame enters the call frame of [addr] called with [input].
+func (t *gasProfilerTracer) pushFrame(addr common.Address, input []byte, create bool) {
+	frame := &gasFrame{
+		address:  addr,
+		selector: functionSelector(input, create),
+	}
+	frame.stack = fmt.Sprintf("%s:%s", addr.Hex(), frame.selector)
+	if len(t.frames) > 0 {
+		frame.stack = t.frames[len(t.frames)-1].stack + ";" + frame.stack
+	}
+	t.frames = append(t.frames, frame)
+
+	contract, ok := t.profile.Contracts[addr]
+	if !ok {
+		contract = &contractGasProfile{Functions: make(map[string]*functionGasProfile)}
+		t.profile.Contracts[addr] = contract
+	}
+	contract.Calls++
+	function, ok := contract.Functions[frame.selector]
+	if !ok {
+		function = &functionGasProfile{}
+		contract.Functions[frame.selector] = function
+	}
+	function.Calls++
+}
+
+// popFrame exits the current call frame, which used [gasUsed] including the
+// gas used by its children, and attributes its self gas.
+func (t *gasProfilerTracer) popFrame(gasUsed uint64) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	if len(t.frames) > 0 {
+		t.frames[len(t.frames)-1].childGas += gasUsed
+	}
+
+	var self uint64
+	if gasUsed > frame.childGas {
+		self = gasUsed - frame.childGas
+	}
+	// Precompiles consume gas without executing any opcodes.
+	if !frame.hasOpcodes {
+		t.profile.Opcodes[gasProfilerPrecompile] += self
+	}
+	contract := t.profile.Contracts[frame.address]
+	contract.GasUsed += self
+	contract.Functions[frame.selector].GasUsed += self
+	t.flame[frame.stack] += self
+}
+
+// functionSelector returns the hex encoded 4-byte selector of [input].
+func functionSelector(input []byte, create bool) string {
+	switch {
+	case create:
+		return "constructor"
+	case len(input) < 4:
+		return "fallback"
+	default:
+		return bytesToHex(input[:4])
+	}
+}
+
+// isColdStorageAccess returns true if the SLOAD or SSTORE costing [cost]
+// accessed a slot that was not yet in the access list (EIP-2929).
+func isColdStorageAccess(op vm.OpCode, cost uint64) bool {
+	if op == vm.SLOAD {
+		return cost == params.ColdSloadCostEIP2929
+	}
+	if cost < params.ColdSloadCostEIP2929 {
+		return false
+	}
+	switch cost - params.ColdSloadCostEIP2929 {
+	case params.WarmStorageReadCostEIP2929, params.SstoreResetGasEIP2200 - params.ColdSloadCostEIP2929, params.SstoreSetGasEIP2200:
+		return true
+	default:
+		return false
+	}
+}
+
+// opcodeClass returns the class [op] is aggregated under.
+func opcodeClass(op vm.OpCode) string {
+	switch {
+	case op == vm.SLOAD || op == vm.SSTORE || op == vm.TLOAD || op == vm.TSTORE:
+		return "storage"
+	case op == vm.CALL || op == vm.CALLCODE || op == vm.DELEGATECALL || op == vm.STATICCALL ||
+		op == vm.CREATE || op == vm.CREATE2 || op == vm.SELFDESTRUCT:
+		return "call"
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return "log"
+	case op == vm.KECCAK256:
+		return "hash"
+	case op == vm.MLOAD || op == vm.MSTORE || op == vm.MSTORE8 || op == vm.MSIZE || op == vm.MCOPY ||
+		op == vm.CALLDATACOPY || op == vm.CODECOPY || op == vm.EXTCODECOPY || op == vm.RETURNDATACOPY:
+		return "memory"
+	case op == vm.BALANCE || op == vm.EXTCODESIZE || op == vm.EXTCODEHASH || op == vm.SELFBALANCE:
+		return "account"
+	case op.IsPush() || (op >= vm.DUP1 && op <= vm.SWAP16) || op == vm.POP:
+		return "stack"
+	case op == vm.JUMP || op == vm.JUMPI || op == vm.JUMPDEST || op == vm.PC || op == vm.STOP ||
+		op == vm.RETURN || op == vm.REVERT || op == vm.INVALID || op == vm.GAS:
+		return "control"
+	case op < vm.KECCAK256:
+		return "arithmetic"
+	default:
+		return "environment"
+	}
+}
@@ -15,14 +15,16 @@ var _ = (*accountMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (a account) MarshalJSON() ([]byte, error) {
 	type account struct {
-		Balance *hexutil.Big                `json:"balance,omitempty"`
-		Code    hexutil.Bytes               `json:"code,omitempty"`
-		Nonce   uint64                      `json:"nonce,omitempty"`
-		Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+		Balance        *hexutil.Big                `json:"balance,omitempty"`
+		Code           hexutil.Bytes               `json:"code,omitempty"`
+		DecodedStorage map[common.Hash]string      `json:"decodedStorage,omitempty"`
+		Nonce          uint64                      `json:"nonce,omitempty"`
+		Storage        map[common.Hash]common.Hash `json:"storage,omitempty"`
 	}
 	var enc account
 	enc.Balance = (*hexutil.Big)(a.Balance)
 	enc.Code = a.Code
+	enc.DecodedStorage = a.DecodedStorage
 	enc.Nonce = a.Nonce
 	enc.Storage = a.Storage
 	return json.Marshal(&enc)
@@ -31,10 +33,11 @@ func (a account) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (a *account) UnmarshalJSON(input []byte) error {
 	type account struct {
-		Balance *hexutil.Big                `json:"balance,omitempty"`
-		Code    *hexutil.Bytes              `json:"code,omitempty"`
-		Nonce   *uint64                     `json:"nonce,omitempty"`
-		Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+		Balance        *hexutil.Big                `json:"balance,omitempty"`
+		Code           *hexutil.Bytes              `json:"code,omitempty"`
+		DecodedStorage map[common.Hash]string      `json:"decodedStorage,omitempty"`
+		Nonce          *uint64                     `json:"nonce,omitempty"`
+		Storage        map[common.Hash]common.Hash `json:"storage,omitempty"`
 	}
 	var dec account
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -46,6 +49,9 @@ func (a *account) UnmarshalJSON(input []byte) error {
 	if dec.Code != nil {
 		a.Code = *dec.Code
 	}
+	if dec.DecodedStorage != nil {
+		a.DecodedStorage = dec.DecodedStorage
+	}
 	if dec.Nonce != nil {
 		a.Nonce = *dec.Nonce
 	}
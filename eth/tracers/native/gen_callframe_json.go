@@ -6,9 +6,9 @@ import (
 	"encoding/json"
 	"math/big"
 
-	"github.com/shubhamdubey02/subnet-evm/core/vm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
 )
 
 var _ = (*callFrameMarshaling)(nil)
@@ -16,19 +16,20 @@ var _ = (*callFrameMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (c callFrame) MarshalJSON() ([]byte, error) {
 	type callFrame0 struct {
-		Type         vm.OpCode       `json:"-"`
-		From         common.Address  `json:"from"`
-		Gas          hexutil.Uint64  `json:"gas"`
-		GasUsed      hexutil.Uint64  `json:"gasUsed"`
-		To           *common.Address `json:"to,omitempty" rlp:"optional"`
-		Input        hexutil.Bytes   `json:"input" rlp:"optional"`
-		Output       hexutil.Bytes   `json:"output,omitempty" rlp:"optional"`
-		Error        string          `json:"error,omitempty" rlp:"optional"`
-		RevertReason string          `json:"revertReason,omitempty"`
-		Calls        []callFrame     `json:"calls,omitempty" rlp:"optional"`
-		Logs         []callLog       `json:"logs,omitempty" rlp:"optional"`
-		Value        *hexutil.Big    `json:"value,omitempty" rlp:"optional"`
-		TypeString   string          `json:"type"`
+		Type            vm.OpCode          `json:"-"`
+		From            common.Address     `json:"from"`
+		Gas             hexutil.Uint64     `json:"gas"`
+		GasUsed         hexutil.Uint64     `json:"gasUsed"`
+		To              *common.Address    `json:"to,omitempty" rlp:"optional"`
+		Input           hexutil.Bytes      `json:"input" rlp:"optional"`
+		Output          hexutil.Bytes      `json:"output,omitempty" rlp:"optional"`
+		Error           string             `json:"error,omitempty" rlp:"optional"`
+		RevertReason    string             `json:"revertReason,omitempty"`
+		Calls           []callFrame        `json:"calls,omitempty" rlp:"optional"`
+		Logs            []callLog          `json:"logs,omitempty" rlp:"optional"`
+		PrecompileState []precompileAccess `json:"precompileState,omitempty" rlp:"-"`
+		Value           *hexutil.Big       `json:"value,omitempty" rlp:"optional"`
+		TypeString      string             `json:"type"`
 	}
 	var enc callFrame0
 	enc.Type = c.Type
@@ -42,6 +43,7 @@ func (c callFrame) MarshalJSON() ([]byte, error) {
 	enc.RevertReason = c.RevertReason
 	enc.Calls = c.Calls
 	enc.Logs = c.Logs
+	enc.PrecompileState = c.PrecompileState
 	enc.Value = (*hexutil.Big)(c.Value)
 	enc.TypeString = c.TypeString()
 	return json.Marshal(&enc)
@@ -50,18 +52,19 @@ func (c callFrame) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (c *callFrame) UnmarshalJSON(input []byte) error {
 	type callFrame0 struct {
-		Type         *vm.OpCode      `json:"-"`
-		From         *common.Address `json:"from"`
-		Gas          *hexutil.Uint64 `json:"gas"`
-		GasUsed      *hexutil.Uint64 `json:"gasUsed"`
-		To           *common.Address `json:"to,omitempty" rlp:"optional"`
-		Input        *hexutil.Bytes  `json:"input" rlp:"optional"`
-		Output       *hexutil.Bytes  `json:"output,omitempty" rlp:"optional"`
-		Error        *string         `json:"error,omitempty" rlp:"optional"`
-		RevertReason *string         `json:"revertReason,omitempty"`
-		Calls        []callFrame     `json:"calls,omitempty" rlp:"optional"`
-		Logs         []callLog       `json:"logs,omitempty" rlp:"optional"`
-		Value        *hexutil.Big    `json:"value,omitempty" rlp:"optional"`
+		Type            *vm.OpCode         `json:"-"`
+		From            *common.Address    `json:"from"`
+		Gas             *hexutil.Uint64    `json:"gas"`
+		GasUsed         *hexutil.Uint64    `json:"gasUsed"`
+		To              *common.Address    `json:"to,omitempty" rlp:"optional"`
+		Input           *hexutil.Bytes     `json:"input" rlp:"optional"`
+		Output          *hexutil.Bytes     `json:"output,omitempty" rlp:"optional"`
+		Error           *string            `json:"error,omitempty" rlp:"optional"`
+		RevertReason    *string            `json:"revertReason,omitempty"`
+		Calls           []callFrame        `json:"calls,omitempty" rlp:"optional"`
+		Logs            []callLog          `json:"logs,omitempty" rlp:"optional"`
+		PrecompileState []precompileAccess `json:"precompileState,omitempty" rlp:"-"`
+		Value           *hexutil.Big       `json:"value,omitempty" rlp:"optional"`
 	}
 	var dec callFrame0
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -100,6 +103,9 @@ func (c *callFrame) UnmarshalJSON(input []byte) error {
 	if dec.Logs != nil {
 		c.Logs = dec.Logs
 	}
+	if dec.PrecompileState != nil {
+		c.PrecompileState = dec.PrecompileState
+	}
 	if dec.Value != nil {
 		c.Value = (*big.Int)(dec.Value)
 	}
@@ -123,6 +123,42 @@ func (t *muxTracer) CaptureTxEnd(restGas uint64) {
 	}
 }
 
+// CapturePrecompileStateRead forwards the event to the tracers implementing vm.PrecompileLogger.
+func (t *muxTracer) CapturePrecompileStateRead(precompile common.Address, addr common.Address, key common.Hash, value common.Hash) {
+	for _, t := range t.tracers {
+		if l, ok := t.(vm.PrecompileLogger); ok {
+			l.CapturePrecompileStateRead(precompile, addr, key, value)
+		}
+	}
+}
+
+// CapturePrecompileStateWrite forwards the event to the tracers implementing vm.PrecompileLogger.
+func (t *muxTracer) CapturePrecompileStateWrite(precompile common.Address, addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	for _, t := range t.tracers {
+		if l, ok := t.(vm.PrecompileLogger); ok {
+			l.CapturePrecompileStateWrite(precompile, addr, key, prev, value)
+		}
+	}
+}
+
+// CapturePrecompileBalanceChange forwards the event to the tracers implementing vm.PrecompileLogger.
+func (t *muxTracer) CapturePrecompileBalanceChange(precompile common.Address, addr common.Address, prev *big.Int, amount *big.Int) {
+	for _, t := range t.tracers {
+		if l, ok := t.(vm.PrecompileLogger); ok {
+			l.CapturePrecompileBalanceChange(precompile, addr, prev, amount)
+		}
+	}
+}
+
+// CapturePrecompileLog forwards the event to the tracers implementing vm.PrecompileLogger.
+func (t *muxTracer) CapturePrecompileLog(precompile common.Address, topics []common.Hash, data []byte) {
+	for _, t := range t.tracers {
+		if l, ok := t.(vm.PrecompileLogger); ok {
+			l.CapturePrecompileLog(precompile, topics, data)
+		}
+	}
+}
+
 // GetResult returns an empty json object.
 func (t *muxTracer) GetResult() (json.RawMessage, error) {
 	resObject := make(map[string]json.RawMessage)
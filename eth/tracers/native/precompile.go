@@ -0,0 +1,106 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package native
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+)
+
+// allowListPrecompiles are the built-in precompiles that store allow list
+// roles keyed by the hash of the address.
+var allowListPrecompiles = map[common.Address]bool{
+	deployerallowlist.ContractAddress: true,
+	txallowlist.ContractAddress:       true,
+	nativeminter.ContractAddress:      true,
+	feemanager.ContractAddress:        true,
+	rewardmanager.ContractAddress:     true,
+}
+
+// Storage keys used by the built-in precompiles. These mirror the layouts in
+// precompile/contracts/feemanager and precompile/contracts/rewardmanager.
+var (
+	feeConfigSlotNames = map[common.Hash]string{
+		{1}: "gasLimit",
+		{2}: "targetBlockRate",
+		{3}: "minBaseFee",
+		{4}: "targetGas",
+		{5}: "baseFeeChangeDenominator",
+		{6}: "minBlockGasCost",
+		{7}: "maxBlockGasCost",
+		{8}: "blockGasCostStep",
+	}
+	feeConfigLastChangedAtKey = common.Hash{'l', 'c', 'a'}
+	rewardAddressStorageKey   = common.Hash{'r', 'a', 's', 'k'}
+)
+
+// precompileAccess is a single state access performed by a stateful precompile.
+type precompileAccess struct {
+	Precompile common.Address `json:"precompile"`
+	Type       string         `json:"type"`
+	Address    common.Address `json:"address"`
+	Key        *common.Hash   `json:"key,omitempty"`
+	Prev       *common.Hash   `json:"prev,omitempty"`
+	Value      *common.Hash   `json:"value,omitempty"`
+	Amount     *hexutil.Big   `json:"amount,omitempty"`
+	Decoded    string         `json:"decoded,omitempty"`
+}
+
+// decodePrecompileSlot returns a human readable description of the value
+// [value] stored at [key] by the built-in precompile at [addr]. It returns
+// false if the slot is not known.
+func decodePrecompileSlot(addr common.Address, key common.Hash, value common.Hash) (string, bool) {
+	switch addr {
+	case feemanager.ContractAddress:
+		if name, ok := feeConfigSlotNames[key]; ok {
+			return fmt.Sprintf("%s: %s", name, value.Big()), true
+		}
+		if key == feeConfigLastChangedAtKey {
+			return fmt.Sprintf("feeConfigLastChangedAt: %s", value.Big()), true
+		}
+	case rewardmanager.ContractAddress:
+		if key == rewardAddressStorageKey {
+			return fmt.Sprintf("rewardAddress: %s", common.BytesToAddress(value.Bytes())), true
+		}
+	}
+	if allowListPrecompiles[addr] && isAddressKey(key) {
+		return fmt.Sprintf("role[%s]: %s", common.BytesToAddress(key.Bytes()), allowlist.Role(value)), true
+	}
+	return "", false
+}
+
+// decodePrecompileStorage returns the human readable descriptions of the known
+// precompile slots in [storage], or nil if [addr] has none.
+func decodePrecompileStorage(addr common.Address, storage map[common.Hash]common.Hash) map[common.Hash]string {
+	var decoded map[common.Hash]string
+	for key, value := range storage {
+		desc, ok := decodePrecompileSlot(addr, key, value)
+		if !ok {
+			continue
+		}
+		if decoded == nil {
+			decoded = make(map[common.Hash]string)
+		}
+		decoded[key] = desc
+	}
+	return decoded
+}
+
+// isAddressKey returns true if [key] is an address left padded to 32 bytes.
+func isAddressKey(key common.Hash) bool {
+	for _, b := range key[:common.HashLength-common.AddressLength] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
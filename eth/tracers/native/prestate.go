@@ -49,10 +49,13 @@ func init() {
 type state = map[common.Address]*account
 
 type account struct {
-	Balance *big.Int                    `json:"balance,omitempty"`
-	Code    []byte                      `json:"code,omitempty"`
-	Nonce   uint64                      `json:"nonce,omitempty"`
-	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Balance *big.Int `json:"balance,omitempty"`
+	Code    []byte   `json:"code,omitempty"`
+	// DecodedStorage contains human readable descriptions of the known storage
+	// slots of built-in stateful precompiles.
+	DecodedStorage map[common.Hash]string      `json:"decodedStorage,omitempty"`
+	Nonce          uint64                      `json:"nonce,omitempty"`
+	Storage        map[common.Hash]common.Hash `json:"storage,omitempty"`
 }
 
 func (a *account) exists() bool {
@@ -80,7 +83,8 @@ type prestateTracer struct {
 }
 
 type prestateTracerConfig struct {
-	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+	DiffMode          bool `json:"diffMode"`          // If true, this tracer will return state modifications
+	DecodePrecompiles bool `json:"decodePrecompiles"` // If true, this tracer will decode the known storage slots of stateful precompiles
 }
 
 func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage) (tracers.Tracer, error) {
@@ -256,11 +260,46 @@ func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
 	}
 }
 
+// CapturePrecompileStateRead implements the vm.PrecompileLogger interface to
+// record storage slots read by stateful precompiles.
+func (t *prestateTracer) CapturePrecompileStateRead(precompile common.Address, addr common.Address, key common.Hash, value common.Hash) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.lookupStorage(addr, key)
+}
+
+// CapturePrecompileStateWrite implements the vm.PrecompileLogger interface to
+// record storage slots written by stateful precompiles.
+func (t *prestateTracer) CapturePrecompileStateWrite(precompile common.Address, addr common.Address, key common.Hash, prev common.Hash, value common.Hash) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.lookupStorage(addr, key)
+}
+
+// CapturePrecompileBalanceChange implements the vm.PrecompileLogger interface to
+// record accounts whose balance is modified by stateful precompiles.
+func (t *prestateTracer) CapturePrecompileBalanceChange(precompile common.Address, addr common.Address, prev *big.Int, amount *big.Int) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.lookupAccount(addr)
+}
+
+// CapturePrecompileLog implements the vm.PrecompileLogger interface.
+func (t *prestateTracer) CapturePrecompileLog(precompile common.Address, topics []common.Hash, data []byte) {
+}
+
 // GetResult returns the json-encoded nested list of call traces, and any
 // error arising from the encoding or forceful termination (via `Stop`).
 func (t *prestateTracer) GetResult() (json.RawMessage, error) {
 	var res []byte
 	var err error
+	if t.config.DecodePrecompiles {
+		decodeState(t.pre)
+		decodeState(t.post)
+	}
 	if t.config.DiffMode {
 		res, err = json.Marshal(struct {
 			Post state `json:"post"`
@@ -281,6 +320,14 @@ func (t *prestateTracer) Stop(err error) {
 	t.interrupt.Store(true)
 }
 
+// decodeState populates the decoded storage of the built-in stateful
+// precompiles in [s].
+func decodeState(s state) {
+	for addr, acc := range s {
+		acc.DecodedStorage = decodePrecompileStorage(addr, acc.Storage)
+	}
+}
+
 // lookupAccount fetches details of an account and adds it to the prestate
 // if it doesn't exist there.
 func (t *prestateTracer) lookupAccount(addr common.Address) {
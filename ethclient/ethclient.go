@@ -100,6 +100,7 @@ type Client interface {
 	SubscribeFilterLogs(context.Context, interfaces.FilterQuery, chan<- types.Log) (interfaces.Subscription, error)
 	AcceptedCodeAt(context.Context, common.Address) ([]byte, error)
 	AcceptedNonceAt(context.Context, common.Address) (uint64, error)
+	PendingNonceAt(context.Context, common.Address) (uint64, error)
 	AcceptedCallContract(context.Context, interfaces.CallMsg) ([]byte, error)
 	CallContract(context.Context, interfaces.CallMsg, *big.Int) ([]byte, error)
 	CallContractAtHash(ctx context.Context, msg interfaces.CallMsg, blockHash common.Hash) ([]byte, error)
@@ -570,6 +571,12 @@ func (ec *client) AcceptedNonceAt(ctx context.Context, account common.Address) (
 	return ec.NonceAt(ctx, account, nil)
 }
 
+// PendingNonceAt returns the account nonce of the given account in the pending state.
+// This includes the transactions of the account in the transaction pool.
+func (ec *client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return ec.NonceAt(ctx, account, big.NewInt(int64(rpc.PendingBlockNumber)))
+}
+
 // AcceptedCallContract executes a message call transaction in the accepted
 // state.
 func (ec *client) AcceptedCallContract(ctx context.Context, msg interfaces.CallMsg) ([]byte, error) {
@@ -0,0 +1,27 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+)
+
+// ExportChain exports the blocks from [first] to [last] to [file] on the node.
+// The bounds can be nil, in which case the chain is exported from genesis and
+// up to the latest block.
+//
+// The admin APIs of the VM, such as profiling and log levels, are served on a
+// separate endpoint by the Client of the plugin/evm package.
+func (ec *Client) ExportChain(ctx context.Context, file string, first *uint64, last *uint64) (bool, error) {
+	var result bool
+	err := ec.c.CallContext(ctx, &result, "admin_exportChain", file, first, last)
+	return result, err
+}
+
+// ImportChain imports the blocks exported to [file] on the node.
+func (ec *Client) ImportChain(ctx context.Context, file string) (bool, error) {
+	var result bool
+	err := ec.c.CallContext(ctx, &result, "admin_importChain", file)
+	return result, err
+}
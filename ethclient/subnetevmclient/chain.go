@@ -0,0 +1,195 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/ethclient"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/modules"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+
+	// Force-load the precompiles so that their configs can be decoded.
+	_ "github.com/shubhamdubey02/subnet-evm/precompile/registry"
+)
+
+// ChainConfig returns the chain config of the node, including its upgrades.
+func (ec *Client) ChainConfig(ctx context.Context) (*params.ChainConfigWithUpgradesJSON, error) {
+	return ec.eth.ChainConfig(ctx)
+}
+
+// ActivePrecompilesAt returns the configs of the precompiles enabled at the
+// given block timestamp, keyed by precompile config key. The timestamp can be
+// nil, in which case the timestamp of the latest block is used.
+func (ec *Client) ActivePrecompilesAt(ctx context.Context, timestamp *uint64) (params.Precompiles, error) {
+	var result params.Precompiles
+	if err := ec.c.CallContext(ctx, &result, "eth_getActivePrecompilesAt", timestamp); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BaseFee returns the base fee of the next block.
+func (ec *Client) BaseFee(ctx context.Context) (*big.Int, error) {
+	var result hexutil.Big
+	if err := ec.c.CallContext(ctx, &result, "eth_baseFee"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+// FeeConfig returns the fee config in effect after the given block, and the
+// number of the block it last changed at. The block number can be nil, in
+// which case the latest block is used.
+func (ec *Client) FeeConfig(ctx context.Context, blockNumber *big.Int) (*commontype.FeeConfig, *big.Int, error) {
+	var result struct {
+		FeeConfig     commontype.FeeConfig `json:"feeConfig"`
+		LastChangedAt *big.Int             `json:"lastChangedAt,omitempty"`
+	}
+	if err := ec.c.CallContext(ctx, &result, "eth_feeConfig", ethclient.ToBlockNumArg(blockNumber)); err != nil {
+		return nil, nil, err
+	}
+	return &result.FeeConfig, result.LastChangedAt, nil
+}
+
+// PriceOptions are the gas prices and tips suggested for a transaction.
+type PriceOptions struct {
+	BaseFee *big.Int
+	GasTip  *big.Int
+	// GasFeeCap is a fee cap covering the doubling of the base fee on top of
+	// the suggested tip.
+	GasFeeCap *big.Int
+}
+
+// SuggestPriceOptions returns the base fee of the next block along with the
+// suggested tip and fee cap for a dynamic fee transaction.
+func (ec *Client) SuggestPriceOptions(ctx context.Context) (*PriceOptions, error) {
+	baseFee, err := ec.BaseFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := ec.eth.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, common.Big2), tip)
+	return &PriceOptions{BaseFee: baseFee, GasTip: tip, GasFeeCap: feeCap}, nil
+}
+
+// Upgrade is a network, precompile or state upgrade of the chain.
+type Upgrade struct {
+	Kind      string
+	Name      string
+	Timestamp uint64
+	Disable   bool
+	// Config is the precompile config of precompile upgrades, or the encoded
+	// state upgrade of state upgrades.
+	Config json.RawMessage
+	// Activated is true if the upgrade is active at the latest block.
+	Activated bool
+	// ActivationBlock is the number of the first block the upgrade is active
+	// at, or nil if it is not activated yet.
+	ActivationBlock *uint64
+}
+
+// UpgradeTimeline returns the network, precompile and state upgrades of the
+// chain ordered by activation timestamp.
+func (ec *Client) UpgradeTimeline(ctx context.Context) ([]Upgrade, error) {
+	var result []struct {
+		Kind            string          `json:"kind"`
+		Name            string          `json:"name"`
+		Timestamp       uint64          `json:"timestamp"`
+		Disable         bool            `json:"disable"`
+		Config          json.RawMessage `json:"config"`
+		Activated       bool            `json:"activated"`
+		ActivationBlock *hexutil.Uint64 `json:"activationBlock"`
+	}
+	if err := ec.c.CallContext(ctx, &result, "eth_getUpgradeTimeline"); err != nil {
+		return nil, err
+	}
+	upgrades := make([]Upgrade, 0, len(result))
+	for _, upgrade := range result {
+		upgrades = append(upgrades, Upgrade{
+			Kind:            upgrade.Kind,
+			Name:            upgrade.Name,
+			Timestamp:       upgrade.Timestamp,
+			Disable:         upgrade.Disable,
+			Config:          upgrade.Config,
+			Activated:       upgrade.Activated,
+			ActivationBlock: (*uint64)(upgrade.ActivationBlock),
+		})
+	}
+	return upgrades, nil
+}
+
+// PrecompileState is the config and the decoded state of an enabled precompile.
+type PrecompileState struct {
+	Address common.Address
+	Config  precompileconfig.Config
+	// Roles counts the addresses holding each role in the allow list of the
+	// precompile, if it has one.
+	Roles *allowlist.RoleCounts
+	// FeeConfig and FeeConfigLastChangedAt are set for the FeeManager precompile.
+	FeeConfig              *commontype.FeeConfig
+	FeeConfigLastChangedAt *big.Int
+	// RewardAddress and AllowFeeRecipients are set for the RewardManager
+	// precompile. RewardAddress is nil if fee recipients are allowed.
+	RewardAddress      *common.Address
+	AllowFeeRecipients *bool
+	// QuorumNumerator is set for the warp precompile.
+	QuorumNumerator *uint64
+}
+
+// PrecompileStateAt returns the configs and the decoded state of the
+// precompiles enabled at the given block, keyed by precompile config key. The
+// block number can be nil, in which case the latest block is used.
+func (ec *Client) PrecompileStateAt(ctx context.Context, blockNumber *big.Int) (map[string]*PrecompileState, error) {
+	var result map[string]struct {
+		Address   common.Address        `json:"address"`
+		Config    json.RawMessage       `json:"config"`
+		Roles     *allowlist.RoleCounts `json:"roles"`
+		FeeConfig *struct {
+			FeeConfig     commontype.FeeConfig `json:"feeConfig"`
+			LastChangedAt *big.Int             `json:"lastChangedAt"`
+		} `json:"feeConfig"`
+		RewardAddress      *common.Address `json:"rewardAddress"`
+		AllowFeeRecipients *bool           `json:"allowFeeRecipients"`
+		QuorumNumerator    *hexutil.Uint64 `json:"quorumNumerator"`
+	}
+	if err := ec.c.CallContext(ctx, &result, "eth_getPrecompileStateAt", ethclient.ToBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	states := make(map[string]*PrecompileState, len(result))
+	for key, state := range result {
+		module, ok := modules.GetPrecompileModule(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown precompile config: %s", key)
+		}
+		config := module.MakeConfig()
+		if err := json.Unmarshal(state.Config, config); err != nil {
+			return nil, fmt.Errorf("failed to decode config of %s: %w", key, err)
+		}
+		states[key] = &PrecompileState{
+			Address:            state.Address,
+			Config:             config,
+			Roles:              state.Roles,
+			RewardAddress:      state.RewardAddress,
+			AllowFeeRecipients: state.AllowFeeRecipients,
+			QuorumNumerator:    (*uint64)(state.QuorumNumerator),
+		}
+		if state.FeeConfig != nil {
+			states[key].FeeConfig = &state.FeeConfig.FeeConfig
+			states[key].FeeConfigLastChangedAt = state.FeeConfig.LastChangedAt
+		}
+	}
+	return states, nil
+}
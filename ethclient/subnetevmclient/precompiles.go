@@ -0,0 +1,206 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi/bind"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/interfaces"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+)
+
+var ErrTransactionFailed = errors.New("transaction failed")
+
+// Transact sends a dynamic fee transaction from the account of [key] calling
+// [to] with [value] and [data], and waits until it is accepted. It returns
+// the receipt of the transaction, along with [ErrTransactionFailed] if the
+// transaction reverted.
+func (ec *Client) Transact(ctx context.Context, key *ecdsa.PrivateKey, to common.Address, value *big.Int, data []byte) (*types.Receipt, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID, err := ec.eth.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	nonce, err := ec.eth.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce of %s: %w", from, err)
+	}
+	prices, err := ec.SuggestPriceOptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas prices: %w", err)
+	}
+	gas, err := ec.eth.EstimateGas(ctx, interfaces.CallMsg{
+		From:      from,
+		To:        &to,
+		GasFeeCap: prices.GasFeeCap,
+		GasTipCap: prices.GasTip,
+		Value:     value,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: prices.GasTip,
+		GasFeeCap: prices.GasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := ec.eth.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	receipt, err := bind.WaitMined(ctx, ec.eth, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for transaction %s: %w", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionFailed, tx.Hash())
+	}
+	return receipt, nil
+}
+
+// callPrecompile calls [precompile] with [data] at the given block. The block
+// number can be nil, in which case the latest block is used.
+func (ec *Client) callPrecompile(ctx context.Context, precompile common.Address, data []byte, blockNumber *big.Int) ([]byte, error) {
+	return ec.eth.CallContract(ctx, interfaces.CallMsg{To: &precompile, Data: data}, blockNumber)
+}
+
+// AllowListRole returns the role of [address] in the allow list of
+// [precompile] at the given block. The block number can be nil, in which case
+// the latest block is used.
+func (ec *Client) AllowListRole(ctx context.Context, precompile common.Address, address common.Address, blockNumber *big.Int) (allowlist.Role, error) {
+	data, err := allowlist.PackReadAllowList(address)
+	if err != nil {
+		return allowlist.Role{}, err
+	}
+	output, err := ec.callPrecompile(ctx, precompile, data, blockNumber)
+	if err != nil {
+		return allowlist.Role{}, err
+	}
+	return allowlist.FromBig(new(big.Int).SetBytes(output))
+}
+
+// SetAllowListRole sets the role of [address] in the allow list of
+// [precompile] to [role], from the account of [key].
+func (ec *Client) SetAllowListRole(ctx context.Context, key *ecdsa.PrivateKey, precompile common.Address, address common.Address, role allowlist.Role) (*types.Receipt, error) {
+	data, err := allowlist.PackModifyAllowList(address, role)
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, precompile, nil, data)
+}
+
+// FeeManagerConfig returns the fee config stored by the FeeManager precompile
+// at the given block. The block number can be nil, in which case the latest
+// block is used.
+func (ec *Client) FeeManagerConfig(ctx context.Context, blockNumber *big.Int) (commontype.FeeConfig, error) {
+	data, err := feemanager.PackGetFeeConfig()
+	if err != nil {
+		return commontype.FeeConfig{}, err
+	}
+	output, err := ec.callPrecompile(ctx, feemanager.ContractAddress, data, blockNumber)
+	if err != nil {
+		return commontype.FeeConfig{}, err
+	}
+	return feemanager.UnpackGetFeeConfigOutput(output, false)
+}
+
+// SetFeeManagerConfig sets the fee config of the chain to [feeConfig] with the
+// FeeManager precompile, from the account of [key].
+func (ec *Client) SetFeeManagerConfig(ctx context.Context, key *ecdsa.PrivateKey, feeConfig commontype.FeeConfig) (*types.Receipt, error) {
+	data, err := feemanager.PackSetFeeConfig(feeConfig)
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, feemanager.ContractAddress, nil, data)
+}
+
+// MintNativeCoin mints [amount] of the native coin to [to] with the
+// NativeMinter precompile, from the account of [key].
+func (ec *Client) MintNativeCoin(ctx context.Context, key *ecdsa.PrivateKey, to common.Address, amount *big.Int) (*types.Receipt, error) {
+	data, err := nativeminter.PackMintNativeCoin(to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, nativeminter.ContractAddress, nil, data)
+}
+
+// RewardAddress returns the reward address stored by the RewardManager
+// precompile at the given block. The block number can be nil, in which case
+// the latest block is used.
+func (ec *Client) RewardAddress(ctx context.Context, blockNumber *big.Int) (common.Address, error) {
+	data, err := rewardmanager.PackCurrentRewardAddress()
+	if err != nil {
+		return common.Address{}, err
+	}
+	output, err := ec.callPrecompile(ctx, rewardmanager.ContractAddress, data, blockNumber)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(output), nil
+}
+
+// FeeRecipientsAllowed returns true if block producers may set the fee
+// recipient of their blocks with the RewardManager precompile at the given
+// block. The block number can be nil, in which case the latest block is used.
+func (ec *Client) FeeRecipientsAllowed(ctx context.Context, blockNumber *big.Int) (bool, error) {
+	data, err := rewardmanager.PackAreFeeRecipientsAllowed()
+	if err != nil {
+		return false, err
+	}
+	output, err := ec.callPrecompile(ctx, rewardmanager.ContractAddress, data, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	return new(big.Int).SetBytes(output).Sign() != 0, nil
+}
+
+// SetRewardAddress sets the address receiving the fees to [address] with the
+// RewardManager precompile, from the account of [key].
+func (ec *Client) SetRewardAddress(ctx context.Context, key *ecdsa.PrivateKey, address common.Address) (*types.Receipt, error) {
+	data, err := rewardmanager.PackSetRewardAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, rewardmanager.ContractAddress, nil, data)
+}
+
+// AllowFeeRecipients lets block producers set the fee recipient of their
+// blocks with the RewardManager precompile, from the account of [key].
+func (ec *Client) AllowFeeRecipients(ctx context.Context, key *ecdsa.PrivateKey) (*types.Receipt, error) {
+	data, err := rewardmanager.PackAllowFeeRecipients()
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, rewardmanager.ContractAddress, nil, data)
+}
+
+// DisableRewards burns the fees with the RewardManager precompile, from the
+// account of [key].
+func (ec *Client) DisableRewards(ctx context.Context, key *ecdsa.PrivateKey) (*types.Receipt, error) {
+	data, err := rewardmanager.PackDisableRewards()
+	if err != nil {
+		return nil, err
+	}
+	return ec.Transact(ctx, key, rewardmanager.ContractAddress, nil, data)
+}
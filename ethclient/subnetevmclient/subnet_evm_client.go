@@ -45,12 +45,45 @@ import (
 //
 // If you want to use the standardized Ethereum RPC functionality, use ethclient.Client instead.
 type Client struct {
-	c *rpc.Client
+	c   *rpc.Client
+	eth ethclient.Client
 }
 
 // New creates a client that uses the given RPC client.
 func New(c *rpc.Client) *Client {
-	return &Client{c}
+	return &Client{
+		c:   c,
+		eth: ethclient.NewClient(c),
+	}
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL with context.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return New(c), nil
+}
+
+// Close closes the underlying RPC connection.
+func (ec *Client) Close() {
+	ec.c.Close()
+}
+
+// Client returns the underlying RPC client.
+func (ec *Client) Client() *rpc.Client {
+	return ec.c
+}
+
+// Eth returns a client for the standard Ethereum RPC functionality of the same node.
+func (ec *Client) Eth() ethclient.Client {
+	return ec.eth
 }
 
 // CreateAccessList tries to create an access list for a specific transaction based on the
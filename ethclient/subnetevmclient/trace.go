@@ -0,0 +1,159 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/eth/tracers"
+	"github.com/shubhamdubey02/subnet-evm/eth/tracers/logger"
+	"github.com/shubhamdubey02/subnet-evm/ethclient"
+	"github.com/shubhamdubey02/subnet-evm/interfaces"
+)
+
+const callTracer = "callTracer"
+
+// CallFrame is a call of the trace produced by the callTracer.
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []CallFrame     `json:"calls,omitempty"`
+	Logs         []CallLog       `json:"logs,omitempty"`
+	// PrecompileState is the state accessed by a stateful precompile executed
+	// in this call.
+	PrecompileState []PrecompileAccess `json:"precompileState,omitempty"`
+}
+
+// CallLog is a log emitted in a call of the trace produced by the callTracer.
+type CallLog struct {
+	Address common.Address `json:"address"`
+	Topics  []common.Hash  `json:"topics"`
+	Data    hexutil.Bytes  `json:"data"`
+}
+
+// PrecompileAccess is a read or write of the state of a stateful precompile.
+type PrecompileAccess struct {
+	Precompile common.Address `json:"precompile"`
+	Type       string         `json:"type"`
+	Address    common.Address `json:"address"`
+	Key        *common.Hash   `json:"key,omitempty"`
+	Prev       *common.Hash   `json:"prev,omitempty"`
+	Value      *common.Hash   `json:"value,omitempty"`
+	Amount     *hexutil.Big   `json:"amount,omitempty"`
+	Decoded    string         `json:"decoded,omitempty"`
+}
+
+// TxTraceResult is the trace of a transaction of a block.
+type TxTraceResult struct {
+	TxHash common.Hash     `json:"txHash"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// TraceTransaction returns the trace of the transaction with the given hash,
+// produced by the tracer selected in [config].
+func (ec *Client) TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, config); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TraceTransactionStructLogs returns the opcode level trace of the transaction
+// with the given hash.
+func (ec *Client) TraceTransactionStructLogs(ctx context.Context, hash common.Hash, config *logger.Config) (*logger.ExecutionResult, error) {
+	var result logger.ExecutionResult
+	if err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, &tracers.TraceConfig{Config: config}); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TraceTransactionCalls returns the call tree of the transaction with the
+// given hash, including the emitted logs.
+func (ec *Client) TraceTransactionCalls(ctx context.Context, hash common.Hash) (*CallFrame, error) {
+	var result CallFrame
+	if err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, callTracerConfig()); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TraceBlock returns the traces of the transactions of the given block,
+// produced by the tracer selected in [config]. The block number can be nil,
+// in which case the latest block is traced.
+func (ec *Client) TraceBlock(ctx context.Context, blockNumber *big.Int, config *tracers.TraceConfig) ([]TxTraceResult, error) {
+	var result []TxTraceResult
+	if err := ec.c.CallContext(ctx, &result, "debug_traceBlockByNumber", ethclient.ToBlockNumArg(blockNumber), config); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TraceBlockCalls returns the call trees of the transactions of the given
+// block, in the order of the transactions. The block number can be nil, in
+// which case the latest block is traced.
+func (ec *Client) TraceBlockCalls(ctx context.Context, blockNumber *big.Int) ([]*CallFrame, error) {
+	results, err := ec.TraceBlock(ctx, blockNumber, callTracerConfig())
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]*CallFrame, 0, len(results))
+	for _, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("failed to trace transaction %s: %s", result.TxHash, result.Error)
+		}
+		var frame CallFrame
+		if err := json.Unmarshal(result.Result, &frame); err != nil {
+			return nil, err
+		}
+		frames = append(frames, &frame)
+	}
+	return frames, nil
+}
+
+// TraceCall returns the trace of executing [msg] on top of the given block,
+// produced by the tracer selected in [config]. The block number can be nil,
+// in which case the latest block is used.
+func (ec *Client) TraceCall(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int, config *tracers.TraceCallConfig) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := ec.c.CallContext(ctx, &result, "debug_traceCall", toCallArg(msg), ethclient.ToBlockNumArg(blockNumber), config); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TraceCallCalls returns the call tree of executing [msg] on top of the given
+// block. The block number can be nil, in which case the latest block is used.
+func (ec *Client) TraceCallCalls(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int) (*CallFrame, error) {
+	var result CallFrame
+	config := &tracers.TraceCallConfig{TraceConfig: *callTracerConfig()}
+	if err := ec.c.CallContext(ctx, &result, "debug_traceCall", toCallArg(msg), ethclient.ToBlockNumArg(blockNumber), config); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// callTracerConfig returns the config of the callTracer including the logs.
+func callTracerConfig() *tracers.TraceConfig {
+	tracer := callTracer
+	return &tracers.TraceConfig{
+		Tracer:       &tracer,
+		TracerConfig: json.RawMessage(`{"withLog": true, "withPrecompileState": true}`),
+	}
+}
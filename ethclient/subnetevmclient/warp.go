@@ -0,0 +1,76 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package subnetevmclient
+
+import (
+	"context"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/warp"
+)
+
+// WarpMessage returns the unsigned warp message with the given ID.
+func (ec *Client) WarpMessage(ctx context.Context, messageID ids.ID) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "warp_getMessage", messageID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WarpMessages returns a page of the warp messages sent from the chain that
+// match [filter]. The next page is requested by setting the cursor of the
+// filter to the cursor of the page. Only deliveries on the same chain are
+// reported, messages delivered to other chains remain pending.
+func (ec *Client) WarpMessages(ctx context.Context, filter warp.MessageFilter) (*warp.MessagesPage, error) {
+	var result warp.MessagesPage
+	if err := ec.c.CallContext(ctx, &result, "warp_getMessages", filter); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WarpMessageSignature returns the BLS signature of the node over the warp
+// message with the given ID.
+func (ec *Client) WarpMessageSignature(ctx context.Context, messageID ids.ID) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "warp_getMessageSignature", messageID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WarpMessageAggregateSignature returns the warp message with the given ID
+// signed by at least [quorumNum] percent of the stake of the validators of
+// the subnet [subnetID]. An empty subnet ID selects the subnet of the chain.
+func (ec *Client) WarpMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetID string) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "warp_getMessageAggregateSignature", messageID, quorumNum, subnetID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WarpBlockSignature returns the BLS signature of the node over the block
+// hash payload of the block with the given ID.
+func (ec *Client) WarpBlockSignature(ctx context.Context, blockID ids.ID) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "warp_getBlockSignature", blockID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WarpBlockAggregateSignature returns the block hash payload of the block with
+// the given ID signed by at least [quorumNum] percent of the stake of the
+// validators of the subnet [subnetID]. An empty subnet ID selects the subnet
+// of the chain.
+func (ec *Client) WarpBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetID string) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &result, "warp_getBlockAggregateSignature", blockID, quorumNum, subnetID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
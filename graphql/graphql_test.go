@@ -0,0 +1,124 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/internal/ethapi"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackend serves a chain of [numBlocks] headers. The other methods of
+// ethapi.Backend are not implemented.
+type testBackend struct {
+	ethapi.Backend
+	numBlocks int64
+}
+
+func (b *testBackend) CurrentBlock() *types.Header {
+	return &types.Header{Number: big.NewInt(b.numBlocks - 1)}
+}
+
+func (b *testBackend) HeaderByNumberOrHash(_ context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	number, ok := blockNrOrHash.Number()
+	if !ok || int64(number) >= b.numBlocks {
+		return nil, nil
+	}
+	return &types.Header{Number: big.NewInt(number.Int64())}, nil
+}
+
+func TestBlocks(t *testing.T) {
+	tests := map[string]struct {
+		query          string
+		expectedBlocks []string
+		expectedErr    string
+	}{
+		"range": {
+			query:          `{ blocks(from: 1, to: 3) { number } }`,
+			expectedBlocks: []string{"0x1", "0x2", "0x3"},
+		},
+		"range to the current block": {
+			query:          `{ blocks(from: 6) { number } }`,
+			expectedBlocks: []string{"0x6", "0x7"},
+		},
+		"range after the current block": {
+			query:          `{ blocks(from: 7, to: 9) { number } }`,
+			expectedBlocks: []string{"0x7"},
+		},
+		"missing from": {
+			query:       `{ blocks(to: 3) { number } }`,
+			expectedErr: errMissingFromBlock.Error(),
+		},
+		"missing from and to": {
+			query:       `{ blocks { number } }`,
+			expectedErr: errMissingFromBlock.Error(),
+		},
+		"too many blocks": {
+			query:       `{ blocks(from: 0, to: 4) { number } }`,
+			expectedErr: "requested too many blocks from 0 to 4, maximum is set to 4",
+		},
+		"too many blocks to the current block": {
+			query:       `{ blocks(from: 2) { number } }`,
+			expectedErr: "requested too many blocks from 2 to 7, maximum is set to 4",
+		},
+		"aliased ranges": {
+			query:          `{ first: blocks(from: 0, to: 1) { number } blocks(from: 2, to: 3) { number } }`,
+			expectedBlocks: []string{"0x2", "0x3"},
+		},
+		"too many blocks in aliased ranges": {
+			query:       `{ first: blocks(from: 0, to: 3) { number } blocks(from: 4, to: 7) { number } }`,
+			expectedErr: "requested too many blocks in total, maximum is set to 4",
+		},
+		"too deep": {
+			query:       `{ block { parent { parent { parent { parent { parent { parent { parent { parent { parent { number } } } } } } } } } } }`,
+			expectedErr: "Field \"number\" has depth 11 that exceeds max depth 10",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			h, err := newHandler(&testBackend{numBlocks: 8}, nil, 4)
+			require.NoError(err)
+			body, err := json.Marshal(map[string]string{"query": test.query})
+			require.NoError(err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+			var response struct {
+				Data struct {
+					Blocks []struct {
+						Number string `json:"number"`
+					} `json:"blocks"`
+				} `json:"data"`
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(json.Unmarshal(w.Body.Bytes(), &response))
+			if test.expectedErr != "" {
+				require.Equal(http.StatusBadRequest, w.Code)
+				require.Len(response.Errors, 1)
+				require.Equal(test.expectedErr, response.Errors[0].Message)
+				return
+			}
+			require.Equal(http.StatusOK, w.Code)
+			require.Empty(response.Errors)
+			var numbers []string
+			for _, block := range response.Data.Blocks {
+				numbers = append(numbers, block.Number)
+			}
+			require.Equal(test.expectedBlocks, numbers)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// (c) 2024, Ava Labs, Inc.
+//
+// This file is a derived work, based on the go-ethereum library whose original
+// notices appear below.
+//
+// It is distributed under a license compatible with the licensing terms of the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/shubhamdubey02/subnet-evm/eth/filters"
+	"github.com/shubhamdubey02/subnet-evm/internal/ethapi"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+)
+
+// maxQueryDepth is the maximum depth of the selections of a query.
+const maxQueryDepth = 10
+
+type handler struct {
+	Schema    *graphql.Schema
+	maxBlocks int64
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		ctx       = r.Context()
+		responded sync.Once
+		timer     *time.Timer
+		cancel    context.CancelFunc
+	)
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+	if h.maxBlocks > 0 {
+		// The block ranges of all the fields of a query share the budget, so
+		// aliasing range queries does not read more blocks.
+		ctx = withBlockBudget(ctx, h.maxBlocks)
+	}
+
+	if timeout, ok := rpc.ContextRequestTimeout(ctx); ok {
+		timer = time.AfterFunc(timeout, func() {
+			responded.Do(func() {
+				// Cancel request handling.
+				cancel()
+
+				// Create the timeout response.
+				response := &graphql.Response{
+					Errors: []*gqlErrors.QueryError{{Message: "request timed out"}},
+				}
+				responseJSON, err := json.Marshal(response)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+
+				// Setting this disables gzip compression in package node.
+				w.Header().Set("transfer-encoding", "identity")
+
+				// Flush the response. Since we are writing close to the response timeout,
+				// chunked transfer encoding must be disabled by setting content-length.
+				w.Header().Set("content-type", "application/json")
+				w.Header().Set("content-length", strconv.Itoa(len(responseJSON)))
+				w.Write(responseJSON)
+				if flush, ok := w.(http.Flusher); ok {
+					flush.Flush()
+				}
+			})
+		})
+	}
+
+	response := h.Schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+	if timer != nil {
+		timer.Stop()
+	}
+	responded.Do(func() {
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(response.Errors) > 0 {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(responseJSON)
+	})
+}
+
+// New returns a new `http.Handler` that answers GraphQL queries about the
+// chain data served by [backend]. The block ranges read by a request are
+// limited to [maxBlocks] blocks in total, unless it is 0.
+func New(backend ethapi.Backend, filterSystem *filters.FilterSystem, maxBlocks int64) (http.Handler, error) {
+	return newHandler(backend, filterSystem, maxBlocks)
+}
+
+func newHandler(backend ethapi.Backend, filterSystem *filters.FilterSystem, maxBlocks int64) (*handler, error) {
+	q := Resolver{backend, filterSystem, maxBlocks}
+
+	s, err := graphql.ParseSchema(schema, &q, graphql.MaxDepth(maxQueryDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &handler{Schema: s, maxBlocks: maxBlocks}, nil
+}
+
+// blockBudget is the number of blocks the range queries of a request may still
+// read. The fields of a query are resolved concurrently.
+type blockBudget struct {
+	lock      sync.Mutex
+	max       int64
+	remaining int64
+}
+
+type blockBudgetKey struct{}
+
+// withBlockBudget returns a copy of [ctx] carrying a budget of [maxBlocks] blocks.
+func withBlockBudget(ctx context.Context, maxBlocks int64) context.Context {
+	return context.WithValue(ctx, blockBudgetKey{}, &blockBudget{max: maxBlocks, remaining: maxBlocks})
+}
+
+// takeBlocks takes the [numBlocks] blocks read by a range query from the
+// budget of the request in [ctx], or returns an error if it does not cover them.
+func takeBlocks(ctx context.Context, numBlocks int64) error {
+	budget, ok := ctx.Value(blockBudgetKey{}).(*blockBudget)
+	if !ok {
+		return nil
+	}
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if numBlocks > budget.remaining {
+		return fmt.Errorf("requested too many blocks in total, maximum is set to %d", budget.max)
+	}
+	budget.remaining -= numBlocks
+	return nil
+}
@@ -1647,16 +1647,28 @@ func AccessList(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrH
 		to = crypto.CreateAddress(args.from(), uint64(*args.Nonce))
 	}
 	// Retrieve the precompiles since they don't need to be added to the access list
-	precompiles := vm.ActivePrecompiles(b.ChainConfig().Rules(header.Number, header.Time))
+	rules := b.ChainConfig().Rules(header.Number, header.Time)
+	precompiles := vm.ActivePrecompiles(rules)
 
-	// Create an initial tracer
-	prevTracer := logger.NewAccessListTracer(nil, args.from(), to, precompiles)
+	// Predicates are not discovered by the tracer, since they are addressed to
+	// precompiles, so carry them over to every access list explicitly.
+	var predicates, initial types.AccessList
 	if args.AccessList != nil {
-		prevTracer = logger.NewAccessListTracer(*args.AccessList, args.from(), to, precompiles)
+		for _, tuple := range *args.AccessList {
+			if _, ok := rules.Predicaters[tuple.Address]; ok {
+				predicates = append(predicates, tuple)
+			} else {
+				initial = append(initial, tuple)
+			}
+		}
 	}
+
+	// Create an initial tracer
+	prevTracer := logger.NewAccessListTracer(initial, args.from(), to, precompiles)
 	for {
 		// Retrieve the current access list to expand
-		accessList := prevTracer.AccessList()
+		traced := prevTracer.AccessList()
+		accessList := append(append(types.AccessList{}, traced...), predicates...)
 		log.Trace("Creating access list", "input", accessList)
 
 		// Copy the original db so we don't modify it
@@ -1669,7 +1681,7 @@ func AccessList(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrH
 		}
 
 		// Apply the transaction with the access list tracer
-		tracer := logger.NewAccessListTracer(accessList, args.from(), to, precompiles)
+		tracer := logger.NewAccessListTracer(traced, args.from(), to, precompiles)
 		config := vm.Config{Tracer: tracer, NoBaseFee: true}
 		vmenv, _ := b.GetEVM(ctx, msg, statedb, header, &config, nil)
 		res, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
@@ -37,6 +37,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -54,7 +57,10 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core/vm"
 	"github.com/shubhamdubey02/subnet-evm/internal/blocktest"
 	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
 	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -490,7 +496,7 @@ func (b testBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) even
 	panic("implement me")
 }
 func (b testBackend) GetFeeConfigAt(parent *types.Header) (commontype.FeeConfig, *big.Int, error) {
-	panic("implement me")
+	return b.chain.GetFeeConfigAt(parent)
 }
 func (b testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	panic("implement me")
@@ -536,7 +542,7 @@ func (b testBackend) BadBlocks() ([]*types.Block, []*core.BadBlockReason) { retu
 func (b testBackend) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
 	panic("implement me")
 }
-func (b testBackend) LastAcceptedBlock() *types.Block { panic("implement me") }
+func (b testBackend) LastAcceptedBlock() *types.Block { return b.chain.LastAcceptedBlock() }
 func (b testBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	panic("implement me")
 }
@@ -644,6 +650,64 @@ func TestEstimateGas(t *testing.T) {
 	}
 }
 
+func TestEstimateGasWithWarpMessages(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var (
+		accounts = newAccounts(2)
+		config   = *params.TestChainConfig
+	)
+	config.GenesisPrecompiles = params.Precompiles{
+		warp.ConfigKey: warp.NewDefaultConfig(utils.NewUint64(0)),
+	}
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	addressedCall, err := payload.NewAddressedCall(accounts[0].addr.Bytes(), []byte{1, 2, 3})
+	require.NoError(err)
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(config.SnowCtx.NetworkID, config.SnowCtx.ChainID, addressedCall.Bytes())
+	require.NoError(err)
+	signers := set.NewBits(0, 1)
+	warpMsg, err := avalancheWarp.NewMessage(unsignedMsg, &avalancheWarp.BitSetSignature{Signers: signers.Bytes()})
+	require.NoError(err)
+
+	predicateBytes := predicate.PackPredicate(warpMsg.Bytes())
+	predicateGas, err := warp.NewDefaultConfig(nil).PredicateGas(predicateBytes)
+	require.NoError(err)
+
+	args := TransactionArgs{
+		From:         &accounts[0].addr,
+		To:           &accounts[1].addr,
+		WarpMessages: []hexutil.Bytes{warpMsg.Bytes()},
+	}
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	estimated, err := api.EstimateGas(context.Background(), args, &latest, nil)
+	require.NoError(err)
+	require.EqualValues(params.TxGas+predicateGas, estimated)
+
+	// The generated access list carries the predicate, so it can be used
+	// without the warp messages.
+	args.Nonce = new(hexutil.Uint64)
+	result, err := api.CreateAccessList(context.Background(), args, &latest)
+	require.NoError(err)
+	require.Empty(result.Error)
+	require.EqualValues(params.TxGas+predicateGas, result.GasUsed)
+	require.Equal(types.AccessList{{
+		Address:     warp.ContractAddress,
+		StorageKeys: utils.BytesToHashSlice(predicateBytes),
+	}}, *result.Accesslist)
+
+	args.WarpMessages = []hexutil.Bytes{{1, 2, 3}}
+	_, err = api.EstimateGas(context.Background(), args, &latest, nil)
+	require.ErrorContains(err, "invalid warp message 0")
+}
+
 func TestCall(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
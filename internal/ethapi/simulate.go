@@ -0,0 +1,479 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/commontype"
+	"github.com/shubhamdubey02/subnet-evm/consensus/dummy"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/core/vm"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/vmerrs"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated
+	// in a single request.
+	maxSimulateBlocks = 256
+
+	// Error codes reported for failed calls in a simulation.
+	simErrCodeReverted   = 3
+	simErrCodeVMError    = -32015
+	simErrCodeInvalidTx  = -38000
+	simErrCodeNotAllowed = -38001
+)
+
+var (
+	errSimulateNoBlocks       = errors.New("empty input")
+	errSimulateTooManyBlocks  = fmt.Errorf("too many blocks (max %d)", maxSimulateBlocks)
+	errSimulateBlockNumber    = errors.New("block numbers must be strictly increasing")
+	errSimulateBlockTimestamp = errors.New("block timestamps must be non-decreasing")
+	errSimulateGasCapReached  = errors.New("simulation exceeded the RPC gas cap")
+)
+
+// SimulateOpts is the input of eth_simulateV1.
+type SimulateOpts struct {
+	BlockStateCalls []SimulateBlock `json:"blockStateCalls"`
+	// Validation enables the nonce, balance, fee and tx allow list checks
+	// that are applied to transactions included in a real block.
+	Validation bool `json:"validation"`
+	// PChainHeight is the P-Chain height used to verify predicates. If it is
+	// not set, the current P-Chain height is used.
+	PChainHeight *hexutil.Uint64 `json:"pChainHeight,omitempty"`
+}
+
+// SimulateBlock is a block to be simulated, executing [Calls] in order on top
+// of the state left by the previous block.
+type SimulateBlock struct {
+	BlockOverrides *BlockOverrides   `json:"blockOverrides,omitempty"`
+	StateOverrides *StateOverride    `json:"stateOverrides,omitempty"`
+	Calls          []TransactionArgs `json:"calls"`
+}
+
+// SimulateBlockResult is the result of a simulated block.
+type SimulateBlockResult struct {
+	Number     hexutil.Uint64       `json:"number"`
+	Hash       common.Hash          `json:"hash"`
+	ParentHash common.Hash          `json:"parentHash"`
+	Timestamp  hexutil.Uint64       `json:"timestamp"`
+	GasLimit   hexutil.Uint64       `json:"gasLimit"`
+	GasUsed    hexutil.Uint64       `json:"gasUsed"`
+	Coinbase   common.Address       `json:"miner"`
+	BaseFee    *hexutil.Big         `json:"baseFeePerGas,omitempty"`
+	StateRoot  common.Hash          `json:"stateRoot"`
+	Calls      []SimulateCallResult `json:"calls"`
+}
+
+// SimulateCallResult is the result of a single call of a simulated block.
+type SimulateCallResult struct {
+	ReturnData hexutil.Bytes      `json:"returnData"`
+	Logs       []*types.Log       `json:"logs"`
+	GasUsed    hexutil.Uint64     `json:"gasUsed"`
+	Status     hexutil.Uint64     `json:"status"`
+	Error      *SimulateCallError `json:"error,omitempty"`
+}
+
+// SimulateCallError describes why a simulated call failed.
+type SimulateCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// simChainContext resolves the headers of the simulated blocks in addition to
+// the canonical headers, so BLOCKHASH works across simulated blocks.
+type simChainContext struct {
+	*ChainContext
+	headers map[uint64]*types.Header
+}
+
+func (c *simChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := c.headers[number]; ok {
+		if header.Hash() != hash {
+			return nil
+		}
+		return header
+	}
+	return c.ChainContext.GetHeader(hash, number)
+}
+
+// SimulateV1 executes a series of blocks, each containing a list of calls, on
+// top of the state of [blockNrOrHash]. The state changes of each call are
+// visible to the calls and blocks that follow it, and nothing is persisted.
+//
+// Subnet rules are honored: senders must be enabled in the tx allow list, the
+// fee config stored by the fee manager (including changes made by earlier
+// simulated blocks) determines the gas limit and base fee of each block, and
+// predicates included in the access list of a call are verified.
+func (s *BlockChainAPI) SimulateV1(ctx context.Context, opts SimulateOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]*SimulateBlockResult, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errSimulateNoBlocks
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, errSimulateTooManyBlocks
+	}
+	if blockNrOrHash == nil {
+		n := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &n
+	}
+	defer func(start time.Time) { log.Debug("Executing simulation finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, base, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	var cancel context.CancelFunc
+	if timeout := s.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	sim := &simulator{
+		b:        s.b,
+		state:    state,
+		config:   s.b.ChainConfig(),
+		opts:     &opts,
+		gasCap:   s.b.RPCGasCap(),
+		capped:   s.b.RPCGasCap() != 0,
+		chainCtx: &simChainContext{ChainContext: NewChainContext(ctx, s.b), headers: make(map[uint64]*types.Header)},
+	}
+	return sim.execute(ctx, base)
+}
+
+// simulator holds the state shared by the blocks of a simulation.
+type simulator struct {
+	b            Backend
+	state        *state.StateDB
+	config       *params.ChainConfig
+	opts         *SimulateOpts
+	gasCap       uint64 // remaining gas budget of the simulation
+	capped       bool   // whether [gasCap] applies
+	chainCtx     *simChainContext
+	predicateCtx *precompileconfig.PredicateContext // lazily initialized by [predicateContext]
+}
+
+func (sim *simulator) execute(ctx context.Context, base *types.Header) ([]*SimulateBlockResult, error) {
+	var (
+		parent  = base
+		results = make([]*SimulateBlockResult, 0, len(sim.opts.BlockStateCalls))
+	)
+	for bi, block := range sim.opts.BlockStateCalls {
+		header, err := sim.makeHeader(parent, block.BlockOverrides, bi == 0)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", bi, err)
+		}
+		result, err := sim.processBlock(ctx, parent, header, &block)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", bi, err)
+		}
+		sim.chainCtx.headers[header.Number.Uint64()] = header
+		results = append(results, result)
+		parent = header
+	}
+	return results, nil
+}
+
+// feeConfig returns the fee config in effect for the block after [parent].
+func (sim *simulator) feeConfig(parent *types.Header, first bool) (commontype.FeeConfig, error) {
+	if first {
+		feeConfig, _, err := sim.b.GetFeeConfigAt(parent)
+		return feeConfig, err
+	}
+	// The parent is a simulated block, so read the fee config from the
+	// simulated state instead of the chain.
+	if !sim.config.IsPrecompileEnabled(feemanager.ContractAddress, parent.Time) {
+		return sim.config.FeeConfig, nil
+	}
+	feeConfig := feemanager.GetStoredFeeConfig(sim.state)
+	if err := feeConfig.Verify(); err != nil {
+		return commontype.EmptyFeeConfig, err
+	}
+	return feeConfig, nil
+}
+
+// makeHeader builds the header of the block simulated on top of [parent],
+// the same way the miner does, and applies [overrides] to it.
+func (sim *simulator) makeHeader(parent *types.Header, overrides *BlockOverrides, first bool) (*types.Header, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		Time:       parent.Time + 1,
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int).Set(common.Big1),
+	}
+	if overrides != nil {
+		if overrides.Number != nil {
+			header.Number = new(big.Int).Set(overrides.Number.ToInt())
+		}
+		if overrides.Time != nil {
+			header.Time = uint64(*overrides.Time)
+		}
+		if overrides.Coinbase != nil {
+			header.Coinbase = *overrides.Coinbase
+		}
+		if overrides.Difficulty != nil {
+			header.Difficulty = new(big.Int).Set(overrides.Difficulty.ToInt())
+		}
+	}
+	if header.Number.Cmp(parent.Number) <= 0 {
+		return nil, errSimulateBlockNumber
+	}
+	if header.Time < parent.Time {
+		return nil, errSimulateBlockTimestamp
+	}
+
+	feeConfig, err := sim.feeConfig(parent, first)
+	if err != nil {
+		return nil, err
+	}
+	configuredGasLimit := feeConfig.GasLimit.Uint64()
+	if sim.config.IsSubnetEVM(header.Time) {
+		header.GasLimit = configuredGasLimit
+		header.Extra, header.BaseFee, err = dummy.CalcBaseFee(sim.config, feeConfig, parent, header.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate new base fee: %w", err)
+		}
+	} else {
+		header.GasLimit = core.CalcGasLimit(parent.GasUsed, parent.GasLimit, configuredGasLimit, configuredGasLimit)
+	}
+	if overrides != nil {
+		if overrides.GasLimit != nil {
+			header.GasLimit = uint64(*overrides.GasLimit)
+		}
+		if overrides.BaseFee != nil {
+			header.BaseFee = new(big.Int).Set(overrides.BaseFee.ToInt())
+		}
+	}
+	return header, nil
+}
+
+// processBlock executes the calls of [block] on top of the simulated state
+// and finalizes [header].
+func (sim *simulator) processBlock(ctx context.Context, parent *types.Header, header *types.Header, block *SimulateBlock) (*SimulateBlockResult, error) {
+	// Activate any precompile or state upgrade scheduled between the parent
+	// and this block, as the state processor does.
+	upgradeCtx := core.NewEVMBlockContext(header, sim.chainCtx, nil)
+	if err := core.ApplyUpgrades(sim.config, &parent.Time, &upgradeCtx, sim.state); err != nil {
+		return nil, err
+	}
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, err
+	}
+
+	var (
+		rules            = sim.config.Rules(header.Number, header.Time)
+		predicateResults = predicate.NewResults()
+		blockCtx         = core.NewEVMBlockContextWithPredicateResults(header, sim.chainCtx, nil, predicateResults)
+		vmConfig         = &vm.Config{NoBaseFee: !sim.opts.Validation}
+		gp               = new(core.GasPool).AddGas(header.GasLimit)
+		txHashes         = make([]common.Hash, len(block.Calls))
+		calls            = make([]SimulateCallResult, len(block.Calls))
+		gasUsed          uint64
+	)
+	for i := range block.Calls {
+		args := block.Calls[i]
+		if err := sim.setCallDefaults(&args, header, gp); err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		tx := args.ToTransaction()
+		txHashes[i] = simulatedTxHash(tx, args.from(), header.Number, i)
+
+		result, callErr := sim.applyCall(ctx, &args, tx, txHashes[i], header, rules, blockCtx, predicateResults, vmConfig, gp, i)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", sim.b.RPCEVMTimeout())
+		}
+		if callErr != nil {
+			calls[i] = SimulateCallResult{ReturnData: hexutil.Bytes{}, Error: callErr}
+			continue
+		}
+		gasUsed += result.UsedGas
+		if sim.capped {
+			sim.gasCap -= min(sim.gasCap, result.UsedGas)
+		}
+		calls[i] = SimulateCallResult{
+			ReturnData: result.Return(),
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+			Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		}
+		if result.Failed() {
+			calls[i].Status = hexutil.Uint64(types.ReceiptStatusFailed)
+			calls[i].ReturnData = result.Revert()
+			if len(result.Revert()) > 0 {
+				revertErr := newRevertError(result)
+				calls[i].Error = &SimulateCallError{Code: simErrCodeReverted, Message: revertErr.Error(), Data: revertErr.reason}
+			} else {
+				calls[i].Error = &SimulateCallError{Code: simErrCodeVMError, Message: result.Err.Error()}
+			}
+		}
+	}
+
+	header.GasUsed = gasUsed
+	header.Root = sim.state.IntermediateRoot(sim.config.IsEIP158(header.Number))
+	hash := header.Hash()
+	for i := range calls {
+		logs := sim.state.GetLogs(txHashes[i], header.Number.Uint64(), hash)
+		if logs == nil {
+			logs = []*types.Log{}
+		}
+		calls[i].Logs = logs
+	}
+	result := &SimulateBlockResult{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		Hash:       hash,
+		ParentHash: header.ParentHash,
+		Timestamp:  hexutil.Uint64(header.Time),
+		GasLimit:   hexutil.Uint64(header.GasLimit),
+		GasUsed:    hexutil.Uint64(header.GasUsed),
+		Coinbase:   header.Coinbase,
+		StateRoot:  header.Root,
+		Calls:      calls,
+	}
+	if header.BaseFee != nil {
+		result.BaseFee = (*hexutil.Big)(header.BaseFee)
+	}
+	return result, nil
+}
+
+// simulatedTxHash returns the hash identifying the call [tx] from [from] at
+// [index] in block [number]. The unsigned transaction hash does not cover the
+// sender, so identical calls from different senders would share their logs and
+// predicate results.
+func simulatedTxHash(tx *types.Transaction, from common.Address, number *big.Int, index int) common.Hash {
+	return crypto.Keccak256Hash(tx.Hash().Bytes(), from.Bytes(), number.Bytes(), big.NewInt(int64(index)).Bytes())
+}
+
+// predicateContext returns the context used to verify the predicates of
+// [tx]. The P-Chain height is only resolved once a call includes a predicate.
+func (sim *simulator) predicateContext(ctx context.Context, rules params.Rules, tx *types.Transaction) (*precompileconfig.PredicateContext, error) {
+	if sim.predicateCtx != nil || len(predicate.PreparePredicateStorageSlots(rules, tx.AccessList())) == 0 {
+		return sim.predicateCtx, nil
+	}
+	snowCtx := sim.config.SnowCtx
+	if snowCtx == nil {
+		return nil, nil
+	}
+	predicateCtx := &precompileconfig.PredicateContext{SnowCtx: snowCtx}
+	switch {
+	case sim.opts.PChainHeight != nil:
+		predicateCtx.ProposerVMBlockCtx = &block.Context{PChainHeight: uint64(*sim.opts.PChainHeight)}
+	case snowCtx.ValidatorState != nil:
+		height, err := snowCtx.ValidatorState.GetCurrentHeight(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current P-Chain height: %w", err)
+		}
+		predicateCtx.ProposerVMBlockCtx = &block.Context{PChainHeight: height}
+	}
+	sim.predicateCtx = predicateCtx
+	return predicateCtx, nil
+}
+
+// setCallDefaults fills in the fields of [args] that were left empty. Calls
+// default to the sender's current nonce and to the gas left in the block.
+func (sim *simulator) setCallDefaults(args *TransactionArgs, header *types.Header, gp *core.GasPool) error {
+	if err := args.setPredicateDefaults(); err != nil {
+		return err
+	}
+	if args.Nonce == nil {
+		nonce := sim.state.GetNonce(args.from())
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if sim.capped && sim.gasCap == 0 {
+		return errSimulateGasCapReached
+	}
+	if args.Gas == nil {
+		gas := gp.Gas()
+		if sim.capped && sim.gasCap < gas {
+			gas = sim.gasCap
+		}
+		args.Gas = (*hexutil.Uint64)(&gas)
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(sim.config.ChainID)
+	}
+	if sim.opts.Validation && header.BaseFee != nil && args.GasPrice == nil && args.MaxFeePerGas == nil {
+		// Pay the base fee without a tip unless the caller specified the fees.
+		args.MaxFeePerGas = (*hexutil.Big)(header.BaseFee)
+		if args.MaxPriorityFeePerGas == nil {
+			args.MaxPriorityFeePerGas = new(hexutil.Big)
+		}
+	}
+	return nil
+}
+
+// applyCall executes a single call of a simulated block. Failures that would
+// prevent the call from being included in a block are returned as a
+// [SimulateCallError] rather than an execution result.
+func (sim *simulator) applyCall(ctx context.Context, args *TransactionArgs, tx *types.Transaction, txHash common.Hash, header *types.Header, rules params.Rules, blockCtx vm.BlockContext, predicateResults *predicate.Results, vmConfig *vm.Config, gp *core.GasPool, index int) (*core.ExecutionResult, *SimulateCallError) {
+	msg, err := args.ToMessage(sim.gasCap, header.BaseFee)
+	if err != nil {
+		return nil, &SimulateCallError{Code: simErrCodeInvalidTx, Message: err.Error()}
+	}
+	msg.Nonce = tx.Nonce()
+	msg.SkipAccountChecks = !sim.opts.Validation
+
+	// The tx allow list is enforced even without validation, since the call
+	// could never be included otherwise.
+	if sim.config.IsPrecompileEnabled(txallowlist.ContractAddress, header.Time) {
+		if role := txallowlist.GetTxAllowListStatus(sim.state, msg.From); !role.IsEnabled() {
+			err := fmt.Errorf("%w: %s", vmerrs.ErrSenderAddressNotAllowListed, msg.From)
+			return nil, &SimulateCallError{Code: simErrCodeNotAllowed, Message: err.Error()}
+		}
+	}
+	predicateCtx, err := sim.predicateContext(ctx, rules, tx)
+	if err != nil {
+		return nil, &SimulateCallError{Code: simErrCodeInvalidTx, Message: err.Error()}
+	}
+	txPredicateResults, err := core.CheckPredicates(rules, predicateCtx, tx)
+	if err != nil {
+		return nil, &SimulateCallError{Code: simErrCodeInvalidTx, Message: err.Error()}
+	}
+	predicateResults.SetTxResults(txHash, txPredicateResults)
+
+	sim.state.SetTxContext(txHash, index)
+	evm, vmError := sim.b.GetEVM(ctx, msg, sim.state, header, vmConfig, &blockCtx)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	// A failed call is not part of the block, so the gas it bought is returned
+	// to the pool along with the state changes.
+	snapshot, gasSnapshot := sim.state.Snapshot(), gp.Gas()
+	result, err := core.ApplyMessage(evm, msg, gp)
+	if vmErr := vmError(); vmErr != nil {
+		err = vmErr
+	}
+	if err != nil {
+		sim.state.RevertToSnapshot(snapshot)
+		gp.SetGas(gasSnapshot)
+		return nil, &SimulateCallError{Code: simErrCodeInvalidTx, Message: err.Error()}
+	}
+	sim.state.Finalise(true)
+	return result, nil
+}
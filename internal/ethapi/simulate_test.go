@@ -0,0 +1,163 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/vmerrs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var (
+		accounts = newAccounts(3)
+		config   = *params.TestChainConfig
+		// logger emits a single LOG0 with one byte of memory
+		logger    = common.HexToAddress("0x1234")
+		genBlocks = 2
+	)
+	config.GenesisPrecompiles = params.Precompiles{
+		txallowlist.ConfigKey: txallowlist.NewConfig(new(uint64), []common.Address{accounts[0].addr}, []common.Address{accounts[1].addr}, nil),
+	}
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(2 * params.Ether)},
+			accounts[1].addr: {Balance: big.NewInt(params.Ether)},
+			accounts[2].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, genBlocks, genesis, func(i int, b *core.BlockGen) {}))
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	results, err := api.SimulateV1(context.Background(), SimulateOpts{
+		BlockStateCalls: []SimulateBlock{
+			{
+				StateOverrides: &StateOverride{
+					logger: {Code: hex2Bytes("60016000a0")},
+				},
+				Calls: []TransactionArgs{
+					{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(params.Ether))},
+					{From: &accounts[0].addr, To: &logger},
+				},
+			},
+			{
+				Calls: []TransactionArgs{
+					// Only succeeds with the funds received in the previous block
+					{From: &accounts[1].addr, To: &accounts[2].addr, Value: (*hexutil.Big)(big.NewInt(3 * params.Ether / 2))},
+					// accounts[2] is not enabled in the tx allow list
+					{From: &accounts[2].addr, To: &accounts[0].addr},
+				},
+			},
+		},
+	}, &latest)
+	require.NoError(err)
+	require.Len(results, 2)
+
+	first, second := results[0], results[1]
+	require.EqualValues(genBlocks+1, first.Number)
+	require.EqualValues(genBlocks+2, second.Number)
+	require.Equal(first.Hash, second.ParentHash)
+	require.Greater(second.Timestamp, first.Timestamp)
+
+	require.Len(first.Calls, 2)
+	for _, call := range first.Calls {
+		require.Nil(call.Error)
+		require.EqualValues(types.ReceiptStatusSuccessful, call.Status)
+	}
+	require.Empty(first.Calls[0].Logs)
+	require.Len(first.Calls[1].Logs, 1)
+	log := first.Calls[1].Logs[0]
+	require.Equal(logger, log.Address)
+	require.Equal([]byte{0}, log.Data)
+	require.Equal(first.Hash, log.BlockHash)
+	require.EqualValues(first.Number, log.BlockNumber)
+	require.EqualValues(first.Calls[0].GasUsed+first.Calls[1].GasUsed, first.GasUsed)
+
+	require.Nil(second.Calls[0].Error)
+	require.EqualValues(params.TxGas, second.Calls[0].GasUsed)
+	require.NotNil(second.Calls[1].Error)
+	require.Equal(simErrCodeNotAllowed, second.Calls[1].Error.Code)
+	require.EqualValues(params.TxGas, second.GasUsed)
+
+	// Identical calls from different senders have their own logs.
+	gas := hexutil.Uint64(100_000)
+	results, err = api.SimulateV1(context.Background(), SimulateOpts{
+		BlockStateCalls: []SimulateBlock{{
+			StateOverrides: &StateOverride{
+				logger: {Code: hex2Bytes("60016000a0")},
+			},
+			Calls: []TransactionArgs{
+				{From: &accounts[0].addr, To: &logger, Gas: &gas},
+				{From: &accounts[1].addr, To: &logger, Gas: &gas},
+			},
+		}},
+	}, &latest)
+	require.NoError(err)
+	calls := results[0].Calls
+	require.Len(calls[0].Logs, 1)
+	require.Len(calls[1].Logs, 1)
+	require.NotEqual(calls[0].Logs[0].TxHash, calls[1].Logs[0].TxHash)
+	require.EqualValues(1, calls[1].Logs[0].Index)
+
+	// With validation the nonce and fees are checked.
+	badNonce := hexutil.Uint64(5)
+	results, err = api.SimulateV1(context.Background(), SimulateOpts{
+		Validation: true,
+		BlockStateCalls: []SimulateBlock{{
+			Calls: []TransactionArgs{
+				{From: &accounts[0].addr, To: &accounts[1].addr, Value: (*hexutil.Big)(big.NewInt(1000))},
+				{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &badNonce},
+				{From: &accounts[1].addr, To: &accounts[0].addr, MaxFeePerGas: (*hexutil.Big)(common.Big1)},
+			},
+		}},
+	}, &latest)
+	require.NoError(err)
+	calls = results[0].Calls
+	require.Nil(calls[0].Error)
+	require.NotNil(calls[1].Error)
+	require.Equal(simErrCodeInvalidTx, calls[1].Error.Code)
+	require.Contains(calls[1].Error.Message, core.ErrNonceTooHigh.Error())
+	require.NotNil(calls[2].Error)
+	require.Contains(calls[2].Error.Message, core.ErrFeeCapTooLow.Error())
+
+	// The gas bought by a call failing before execution is returned to the block.
+	gasLimit, createGas, transferGas := hexutil.Uint64(500_000), hexutil.Uint64(300_000), hexutil.Uint64(300_000)
+	initCode := make(hexutil.Bytes, params.MaxInitCodeSize+1)
+	results, err = api.SimulateV1(context.Background(), SimulateOpts{
+		BlockStateCalls: []SimulateBlock{{
+			BlockOverrides: &BlockOverrides{GasLimit: &gasLimit},
+			Calls: []TransactionArgs{
+				{From: &accounts[0].addr, Gas: &createGas, Input: &initCode},
+				{From: &accounts[0].addr, To: &accounts[1].addr, Gas: &transferGas},
+			},
+		}},
+	}, &latest)
+	require.NoError(err)
+	calls = results[0].Calls
+	require.NotNil(calls[0].Error)
+	require.Contains(calls[0].Error.Message, vmerrs.ErrMaxInitCodeSizeExceeded.Error())
+	require.Nil(calls[1].Error)
+	require.EqualValues(params.TxGas, results[0].GasUsed)
+
+	// Block numbers must increase.
+	number := hexutil.Big(*big.NewInt(1))
+	_, err = api.SimulateV1(context.Background(), SimulateOpts{
+		BlockStateCalls: []SimulateBlock{{BlockOverrides: &BlockOverrides{Number: &number}}},
+	}, &latest)
+	require.ErrorIs(err, errSimulateBlockNumber)
+}
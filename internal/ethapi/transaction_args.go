@@ -33,6 +33,7 @@ import (
 	"fmt"
 	"math/big"
 
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -40,7 +41,10 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core"
 	"github.com/shubhamdubey02/subnet-evm/core/types"
 	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
 	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/utils"
 )
 
 // TransactionArgs represents the arguments to construct a new transaction
@@ -64,6 +68,11 @@ type TransactionArgs struct {
 	// Introduced by AccessListTxType transaction.
 	AccessList *types.AccessList `json:"accessList,omitempty"`
 	ChainID    *hexutil.Big      `json:"chainId,omitempty"`
+
+	// Signed warp messages to be delivered with the transaction. Each message
+	// is packed into a predicate entry of the warp precompile in the access
+	// list, so it can be read with getVerifiedWarpMessage.
+	WarpMessages []hexutil.Bytes `json:"warpMessages,omitempty"`
 }
 
 // from retrieves the transaction sender address.
@@ -85,8 +94,35 @@ func (args *TransactionArgs) data() []byte {
 	return nil
 }
 
+// setPredicateDefaults moves the warp messages of the transaction into its
+// access list as predicates of the warp precompile.
+func (args *TransactionArgs) setPredicateDefaults() error {
+	if len(args.WarpMessages) == 0 {
+		return nil
+	}
+	var al types.AccessList
+	if args.AccessList != nil {
+		al = append(al, *args.AccessList...)
+	}
+	for i, msg := range args.WarpMessages {
+		if _, err := avalancheWarp.ParseMessage(msg); err != nil {
+			return fmt.Errorf("invalid warp message %d: %w", i, err)
+		}
+		al = append(al, types.AccessTuple{
+			Address:     warp.ContractAddress,
+			StorageKeys: utils.BytesToHashSlice(predicate.PackPredicate(msg)),
+		})
+	}
+	args.AccessList = &al
+	args.WarpMessages = nil
+	return nil
+}
+
 // setDefaults fills in default values for unspecified tx fields.
 func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend) error {
+	if err := args.setPredicateDefaults(); err != nil {
+		return err
+	}
 	if err := args.setFeeDefaults(ctx, b); err != nil {
 		return err
 	}
@@ -223,6 +259,9 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
 		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
 	}
+	if err := args.setPredicateDefaults(); err != nil {
+		return nil, err
+	}
 	// Set sender address or use zero address if none specified.
 	addr := args.from()
 
@@ -0,0 +1,191 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/shubhamdubey02/subnet-evm/core/state"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/precompile/modules"
+	"github.com/shubhamdubey02/subnet-evm/precompile/precompileconfig"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+)
+
+// UpgradeResult is an upgrade of the chain along with the block it activated at.
+type UpgradeResult struct {
+	params.UpgradeTimelineEntry
+	// Activated is true if the upgrade is active at the last accepted block.
+	Activated bool `json:"activated"`
+	// ActivationBlock is the number of the first block whose timestamp is at or
+	// after the timestamp of the upgrade. It is nil for upgrades that are not
+	// activated yet.
+	ActivationBlock *hexutil.Uint64 `json:"activationBlock,omitempty"`
+}
+
+// GetUpgradeTimeline returns the network, precompile and state upgrades of the
+// chain ordered by activation timestamp, along with the blocks they activated at.
+// Only accepted blocks are searched, as the headers of the blocks after the last
+// accepted block cannot be read.
+func (s *BlockChainAPI) GetUpgradeTimeline(ctx context.Context) ([]UpgradeResult, error) {
+	var (
+		current  = s.b.LastAcceptedBlock().Header()
+		timeline = s.b.ChainConfig().UpgradeTimeline()
+		results  = make([]UpgradeResult, 0, len(timeline))
+	)
+	for _, upgrade := range timeline {
+		result := UpgradeResult{
+			UpgradeTimelineEntry: upgrade,
+			Activated:            upgrade.Timestamp <= current.Time,
+		}
+		if result.Activated {
+			number, err := s.firstBlockAt(ctx, upgrade.Timestamp, current.Number.Uint64())
+			if err != nil {
+				return nil, err
+			}
+			result.ActivationBlock = (*hexutil.Uint64)(&number)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// firstBlockAt returns the number of the first block up to [last] whose
+// timestamp is at or after [timestamp].
+func (s *BlockChainAPI) firstBlockAt(ctx context.Context, timestamp uint64, last uint64) (uint64, error) {
+	var searchErr error
+	number := sort.Search(int(last)+1, func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		header, err := s.b.HeaderByNumber(ctx, rpc.BlockNumber(i))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		if header == nil {
+			searchErr = fmt.Errorf("header %d not found", i)
+			return true
+		}
+		return header.Time >= timestamp
+	})
+	return uint64(number), searchErr
+}
+
+// PrecompileState is the config of an enabled precompile along with its
+// state decoded from the precompile storage.
+type PrecompileState struct {
+	Address common.Address          `json:"address"`
+	Config  precompileconfig.Config `json:"config"`
+	// Roles counts the addresses holding each role in the allow list of the
+	// precompile, if it has one.
+	Roles *allowlist.RoleCounts `json:"roles,omitempty"`
+	// FeeConfig is the fee config set by the FeeManager precompile.
+	FeeConfig *FeeConfigResult `json:"feeConfig,omitempty"`
+	// RewardAddress is the reward address set by the RewardManager precompile.
+	// It is nil if fee recipients are allowed.
+	RewardAddress      *common.Address `json:"rewardAddress,omitempty"`
+	AllowFeeRecipients *bool           `json:"allowFeeRecipients,omitempty"`
+	// QuorumNumerator is the quorum numerator used to verify warp messages.
+	QuorumNumerator *hexutil.Uint64 `json:"quorumNumerator,omitempty"`
+}
+
+// GetPrecompileStateAt returns the configs and the decoded state of the
+// precompiles enabled at the given block, keyed by precompile config key.
+func (s *BlockChainAPI) GetPrecompileStateAt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (map[string]*PrecompileState, error) {
+	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*PrecompileState)
+	for key, config := range s.b.ChainConfig().EnabledStatefulPrecompiles(header.Time) {
+		module, ok := modules.GetPrecompileModule(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown precompile config: %s", key)
+		}
+		result := &PrecompileState{
+			Address: module.Address,
+			Config:  config,
+		}
+		switch config := config.(type) {
+		case *deployerallowlist.Config, *txallowlist.Config, *nativeminter.Config:
+			result.Roles, err = countRoles(statedb, module.Address, nil)
+		case *feemanager.Config:
+			result.Roles, err = countRoles(statedb, module.Address, feemanager.StorageSlots())
+			if err != nil {
+				return nil, err
+			}
+			feeConfig, lastChangedAt, err := s.b.GetFeeConfigAt(header)
+			if err != nil {
+				return nil, err
+			}
+			result.FeeConfig = &FeeConfigResult{FeeConfig: feeConfig, LastChangedAt: lastChangedAt}
+		case *rewardmanager.Config:
+			result.Roles, err = countRoles(statedb, module.Address, rewardmanager.StorageSlots())
+			rewardAddress, allowFeeRecipients := rewardmanager.GetStoredRewardAddress(statedb)
+			if !allowFeeRecipients {
+				result.RewardAddress = &rewardAddress
+			}
+			result.AllowFeeRecipients = &allowFeeRecipients
+		case *warp.Config:
+			quorumNumerator := config.QuorumNumerator
+			if quorumNumerator == 0 {
+				quorumNumerator = warp.WarpDefaultQuorumNumerator
+			}
+			result.QuorumNumerator = (*hexutil.Uint64)(&quorumNumerator)
+		}
+		if err != nil {
+			return nil, err
+		}
+		results[key] = result
+	}
+	return results, nil
+}
+
+// countRoles counts the roles in the allow list stored at [address], skipping
+// the storage slots in [otherSlots] that the precompile uses for other state.
+// Storage keys are hashed in the trie, so the slots of the allow list are
+// recognized by their values.
+func countRoles(statedb *state.StateDB, address common.Address, otherSlots []common.Hash) (*allowlist.RoleCounts, error) {
+	counts := new(allowlist.RoleCounts)
+	tr, err := statedb.StorageTrie(address)
+	if tr == nil || err != nil {
+		return counts, err
+	}
+	skip := make(map[common.Hash]struct{}, len(otherSlots))
+	for _, slot := range otherSlots {
+		skip[crypto.Keccak256Hash(slot.Bytes())] = struct{}{}
+	}
+	nodeIt, err := tr.NodeIterator(nil)
+	if err != nil {
+		return nil, err
+	}
+	it := trie.NewIterator(nodeIt)
+	for it.Next() {
+		if _, ok := skip[common.BytesToHash(it.Key)]; ok {
+			continue
+		}
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		counts.Add(common.BytesToHash(content))
+	}
+	return counts, it.Err
+}
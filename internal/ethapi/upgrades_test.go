@@ -0,0 +1,99 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeTimelineAndPrecompileState(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		accounts      = newAccounts(3)
+		config        = *params.TestChainConfig
+		rewardAddress = common.Address{1}
+	)
+	feeConfig := params.DefaultFeeConfig
+	feeConfig.TargetBlockRate = 2 // stored in a slot of the fee manager holding the value of the admin role
+	config.GenesisPrecompiles = params.Precompiles{
+		feemanager.ConfigKey: feemanager.NewConfig(utils.NewUint64(0), []common.Address{accounts[0].addr}, []common.Address{accounts[1].addr, accounts[2].addr}, nil, &feeConfig),
+		rewardmanager.ConfigKey: rewardmanager.NewConfig(utils.NewUint64(0), nil, nil, []common.Address{accounts[0].addr}, &rewardmanager.InitialRewardConfig{
+			RewardAddress: rewardAddress,
+		}),
+	}
+	// Blocks are generated 10 seconds apart.
+	config.UpgradeConfig = params.UpgradeConfig{
+		PrecompileUpgrades: []params.PrecompileUpgrade{
+			{Config: warp.NewConfig(utils.NewUint64(15), 0)},
+			{Config: rewardmanager.NewDisableConfig(utils.NewUint64(1000))},
+		},
+	}
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, 3, genesis, func(i int, b *core.BlockGen) {}))
+
+	timeline, err := api.GetUpgradeTimeline(context.Background())
+	require.NoError(err)
+	type entry struct {
+		name            string
+		activated       bool
+		activationBlock *hexutil.Uint64
+	}
+	var entries []entry
+	for _, upgrade := range timeline {
+		entries = append(entries, entry{upgrade.Name, upgrade.Activated, upgrade.ActivationBlock})
+	}
+	block := func(n hexutil.Uint64) *hexutil.Uint64 { return &n }
+	require.Equal([]entry{
+		{"subnetEVM", true, block(0)},
+		{"durango", true, block(0)},
+		{feemanager.ConfigKey, true, block(0)},
+		{rewardmanager.ConfigKey, true, block(0)},
+		{warp.ConfigKey, true, block(2)},
+		{rewardmanager.ConfigKey, false, nil},
+	}, entries)
+
+	// Warp is not enabled yet at block 1.
+	states, err := api.GetPrecompileStateAt(context.Background(), rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(err)
+	require.Len(states, 2)
+	require.NotContains(states, warp.ConfigKey)
+
+	states, err = api.GetPrecompileStateAt(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(err)
+	require.Len(states, 3)
+
+	feeManagerState := states[feemanager.ConfigKey]
+	require.Equal(feemanager.ContractAddress, feeManagerState.Address)
+	require.Equal(&allowlist.RoleCounts{Admin: 1, Enabled: 2}, feeManagerState.Roles)
+	require.Equal(feeConfig.TargetBlockRate, feeManagerState.FeeConfig.FeeConfig.TargetBlockRate)
+
+	rewardManagerState := states[rewardmanager.ConfigKey]
+	require.Equal(&allowlist.RoleCounts{Manager: 1}, rewardManagerState.Roles)
+	require.Equal(&rewardAddress, rewardManagerState.RewardAddress)
+	require.False(*rewardManagerState.AllowFeeRecipients)
+
+	warpState := states[warp.ConfigKey]
+	require.Nil(warpState.Roles)
+	require.Equal(hexutil.Uint64(warp.WarpDefaultQuorumNumerator), *warpState.QuorumNumerator)
+}
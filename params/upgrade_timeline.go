@@ -0,0 +1,92 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kinds of the upgrades in an upgrade timeline.
+const (
+	NetworkUpgradeKind    = "network"
+	PrecompileUpgradeKind = "precompile"
+	StateUpgradeKind      = "state"
+)
+
+// UpgradeTimelineEntry is an upgrade of the chain activating at a block timestamp.
+type UpgradeTimelineEntry struct {
+	Kind string `json:"kind"`
+	// Name is the name of the network upgrade, or the config key of the
+	// precompile for precompile upgrades.
+	Name      string `json:"name"`
+	Timestamp uint64 `json:"timestamp"`
+	// Disable is true if the precompile upgrade disables the precompile.
+	Disable bool `json:"disable,omitempty"`
+	// Config is the precompile config of precompile upgrades, or the state
+	// upgrade of state upgrades.
+	Config interface{} `json:"config,omitempty"`
+}
+
+// UpgradeTimeline returns the scheduled network upgrades, the genesis and
+// upgrade precompile configs and the state upgrades of [c], ordered by
+// activation timestamp. Upgrades activating at the same timestamp are ordered
+// as they are applied: network upgrades, then precompile upgrades, then state
+// upgrades.
+func (c *ChainConfig) UpgradeTimeline() []UpgradeTimelineEntry {
+	var timeline []UpgradeTimelineEntry
+	forks := append(c.NetworkUpgrades.forkOrder(), fork{name: "cancunTime", timestamp: c.CancunTime})
+	for _, fork := range forks {
+		if fork.timestamp == nil {
+			continue
+		}
+		timeline = append(timeline, UpgradeTimelineEntry{
+			Kind:      NetworkUpgradeKind,
+			Name:      strings.TrimSuffix(strings.TrimSuffix(fork.name, "Timestamp"), "Time"),
+			Timestamp: *fork.timestamp,
+		})
+	}
+
+	genesisKeys := make([]string, 0, len(c.GenesisPrecompiles))
+	for key := range c.GenesisPrecompiles {
+		genesisKeys = append(genesisKeys, key)
+	}
+	sort.Strings(genesisKeys)
+	for _, key := range genesisKeys {
+		timeline = append(timeline, precompileTimelineEntry(PrecompileUpgrade{c.GenesisPrecompiles[key]}))
+	}
+	for _, upgrade := range c.PrecompileUpgrades {
+		timeline = append(timeline, precompileTimelineEntry(upgrade))
+	}
+
+	for i := range c.StateUpgrades {
+		upgrade := c.StateUpgrades[i]
+		timeline = append(timeline, UpgradeTimelineEntry{
+			Kind:      StateUpgradeKind,
+			Name:      StateUpgradeKind,
+			Timestamp: *upgrade.BlockTimestamp,
+			Config:    &upgrade,
+		})
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp < timeline[j].Timestamp
+	})
+	return timeline
+}
+
+// precompileTimelineEntry returns the timeline entry of [upgrade].
+func precompileTimelineEntry(upgrade PrecompileUpgrade) UpgradeTimelineEntry {
+	var timestamp uint64
+	if upgrade.Timestamp() != nil {
+		timestamp = *upgrade.Timestamp()
+	}
+	return UpgradeTimelineEntry{
+		Kind:      PrecompileUpgradeKind,
+		Name:      upgrade.Key(),
+		Timestamp: timestamp,
+		Disable:   upgrade.IsDisabled(),
+		Config:    upgrade.Config,
+	}
+}
@@ -0,0 +1,52 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeTimeline(t *testing.T) {
+	admins := []common.Address{{1}}
+	config := *TestChainConfig
+	config.DurangoTimestamp = utils.NewUint64(10)
+	config.GenesisPrecompiles = Precompiles{
+		txallowlist.ConfigKey:       txallowlist.NewConfig(utils.NewUint64(0), admins, nil, nil),
+		deployerallowlist.ConfigKey: deployerallowlist.NewConfig(utils.NewUint64(0), admins, nil, nil),
+	}
+	stateUpgrade := StateUpgrade{BlockTimestamp: utils.NewUint64(10)}
+	config.UpgradeConfig = UpgradeConfig{
+		StateUpgrades: []StateUpgrade{stateUpgrade},
+		PrecompileUpgrades: []PrecompileUpgrade{
+			{txallowlist.NewDisableConfig(utils.NewUint64(20))},
+			{deployerallowlist.NewConfig(utils.NewUint64(10), admins, nil, nil)},
+		},
+	}
+
+	type entry struct {
+		kind      string
+		name      string
+		timestamp uint64
+		disable   bool
+	}
+	var timeline []entry
+	for _, upgrade := range config.UpgradeTimeline() {
+		timeline = append(timeline, entry{upgrade.Kind, upgrade.Name, upgrade.Timestamp, upgrade.Disable})
+	}
+	require.Equal(t, []entry{
+		{NetworkUpgradeKind, "subnetEVM", 0, false},
+		{PrecompileUpgradeKind, deployerallowlist.ConfigKey, 0, false},
+		{PrecompileUpgradeKind, txallowlist.ConfigKey, 0, false},
+		{NetworkUpgradeKind, "durango", 10, false},
+		{PrecompileUpgradeKind, deployerallowlist.ConfigKey, 10, false},
+		{StateUpgradeKind, StateUpgradeKind, 10, false},
+		{PrecompileUpgradeKind, txallowlist.ConfigKey, 20, true},
+	}, timeline)
+}
@@ -86,6 +86,7 @@ type ConfigReply struct {
 }
 
 func (p *Admin) GetVMConfig(_ *http.Request, _ *struct{}, reply *ConfigReply) error {
-	reply.Config = &p.vm.config
+	config := p.vm.config.Redacted()
+	reply.Config = &config
 	return nil
 }
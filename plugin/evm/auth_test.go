@@ -0,0 +1,50 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenAuthHandler(t *testing.T) {
+	handler := newTokenAuthHandler("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := map[string]struct {
+		authorization  string
+		expectedStatus int
+	}{
+		"valid token": {
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusOK,
+		},
+		"invalid token": {
+			authorization:  "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"missing scheme": {
+			authorization:  "secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"missing header": {
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/warp-admin", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, test.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -72,6 +72,7 @@ func (b *Block) Accept(context.Context) error {
 	if err := b.handlePrecompileAccept(rules, sharedMemoryWriter); err != nil {
 		return err
 	}
+	b.indexWarpMessages()
 	if err := vm.blockChain.Accept(b.ethBlock); err != nil {
 		return fmt.Errorf("chain could not accept %s: %w", b.ID(), err)
 	}
@@ -111,9 +112,10 @@ func (b *Block) handlePrecompileAccept(rules params.Rules, sharedMemoryWriter *s
 		return fmt.Errorf("failed to fetch receipts for accepted block with non-empty root hash (%s) (Block: %s, Height: %d)", b.ethBlock.ReceiptHash(), b.ethBlock.Hash(), b.ethBlock.NumberU64())
 	}
 	acceptCtx := &precompileconfig.AcceptContext{
-		SnowCtx:      b.vm.ctx,
-		SharedMemory: sharedMemoryWriter,
-		Warp:         b.vm.warpBackend,
+		SnowCtx:        b.vm.ctx,
+		SharedMemory:   sharedMemoryWriter,
+		Warp:           b.vm.warpBackend,
+		BlockTimestamp: b.ethBlock.Time(),
 	}
 	for _, receipt := range receipts {
 		for logIdx, log := range receipt.Logs {
@@ -130,6 +132,25 @@ func (b *Block) handlePrecompileAccept(rules params.Rules, sharedMemoryWriter *s
 	return nil
 }
 
+// indexWarpMessages adds the warp messages sent and delivered in the block to
+// the warp message index if it is enabled. The index is written to the vm's
+// versionDB so it is committed atomically with the block being accepted. The
+// index is secondary data, so failing to update it is logged and does not
+// prevent the block from being accepted.
+func (b *Block) indexWarpMessages() {
+	if b.vm.warpIndexer == nil {
+		return
+	}
+	receipts := rawdb.ReadReceipts(b.vm.chaindb, b.ethBlock.Hash(), b.ethBlock.NumberU64(), b.ethBlock.Time(), b.vm.chainConfig)
+	if len(receipts) == 0 && b.ethBlock.ReceiptHash() != types.EmptyRootHash {
+		log.Error("Failed to fetch receipts to index warp messages", "block", b.ID(), "height", b.Height())
+		return
+	}
+	if err := b.vm.warpIndexer.IndexBlock(b.ethBlock, receipts); err != nil {
+		log.Error("Failed to index warp messages", "block", b.ID(), "height", b.Height(), "err", err)
+	}
+}
+
 // Reject implements the snowman.Block interface
 func (b *Block) Reject(context.Context) error {
 	b.status = choices.Rejected
@@ -228,7 +249,7 @@ func (b *Block) verify(predicateContext *precompileconfig.PredicateContext, writ
 	// If the chain is still bootstrapping, we can assume that all blocks we are verifying have
 	// been accepted by the network (so the predicate was validated by the network when the
 	// block was originally verified).
-	if b.vm.bootstrapped {
+	if b.vm.bootstrapped.Get() {
 		if err := b.verifyPredicates(predicateContext); err != nil {
 			return fmt.Errorf("failed to verify predicates: %w", err)
 		}
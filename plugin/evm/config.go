@@ -12,6 +12,8 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/shubhamdubey02/subnet-evm/core/txpool/legacypool"
 	"github.com/shubhamdubey02/subnet-evm/eth"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	"github.com/shubhamdubey02/subnet-evm/warp/relayer"
 	"github.com/spf13/cast"
 )
 
@@ -51,6 +53,14 @@ const (
 	defaultPopulateMissingTriesParallelism            = 1024
 	defaultStateSyncServerTrieCache                   = 64 // MB
 	defaultAcceptedCacheSize                          = 32 // blocks
+	defaultWarpPruningInterval                        = time.Minute
+	defaultHealthAcceptorQueueThreshold               = .9
+	defaultHealthMaxBlockDelayMultiplier              = 60 // 2 minutes with a 2s block target
+	defaultHealthTxPoolThreshold                      = .95
+	defaultHealthMinFreeDiskSpace              uint64 = 1 << 30 // 1 GiB
+	defaultRPCRateLimitMaxStored                      = 1000
+	defaultRPCRateLimitDefaultCost                    = 1
+	defaultRPCRateLimitMaxClients                     = 10_000
 
 	// defaultStateSyncMinBlocks is the minimum number of blocks the blockchain
 	// should be ahead of local last accepted to perform state sync.
@@ -61,6 +71,9 @@ const (
 	// - state sync time: ~6 hrs.
 	defaultStateSyncMinBlocks   = 300_000
 	defaultStateSyncRequestSize = 1024 // the number of key/values to ask peers for per request
+
+	// redacted replaces the secrets of the config when it is logged or served.
+	redacted = "<redacted>"
 )
 
 var (
@@ -73,6 +86,13 @@ var (
 		"internal-blockchain",
 		"internal-transaction",
 	}
+	defaultRPCRateLimitMethodCosts = []RPCMethodCost{
+		{Method: "eth_getLogs", Cost: 10, CostPerBlock: 0.1},
+		{Method: "debug_trace*", Cost: 100},
+		{Method: "eth_call", Cost: 5},
+		{Method: "eth_estimateGas", Cost: 5},
+	}
+
 	defaultAllowUnprotectedTxHashes = []common.Hash{
 		common.HexToHash("0xfefb2da535e927b85fe68eb81cb2e4a5827c905f78381a01ef2322aa9b0aee8e"), // EIP-1820: https://eips.ethereum.org/EIPS/eip-1820
 	}
@@ -82,6 +102,33 @@ type Duration struct {
 	time.Duration
 }
 
+// RPCAccessTier sets the methods the clients of a tier may call and the
+// budgets of each client of the tier (see [rpc.AccessTier]).
+type RPCAccessTier struct {
+	Name              string   `json:"name"`
+	AllowedMethods    []string `json:"allowed-methods,omitempty"` // Method patterns, e.g. "eth_*". All methods are allowed if empty
+	DeniedMethods     []string `json:"denied-methods,omitempty"`  // Method patterns denied even if allowed, e.g. "debug_*"
+	RequestsPerSecond float64  `json:"requests-per-second"`       // Refill rate of the request budget (0 disables)
+	RequestBurst      int      `json:"request-burst"`             // Maximum requests stored in the request budget
+	CPURefillRate     Duration `json:"cpu-refill-rate"`           // CPU time per second refilling the CPU budget (0 disables)
+	CPUMaxStored      Duration `json:"cpu-max-stored"`            // Maximum CPU time stored in the CPU budget
+}
+
+// RPCAPIKey authenticates a client of an [RPCAccessTier].
+type RPCAPIKey struct {
+	Name string `json:"name"` // Identifies the client in logs and metrics
+	Key  string `json:"key"`
+	Tier string `json:"tier"`
+}
+
+// RPCMethodCost sets the cost of the calls to the methods matching a pattern
+// (see [rpc.MethodCost]).
+type RPCMethodCost struct {
+	Method       string  `json:"method"`         // Method name or prefix followed by "*", e.g. "debug_trace*"
+	Cost         float64 `json:"cost"`           // Cost of each call
+	CostPerBlock float64 `json:"cost-per-block"` // Cost added per block in the range of the filter of a call, as in eth_getLogs
+}
+
 // Config ...
 type Config struct {
 	// Airdrop
@@ -92,6 +139,10 @@ type Config struct {
 	AdminAPIEnabled   bool   `json:"admin-api-enabled"`
 	AdminAPIDir       string `json:"admin-api-dir"`
 	WarpAPIEnabled    bool   `json:"warp-api-enabled"`
+	// WarpIndexEnabled indexes the warp messages sent and delivered in blocks
+	// accepted from then on, and serves them through warp_getMessages. Only
+	// deliveries to this chain are observed.
+	WarpIndexEnabled bool `json:"warp-index-enabled"`
 
 	// EnabledEthAPIs is a list of Ethereum services that should be enabled
 	// If none is specified, then we use the default list [defaultEnabledAPIs]
@@ -119,17 +170,27 @@ type Config struct {
 	SnapshotVerify bool `json:"snapshot-verification-enabled"`
 
 	// Pruning Settings
-	Pruning                         bool    `json:"pruning-enabled"`                    // If enabled, trie roots are only persisted every 4096 blocks
-	AcceptorQueueLimit              int     `json:"accepted-queue-limit"`               // Maximum blocks to queue before blocking during acceptance
-	CommitInterval                  uint64  `json:"commit-interval"`                    // Specifies the commit interval at which to persist EVM and atomic tries.
-	AllowMissingTries               bool    `json:"allow-missing-tries"`                // If enabled, warnings preventing an incomplete trie index are suppressed
-	PopulateMissingTries            *uint64 `json:"populate-missing-tries,omitempty"`   // Sets the starting point for re-populating missing tries. Disables re-generation if nil.
-	PopulateMissingTriesParallelism int     `json:"populate-missing-tries-parallelism"` // Number of concurrent readers to use when re-populating missing tries on startup.
-	PruneWarpDB                     bool    `json:"prune-warp-db-enabled"`              // Determines if the warpDB should be cleared on startup
+	Pruning                         bool     `json:"pruning-enabled"`                    // If enabled, trie roots are only persisted every 4096 blocks
+	AcceptorQueueLimit              int      `json:"accepted-queue-limit"`               // Maximum blocks to queue before blocking during acceptance
+	CommitInterval                  uint64   `json:"commit-interval"`                    // Specifies the commit interval at which to persist EVM and atomic tries.
+	AllowMissingTries               bool     `json:"allow-missing-tries"`                // If enabled, warnings preventing an incomplete trie index are suppressed
+	PopulateMissingTries            *uint64  `json:"populate-missing-tries,omitempty"`   // Sets the starting point for re-populating missing tries. Disables re-generation if nil.
+	PopulateMissingTriesParallelism int      `json:"populate-missing-tries-parallelism"` // Number of concurrent readers to use when re-populating missing tries on startup.
+	PruneWarpDB                     bool     `json:"prune-warp-db-enabled"`              // Determines if the warpDB should be cleared on startup
+	WarpRetentionBlocks             uint64   `json:"warp-retention-blocks"`              // Prunes warp messages accepted this many blocks before the last accepted block (0 disables)
+	WarpRetentionPeriod             Duration `json:"warp-retention-period"`              // Prunes warp messages accepted this long ago (0 disables)
+	WarpPruneDelivered              bool     `json:"warp-prune-delivered"`               // Prunes warp messages once their delivery is observed on this chain (requires warp-index-enabled)
+	WarpPruningInterval             Duration `json:"warp-pruning-interval"`              // Interval between warp database pruning passes
 
 	// Metric Settings
 	MetricsExpensiveEnabled bool `json:"metrics-expensive-enabled"` // Debug-level metrics that might impact runtime performance
 
+	// Health Check Settings
+	HealthAcceptorQueueThreshold  float64 `json:"health-acceptor-queue-threshold"`   // Share of accepted-queue-limit the acceptor queue may fill before reporting unhealthy (0 disables)
+	HealthMaxBlockDelayMultiplier uint64  `json:"health-max-block-delay-multiplier"` // Number of target block rates the last accepted block may age while transactions are pending (0 disables)
+	HealthTxPoolThreshold         float64 `json:"health-txpool-threshold"`           // Share of the txpool global slots and queue that may fill before reporting unhealthy (0 disables)
+	HealthMinFreeDiskSpace        uint64  `json:"health-min-free-disk-space"`        // Minimum free bytes on the chain data disk before reporting unhealthy (0 disables)
+
 	// API Settings
 	LocalTxsEnabled bool `json:"local-txs-enabled"`
 
@@ -148,6 +209,20 @@ type Config struct {
 	AllowUnfinalizedQueries  bool          `json:"allow-unfinalized-queries"`
 	AllowUnprotectedTxs      bool          `json:"allow-unprotected-txs"`
 	AllowUnprotectedTxHashes []common.Hash `json:"allow-unprotected-tx-hashes"`
+	TraceOutputDir           string        `json:"trace-output-dir"` // Directory debug_traceBlockRangeToFiles writes under. Defaults to the temporary directory
+
+	// RPC Authentication Settings
+	RPCAuthTiers         []RPCAccessTier `json:"rpc-auth-tiers"`          // Access tiers of the clients of the eth RPC and WS endpoints
+	RPCAuthKeys          []RPCAPIKey     `json:"rpc-auth-keys"`           // API keys of the clients
+	RPCAuthJWTSecret     hexutil.Bytes   `json:"rpc-auth-jwt-secret"`     // HS256 secret of accepted JWTs, whose "sub" claim names the client and "tier" claim sets its tier
+	RPCAuthAnonymousTier string          `json:"rpc-auth-anonymous-tier"` // Tier of requests without credentials. Rejects them if empty
+
+	// RPC Rate Limit Settings
+	RPCRateLimitRefillRate  float64         `json:"rpc-rate-limit-refill-rate"`  // Cost per second refilling the budget of each client IP or authenticated client (0 disables)
+	RPCRateLimitMaxStored   float64         `json:"rpc-rate-limit-max-stored"`   // Maximum cost stored in the budget of a client. Calls costing more are always rejected
+	RPCRateLimitDefaultCost float64         `json:"rpc-rate-limit-default-cost"` // Cost of the calls to methods without a method cost
+	RPCRateLimitMethodCosts []RPCMethodCost `json:"rpc-rate-limit-method-costs"` // Costs of methods. The first matching cost applies
+	RPCRateLimitMaxClients  int             `json:"rpc-rate-limit-max-clients"`  // Maximum number of client budgets tracked
 
 	// Keystore Settings
 	KeystoreDirectory             string `json:"keystore-directory"` // both absolute and relative supported
@@ -222,6 +297,21 @@ type Config struct {
 	// Note: only supports AddressedCall payloads as defined here:
 	// https://github.com/MetalBlockchain/metalgo/tree/7623ffd4be915a5185c9ed5e11fa9be15a6e1f00/vms/platformvm/warp/payload#addressedcall
 	WarpOffChainMessages []hexutil.Bytes `json:"warp-off-chain-messages"`
+
+	// Warp Off-Chain Registry Settings
+	WarpOffChainAdminToken      string           `json:"warp-off-chain-admin-token"`      // Bearer token of the warp admin API registering off-chain messages at runtime (empty disables)
+	WarpOffChainSourceAddresses []common.Address `json:"warp-off-chain-source-addresses"` // Source addresses of the AddressedCall messages that may be registered
+	WarpOffChainMessageTTL      Duration         `json:"warp-off-chain-message-ttl"`      // Default and maximum lifetime of registered off-chain messages (0 disables expiry)
+
+	// Warp Relayer Settings
+	WarpRelayerEnabled            bool                  `json:"warp-relayer-enabled"`             // Enables delivering accepted warp messages to [WarpRelayerDestinations]
+	WarpRelayerPrivateKey         string                `json:"warp-relayer-private-key"`         // Hex encoded key of the account paying for deliveries
+	WarpRelayerPrivateKeyFile     string                `json:"warp-relayer-private-key-file"`    // File holding the hex encoded key, used instead of [WarpRelayerPrivateKey]
+	WarpRelayerDestinations       []relayer.Destination `json:"warp-relayer-destinations"`        // Chains accepted warp messages are delivered to
+	WarpRelayerQuorumNumerator    uint64                `json:"warp-relayer-quorum-numerator"`    // Share of stake that must sign a message before delivery
+	WarpRelayerMaxAttempts        int                   `json:"warp-relayer-max-attempts"`        // Number of delivery attempts before a message is dropped
+	WarpRelayerRetryInterval      Duration              `json:"warp-relayer-retry-interval"`      // Delay before the first retry of a failed delivery
+	WarpRelayerAggregationTimeout Duration              `json:"warp-relayer-aggregation-timeout"` // Time given to validators to sign a message in a delivery attempt
 }
 
 // EthAPIs returns an array of strings representing the Eth APIs that should be enabled
@@ -233,11 +323,49 @@ func (c Config) EthBackendSettings() eth.Settings {
 	return eth.Settings{MaxBlocksPerRequest: c.MaxBlocksPerRequest}
 }
 
+// Redacted returns a copy of the config with its secrets replaced, so it can
+// be logged or served by the admin API.
+func (c Config) Redacted() Config {
+	if c.WarpRelayerPrivateKey != "" {
+		c.WarpRelayerPrivateKey = redacted
+	}
+	if c.WarpOffChainAdminToken != "" {
+		c.WarpOffChainAdminToken = redacted
+	}
+	if len(c.RPCAuthKeys) > 0 {
+		keys := make([]RPCAPIKey, len(c.RPCAuthKeys))
+		for i, key := range c.RPCAuthKeys {
+			key.Key = redacted
+			keys[i] = key
+		}
+		c.RPCAuthKeys = keys
+	}
+	// The JWT secret is hex encoded, so it is removed rather than replaced.
+	c.RPCAuthJWTSecret = nil
+	return c
+}
+
+// String implements the stringer interface, hiding the secrets of the config.
+func (c Config) String() string {
+	// config has the fields of Config without its methods, so formatting it
+	// does not call String again.
+	type config Config
+	return fmt.Sprintf("%+v", config(c.Redacted()))
+}
+
 func (c *Config) SetDefaults() {
 	c.EnabledEthAPIs = defaultEnabledAPIs
 	c.RPCGasCap = defaultRpcGasCap
 	c.RPCTxFeeCap = defaultRpcTxFeeCap
 	c.MetricsExpensiveEnabled = defaultMetricsExpensiveEnabled
+	c.HealthAcceptorQueueThreshold = defaultHealthAcceptorQueueThreshold
+	c.HealthMaxBlockDelayMultiplier = defaultHealthMaxBlockDelayMultiplier
+	c.HealthTxPoolThreshold = defaultHealthTxPoolThreshold
+	c.HealthMinFreeDiskSpace = defaultHealthMinFreeDiskSpace
+	c.RPCRateLimitMaxStored = defaultRPCRateLimitMaxStored
+	c.RPCRateLimitDefaultCost = defaultRPCRateLimitDefaultCost
+	c.RPCRateLimitMethodCosts = defaultRPCRateLimitMethodCosts
+	c.RPCRateLimitMaxClients = defaultRPCRateLimitMaxClients
 
 	c.TxPoolPriceLimit = legacypool.DefaultConfig.PriceLimit
 	c.TxPoolPriceBump = legacypool.DefaultConfig.PriceBump
@@ -282,6 +410,7 @@ func (c *Config) SetDefaults() {
 	c.StateSyncRequestSize = defaultStateSyncRequestSize
 	c.AllowUnprotectedTxHashes = defaultAllowUnprotectedTxHashes
 	c.AcceptedCacheSize = defaultAcceptedCacheSize
+	c.WarpPruningInterval.Duration = defaultWarpPruningInterval
 }
 
 func (d *Duration) UnmarshalJSON(data []byte) (err error) {
@@ -323,5 +452,110 @@ func (c *Config) Validate() error {
 	if c.PushGossipPercentStake < 0 || c.PushGossipPercentStake > 1 {
 		return fmt.Errorf("push-gossip-percent-stake is %f but must be in the range [0, 1]", c.PushGossipPercentStake)
 	}
+	if c.HealthAcceptorQueueThreshold < 0 || c.HealthAcceptorQueueThreshold > 1 {
+		return fmt.Errorf("health-acceptor-queue-threshold is %f but must be in the range [0, 1]", c.HealthAcceptorQueueThreshold)
+	}
+	if c.HealthTxPoolThreshold < 0 || c.HealthTxPoolThreshold > 1 {
+		return fmt.Errorf("health-txpool-threshold is %f but must be in the range [0, 1]", c.HealthTxPoolThreshold)
+	}
+	if c.WarpPruneDelivered && !c.WarpIndexEnabled {
+		return fmt.Errorf("cannot enable warp-prune-delivered without warp-index-enabled")
+	}
+	if c.WarpRetentionBlocks > 0 || c.WarpRetentionPeriod.Duration > 0 || c.WarpPruneDelivered {
+		if c.WarpPruningInterval.Duration <= 0 {
+			return fmt.Errorf("warp-pruning-interval must be positive, found %s", c.WarpPruningInterval)
+		}
+	}
+	if c.WarpOffChainAdminToken != "" && len(c.WarpOffChainSourceAddresses) == 0 {
+		return fmt.Errorf("cannot enable warp-off-chain-admin-token without warp-off-chain-source-addresses")
+	}
+	if c.WarpOffChainMessageTTL.Duration < 0 {
+		return fmt.Errorf("warp-off-chain-message-ttl must be non-negative, found %s", c.WarpOffChainMessageTTL)
+	}
+	if c.WarpRelayerEnabled {
+		if _, err := c.WarpRelayerConfig().Validate(); err != nil {
+			return fmt.Errorf("invalid warp relayer config: %w", err)
+		}
+	}
+	if c.RPCAuthEnabled() {
+		if err := c.RPCAuthConfig().Validate(); err != nil {
+			return fmt.Errorf("invalid rpc auth config: %w", err)
+		}
+	}
+	if c.RPCRateLimitRefillRate != 0 {
+		if err := c.RPCRateLimitConfig().Validate(); err != nil {
+			return fmt.Errorf("invalid rpc rate limit config: %w", err)
+		}
+	}
 	return nil
 }
+
+// RPCRateLimitConfig returns the config of the rate limiting of the clients of
+// the eth RPC and WS endpoints.
+func (c *Config) RPCRateLimitConfig() rpc.RateLimitConfig {
+	costs := make([]rpc.MethodCost, len(c.RPCRateLimitMethodCosts))
+	for i, cost := range c.RPCRateLimitMethodCosts {
+		costs[i] = rpc.MethodCost{
+			Method:       cost.Method,
+			Cost:         cost.Cost,
+			CostPerBlock: cost.CostPerBlock,
+		}
+	}
+	return rpc.RateLimitConfig{
+		Costs:       costs,
+		DefaultCost: c.RPCRateLimitDefaultCost,
+		RefillRate:  c.RPCRateLimitRefillRate,
+		MaxStored:   c.RPCRateLimitMaxStored,
+		MaxClients:  c.RPCRateLimitMaxClients,
+	}
+}
+
+// RPCAuthEnabled returns true if the clients of the eth RPC and WS endpoints
+// must authenticate.
+func (c *Config) RPCAuthEnabled() bool {
+	return len(c.RPCAuthKeys) > 0 || len(c.RPCAuthJWTSecret) > 0 || c.RPCAuthAnonymousTier != ""
+}
+
+// RPCAuthConfig returns the config of the authentication of the clients of
+// the eth RPC and WS endpoints.
+func (c *Config) RPCAuthConfig() rpc.AuthConfig {
+	tiers := make([]rpc.AccessTier, len(c.RPCAuthTiers))
+	for i, tier := range c.RPCAuthTiers {
+		tiers[i] = rpc.AccessTier{
+			Name:              tier.Name,
+			AllowedMethods:    tier.AllowedMethods,
+			DeniedMethods:     tier.DeniedMethods,
+			RequestsPerSecond: tier.RequestsPerSecond,
+			RequestBurst:      tier.RequestBurst,
+			CPURefillRate:     tier.CPURefillRate.Duration,
+			CPUMaxStored:      tier.CPUMaxStored.Duration,
+		}
+	}
+	keys := make([]rpc.APIKey, len(c.RPCAuthKeys))
+	for i, key := range c.RPCAuthKeys {
+		keys[i] = rpc.APIKey{
+			Name: key.Name,
+			Key:  key.Key,
+			Tier: key.Tier,
+		}
+	}
+	return rpc.AuthConfig{
+		Tiers:         tiers,
+		Keys:          keys,
+		JWTSecret:     c.RPCAuthJWTSecret,
+		AnonymousTier: c.RPCAuthAnonymousTier,
+	}
+}
+
+// WarpRelayerConfig returns the config of the warp relayer.
+func (c *Config) WarpRelayerConfig() relayer.Config {
+	return relayer.Config{
+		PrivateKey:         c.WarpRelayerPrivateKey,
+		PrivateKeyFile:     c.WarpRelayerPrivateKeyFile,
+		Destinations:       c.WarpRelayerDestinations,
+		QuorumNumerator:    c.WarpRelayerQuorumNumerator,
+		MaxAttempts:        c.WarpRelayerMaxAttempts,
+		RetryInterval:      c.WarpRelayerRetryInterval.Duration,
+		AggregationTimeout: c.WarpRelayerAggregationTimeout.Duration,
+	}
+}
@@ -105,6 +105,24 @@ func TestUnmarshalConfig(t *testing.T) {
 			Config{AllowUnprotectedTxHashes: []common.Hash{common.HexToHash("0x803351deb6d745e91545a6a3e1c0ea3e9a6a02a1a4193b70edfcd2f40f71a01c")}},
 			false,
 		},
+		{
+			"rpc auth",
+			[]byte(`{"rpc-auth-tiers": [{"name": "free", "denied-methods": ["debug_*"], "requests-per-second": 10, "request-burst": 20, "cpu-refill-rate": "1s", "cpu-max-stored": "10s"}], "rpc-auth-keys": [{"name": "alice", "key": "secret", "tier": "free"}], "rpc-auth-jwt-secret": "0x01", "rpc-auth-anonymous-tier": "free"}`),
+			Config{
+				RPCAuthTiers: []RPCAccessTier{{
+					Name:              "free",
+					DeniedMethods:     []string{"debug_*"},
+					RequestsPerSecond: 10,
+					RequestBurst:      20,
+					CPURefillRate:     Duration{time.Second},
+					CPUMaxStored:      Duration{10 * time.Second},
+				}},
+				RPCAuthKeys:          []RPCAPIKey{{Name: "alice", Key: "secret", Tier: "free"}},
+				RPCAuthJWTSecret:     []byte{1},
+				RPCAuthAnonymousTier: "free",
+			},
+			false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,3 +138,32 @@ func TestUnmarshalConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigRedacted(t *testing.T) {
+	config := Config{
+		WarpRelayerEnabled:     true,
+		WarpRelayerPrivateKey:  "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+		WarpOffChainAdminToken: "admin-token",
+		RPCAuthKeys:            []RPCAPIKey{{Name: "alice", Key: "alice-key", Tier: "free"}},
+		RPCAuthJWTSecret:       []byte("jwt-secret"),
+	}
+	for _, s := range []string{config.String(), fmt.Sprintf("%v", config)} {
+		assert.NotContains(t, s, config.WarpRelayerPrivateKey)
+		assert.NotContains(t, s, config.WarpOffChainAdminToken)
+		assert.NotContains(t, s, config.RPCAuthKeys[0].Key)
+		assert.NotContains(t, s, config.RPCAuthJWTSecret.String())
+		assert.Contains(t, s, "Name:alice")
+		assert.Contains(t, s, "WarpRelayerEnabled:true")
+	}
+	r := config.Redacted()
+	assert.Equal(t, redacted, r.WarpRelayerPrivateKey)
+	assert.Equal(t, redacted, r.WarpOffChainAdminToken)
+	assert.Equal(t, redacted, r.RPCAuthKeys[0].Key)
+	assert.Equal(t, "alice-key", config.RPCAuthKeys[0].Key)
+	assert.Empty(t, r.RPCAuthJWTSecret)
+	assert.NotEqual(t, redacted, config.WarpRelayerPrivateKey)
+
+	// Unset secrets are left empty.
+	assert.Empty(t, Config{}.Redacted().WarpRelayerPrivateKey)
+	assert.Empty(t, Config{}.Redacted().WarpOffChainAdminToken)
+}
@@ -5,9 +5,13 @@ package evm
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
 )
 
 var (
@@ -19,6 +23,31 @@ var (
 // Database implements ethdb.Database
 type Database struct{ database.Database }
 
+// NewChainDatabase returns the chain database stored in [db], the database the
+// node passes to the VM. It is exported for tools opening the database offline.
+func NewChainDatabase(db database.Database) ethdb.Database {
+	// Use NewNested rather than New so that the structure of the database
+	// remains the same regardless of the provided baseDB type.
+	return rawdb.NewDatabase(Database{prefixdb.NewNested(ethDBPrefix, db)})
+}
+
+// ReadLastAcceptedHash reads the hash of the last accepted block from [db], the
+// database the node passes to the VM. It returns an empty hash if no block was
+// accepted after the genesis.
+func ReadLastAcceptedHash(db database.Database) (common.Hash, error) {
+	lastAcceptedBytes, err := prefixdb.New(acceptedPrefix, db).Get(lastAcceptedKey)
+	switch {
+	case err == database.ErrNotFound:
+		return common.Hash{}, nil
+	case err != nil:
+		return common.Hash{}, err
+	case len(lastAcceptedBytes) != common.HashLength:
+		return common.Hash{}, fmt.Errorf("last accepted bytes should have been length %d, but found %d", common.HashLength, len(lastAcceptedBytes))
+	default:
+		return common.BytesToHash(lastAcceptedBytes), nil
+	}
+}
+
 // Stat implements ethdb.Database
 func (db Database) Stat(string) (string, error) { return "", database.ErrNotFound }
 
@@ -3,12 +3,166 @@
 
 package evm
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/utils/storage"
+)
+
+// Names of the checks reported in the details of [VM.HealthCheck]
+const (
+	bootstrappedCheck      = "bootstrapped"
+	acceptorQueueCheck     = "acceptorQueue"
+	lastAcceptedBlockCheck = "lastAcceptedBlock"
+	snapshotCheck          = "snapshot"
+	txPoolCheck            = "txPool"
+	warpDatabaseCheck      = "warpDatabase"
+	diskSpaceCheck         = "diskSpace"
+)
+
+var errUnhealthy = errors.New("unhealthy")
+
+// healthCheckResult is the outcome of a single check of [VM.HealthCheck].
+type healthCheckResult struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message"`
+}
+
+func healthy(format string, args ...interface{}) healthCheckResult {
+	return healthCheckResult{Healthy: true, Message: fmt.Sprintf(format, args...)}
+}
+
+func unhealthy(format string, args ...interface{}) healthCheckResult {
+	return healthCheckResult{Healthy: false, Message: fmt.Sprintf(format, args...)}
+}
 
 // Health returns nil if this chain is healthy.
-// Also returns details, which should be one of:
-// string, []byte, map[string]string
-func (vm *VM) HealthCheck(context.Context) (interface{}, error) {
-	// TODO perform actual health check
-	return nil, nil
+// Also returns details, which is a map from the name of each check to
+// its [healthCheckResult]. The thresholds of the checks are set by the
+// health settings of [Config].
+func (vm *VM) HealthCheck(ctx context.Context) (interface{}, error) {
+	details := map[string]healthCheckResult{
+		bootstrappedCheck:      vm.checkBootstrapped(),
+		acceptorQueueCheck:     vm.checkAcceptorQueue(),
+		lastAcceptedBlockCheck: vm.checkLastAcceptedBlock(),
+		snapshotCheck:          vm.checkSnapshot(),
+		txPoolCheck:            vm.checkTxPool(),
+		warpDatabaseCheck:      vm.checkWarpDatabase(ctx),
+		diskSpaceCheck:         vm.checkDiskSpace(),
+	}
+
+	var failing []string
+	for name, result := range details {
+		if !result.Healthy {
+			failing = append(failing, name)
+		}
+	}
+	if len(failing) == 0 {
+		return details, nil
+	}
+	sort.Strings(failing)
+	return details, fmt.Errorf("%w: failing checks: %s", errUnhealthy, strings.Join(failing, ", "))
+}
+
+// checkBootstrapped reports whether the chain finished state sync and bootstrapping.
+func (vm *VM) checkBootstrapped() healthCheckResult {
+	switch {
+	case vm.stateSyncing.Get():
+		return unhealthy("state sync in progress")
+	case !vm.bootstrapped.Get():
+		return unhealthy("bootstrapping")
+	default:
+		return healthy("bootstrapped")
+	}
+}
+
+// checkAcceptorQueue reports whether the acceptor keeps up with accepted blocks.
+func (vm *VM) checkAcceptorQueue() healthCheckResult {
+	depth := vm.blockChain.AcceptorQueueLength()
+	limit := vm.config.AcceptorQueueLimit
+	threshold := vm.config.HealthAcceptorQueueThreshold
+	if threshold > 0 && limit > 0 && float64(depth) >= threshold*float64(limit) {
+		return unhealthy("acceptor queue holds %d of %d blocks", depth, limit)
+	}
+	return healthy("acceptor queue holds %d of %d blocks", depth, limit)
+}
+
+// checkLastAcceptedBlock reports whether blocks are accepted at the expected rate.
+// Blocks are only built when there are transactions to include, so the age of
+// the last accepted block is only checked while the txpool has pending transactions.
+func (vm *VM) checkLastAcceptedBlock() healthCheckResult {
+	block := vm.blockChain.LastAcceptedBlock()
+	age := vm.clock.Time().Sub(time.Unix(int64(block.Time()), 0))
+	if vm.config.HealthMaxBlockDelayMultiplier == 0 {
+		return healthy("last accepted block %d is %s old", block.NumberU64(), age)
+	}
+
+	feeConfig, _, err := vm.blockChain.GetFeeConfigAt(block.Header())
+	if err != nil {
+		return unhealthy("failed to get fee config at last accepted block %d: %s", block.NumberU64(), err)
+	}
+	maxAge := time.Duration(vm.config.HealthMaxBlockDelayMultiplier*feeConfig.TargetBlockRate) * time.Second
+	pending, _ := vm.txPool.Stats()
+	if pending > 0 && age > maxAge {
+		return unhealthy("last accepted block %d is %s old with %d pending transactions, exceeding %s", block.NumberU64(), age, pending, maxAge)
+	}
+	return healthy("last accepted block %d is %s old", block.NumberU64(), age)
+}
+
+// checkSnapshot reports the snapshot generation status. Generation in progress
+// does not make the chain unhealthy, since state is served from the tries meanwhile.
+func (vm *VM) checkSnapshot() healthCheckResult {
+	snaps := vm.blockChain.Snapshots()
+	if snaps == nil {
+		return healthy("snapshots disabled")
+	}
+	generating, err := snaps.Generating()
+	switch {
+	case err != nil:
+		return unhealthy("failed to get snapshot generation status: %s", err)
+	case generating:
+		return healthy("snapshot generation in progress")
+	default:
+		return healthy("snapshot generated")
+	}
+}
+
+// checkTxPool reports whether the txpool has room for new transactions.
+func (vm *VM) checkTxPool() healthCheckResult {
+	pending, queued := vm.txPool.Stats()
+	capacity := vm.config.TxPoolGlobalSlots + vm.config.TxPoolGlobalQueue
+	threshold := vm.config.HealthTxPoolThreshold
+	if threshold > 0 && capacity > 0 && float64(pending+queued) >= threshold*float64(capacity) {
+		return unhealthy("txpool holds %d pending and %d queued transactions of %d", pending, queued, capacity)
+	}
+	return healthy("txpool holds %d pending and %d queued transactions of %d", pending, queued, capacity)
+}
+
+// checkWarpDatabase reports whether the database of the warp backend is usable.
+func (vm *VM) checkWarpDatabase(ctx context.Context) healthCheckResult {
+	if _, err := vm.warpDB.HealthCheck(ctx); err != nil {
+		return unhealthy("warp database failed health check: %s", err)
+	}
+	return healthy("warp database is healthy")
+}
+
+// checkDiskSpace reports whether the disk holding the chain data has enough free space.
+func (vm *VM) checkDiskSpace() healthCheckResult {
+	dir := vm.ctx.ChainDataDir
+	if dir == "" {
+		return healthy("chain data directory not set")
+	}
+	available, err := storage.AvailableBytes(dir)
+	if err != nil {
+		return unhealthy("failed to get free disk space of %s: %s", dir, err)
+	}
+	if available < vm.config.HealthMinFreeDiskSpace {
+		return unhealthy("%d bytes free on the chain data disk, below %d", available, vm.config.HealthMinFreeDiskSpace)
+	}
+	return healthy("%d bytes free on the chain data disk", available)
 }
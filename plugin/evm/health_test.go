@@ -0,0 +1,93 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck(t *testing.T) {
+	tests := map[string]struct {
+		finishBootstrapping bool
+		configJSON          string
+		chainDataDir        bool
+		addTx               bool
+		expectedFailing     []string
+	}{
+		"bootstrapped": {
+			finishBootstrapping: true,
+			chainDataDir:        true,
+		},
+		"bootstrapping": {
+			finishBootstrapping: false,
+			expectedFailing:     []string{bootstrappedCheck},
+		},
+		"low disk space": {
+			finishBootstrapping: true,
+			configJSON:          `{"health-min-free-disk-space": 18446744073709551615}`,
+			chainDataDir:        true,
+			expectedFailing:     []string{diskSpaceCheck},
+		},
+		"disk space check disabled": {
+			finishBootstrapping: true,
+			configJSON:          `{"health-min-free-disk-space": 0}`,
+			chainDataDir:        true,
+		},
+		"saturated txpool and stale last accepted block": {
+			finishBootstrapping: true,
+			configJSON:          `{"health-txpool-threshold": 0.0001}`,
+			addTx:               true,
+			expectedFailing:     []string{lastAcceptedBlockCheck, txPoolCheck},
+		},
+		"txpool and block delay checks disabled": {
+			finishBootstrapping: true,
+			configJSON:          `{"health-txpool-threshold": 0, "health-max-block-delay-multiplier": 0}`,
+			addTx:               true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			_, vm, _, _ := GenesisVM(t, test.finishBootstrapping, genesisJSONSubnetEVM, test.configJSON, "")
+			defer func() {
+				require.NoError(vm.Shutdown(context.Background()))
+			}()
+
+			if test.chainDataDir {
+				vm.ctx.ChainDataDir = t.TempDir()
+			}
+			if test.addTx {
+				tx := types.NewTransaction(0, testEthAddrs[1], big.NewInt(1), 21000, big.NewInt(testMinGasPrice), nil)
+				signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+				require.NoError(err)
+				for _, err := range vm.txPool.AddRemotesSync([]*types.Transaction{signedTx}) {
+					require.NoError(err)
+				}
+			}
+
+			details, err := vm.HealthCheck(context.Background())
+			results, ok := details.(map[string]healthCheckResult)
+			require.True(ok)
+			var failing []string
+			for name, result := range results {
+				if !result.Healthy {
+					failing = append(failing, name)
+				}
+			}
+			sort.Strings(failing)
+			require.Equal(test.expectedFailing, failing)
+			if len(test.expectedFailing) == 0 {
+				require.NoError(err)
+			} else {
+				require.ErrorIs(err, errUnhealthy)
+			}
+		})
+	}
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core/types"
 	"github.com/shubhamdubey02/subnet-evm/eth"
 	"github.com/shubhamdubey02/subnet-evm/eth/ethconfig"
+	"github.com/shubhamdubey02/subnet-evm/eth/filters"
+	"github.com/shubhamdubey02/subnet-evm/graphql"
 	"github.com/shubhamdubey02/subnet-evm/metrics"
 	subnetEVMPrometheus "github.com/shubhamdubey02/subnet-evm/metrics/prometheus"
 	"github.com/shubhamdubey02/subnet-evm/miner"
@@ -36,12 +38,15 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/params"
 	"github.com/shubhamdubey02/subnet-evm/peer"
 	"github.com/shubhamdubey02/subnet-evm/plugin/evm/message"
+	warpPrecompile "github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
 
 	"github.com/shubhamdubey02/subnet-evm/rpc"
 	statesyncclient "github.com/shubhamdubey02/subnet-evm/sync/client"
 	"github.com/shubhamdubey02/subnet-evm/sync/client/stats"
 	"github.com/shubhamdubey02/subnet-evm/trie"
 	"github.com/shubhamdubey02/subnet-evm/warp"
+	"github.com/shubhamdubey02/subnet-evm/warp/aggregator"
+	"github.com/shubhamdubey02/subnet-evm/warp/relayer"
 	warpValidators "github.com/shubhamdubey02/subnet-evm/warp/validators"
 
 	// Force-load tracer engine to trigger registration
@@ -77,6 +82,7 @@ import (
 	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
 	"github.com/MetalBlockchain/metalgo/utils/units"
 	"github.com/MetalBlockchain/metalgo/vms/components/chain"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
 
 	commonEng "github.com/MetalBlockchain/metalgo/snow/engine/common"
 
@@ -126,16 +132,23 @@ const (
 	adminEndpoint        = "/admin"
 	ethRPCEndpoint       = "/rpc"
 	ethWSEndpoint        = "/ws"
+	graphQLEndpoint      = "/graphql"
+	warpAdminEndpoint    = "/warp-admin"
 	ethTxGossipNamespace = "eth_tx_gossip"
 )
 
 var (
 	// Set last accepted key to be longer than the keys used to store accepted block IDs.
-	lastAcceptedKey = []byte("last_accepted_key")
-	acceptedPrefix  = []byte("snowman_accepted")
-	metadataPrefix  = []byte("metadata")
-	warpPrefix      = []byte("warp")
-	ethDBPrefix     = []byte("ethdb")
+	lastAcceptedKey   = []byte("last_accepted_key")
+	acceptedPrefix    = []byte("snowman_accepted")
+	metadataPrefix    = []byte("metadata")
+	warpPrefix        = []byte("warp")
+	warpIndexPrefix   = []byte("warp_index")
+	warpRelayerPrefix = []byte("warp_relayer")
+	// warpOffChainPrefix is not nested under [warpPrefix], so registered
+	// off-chain messages survive clearing the warp database.
+	warpOffChainPrefix = []byte("warp_offchain")
+	ethDBPrefix        = []byte("ethdb")
 )
 
 var (
@@ -149,6 +162,9 @@ var (
 	errInvalidHeaderPredicateResults = errors.New("invalid header predicate results")
 )
 
+// graphQLAPIName is the name enabling the GraphQL endpoint in the eth-apis config.
+const graphQLAPIName = "graphql"
+
 // legacyApiNames maps pre geth v1.10.20 api names to their updated counterparts.
 // used in attachEthService for backward configuration compatibility.
 var legacyApiNames = map[string]string{
@@ -207,6 +223,10 @@ type VM struct {
 	// set to a prefixDB with the prefix [warpPrefix]
 	warpDB database.Database
 
+	// [warpIndexDB] stores the warp message index. It is part of versiondb
+	// so the index is committed atomically with the last accepted block.
+	warpIndexDB database.Database
+
 	toEngine chan<- commonEng.Message
 
 	syntacticBlockValidator BlockValidator
@@ -231,7 +251,8 @@ type VM struct {
 	multiGatherer avalanchegoMetrics.MultiGatherer
 	sdkMetrics    *prometheus.Registry
 
-	bootstrapped bool
+	bootstrapped avalancheUtils.Atomic[bool]
+	stateSyncing avalancheUtils.Atomic[bool]
 
 	logger SubnetEVMLogger
 	// State sync server and client
@@ -242,6 +263,18 @@ type VM struct {
 	// Used to serve BLS signatures of warp messages over RPC
 	warpBackend warp.Backend
 
+	// Delivers accepted warp messages to other chains if enabled
+	warpRelayer *relayer.Relayer
+
+	// Indexes warp messages sent and delivered in accepted blocks if enabled
+	warpIndexer *warp.Indexer
+
+	// Holds the off-chain warp messages registered through the warp admin API if enabled
+	warpOffChainRegistry *warp.OffChainRegistry
+
+	// Prunes the warp database according to the configured retention policy
+	warpPruner *warp.Pruner
+
 	// Initialize only sets these if nil so they can be overridden in tests
 	p2pSender          commonEng.AppSender
 	ethTxGossipHandler p2p.Handler
@@ -264,7 +297,7 @@ func (vm *VM) Initialize(
 	vm.config.SetDefaults()
 	if len(configBytes) > 0 {
 		if err := json.Unmarshal(configBytes, &vm.config); err != nil {
-			return fmt.Errorf("failed to unmarshal config %s: %w", string(configBytes), err)
+			return fmt.Errorf("failed to unmarshal config: %w", err)
 		}
 	}
 	if err := vm.config.Validate(); err != nil {
@@ -296,9 +329,7 @@ func (vm *VM) Initialize(
 
 	vm.toEngine = toEngine
 	vm.shutdownChan = make(chan struct{}, 1)
-	// Use NewNested rather than New so that the structure of the database
-	// remains the same regardless of the provided baseDB type.
-	vm.chaindb = rawdb.NewDatabase(Database{prefixdb.NewNested(ethDBPrefix, db)})
+	vm.chaindb = NewChainDatabase(db)
 	vm.db = versiondb.New(db)
 	vm.acceptedBlockDB = prefixdb.New(acceptedPrefix, vm.db)
 	vm.metadataDB = prefixdb.New(metadataPrefix, vm.db)
@@ -306,6 +337,7 @@ func (vm *VM) Initialize(
 	// that warp signatures are committed to the database atomically with
 	// the last accepted block.
 	vm.warpDB = prefixdb.New(warpPrefix, db)
+	vm.warpIndexDB = prefixdb.New(warpIndexPrefix, vm.db)
 
 	if vm.config.InspectDatabase {
 		start := time.Now()
@@ -399,6 +431,7 @@ func (vm *VM) Initialize(
 	vm.ethConfig.TxPool.Lifetime = vm.config.TxPoolLifetime.Duration
 
 	vm.ethConfig.AllowUnfinalizedQueries = vm.config.AllowUnfinalizedQueries
+	vm.ethConfig.TraceOutputDir = vm.config.TraceOutputDir
 	vm.ethConfig.AllowUnprotectedTxs = vm.config.AllowUnprotectedTxs
 	vm.ethConfig.AllowUnprotectedTxHashes = vm.config.AllowUnprotectedTxHashes
 	vm.ethConfig.Preimages = vm.config.Preimages
@@ -477,7 +510,21 @@ func (vm *VM) Initialize(
 	for i, hexMsg := range vm.config.WarpOffChainMessages {
 		offchainWarpMessages[i] = []byte(hexMsg)
 	}
-	vm.warpBackend, err = warp.NewBackend(vm.ctx.NetworkID, vm.ctx.ChainID, vm.ctx.WarpSigner, vm, vm.warpDB, warpSignatureCacheSize, offchainWarpMessages)
+	if vm.config.WarpOffChainAdminToken != "" {
+		vm.warpOffChainRegistry, err = warp.NewOffChainRegistry(
+			vm.ctx.NetworkID,
+			vm.ctx.ChainID,
+			warp.OffChainPolicy{
+				AllowedSourceAddresses: vm.config.WarpOffChainSourceAddresses,
+				TTL:                    vm.config.WarpOffChainMessageTTL.Duration,
+			},
+			prefixdb.New(warpOffChainPrefix, db),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to initialize warp off-chain registry: %w", err)
+		}
+	}
+	vm.warpBackend, err = warp.NewBackend(vm.ctx.NetworkID, vm.ctx.ChainID, vm.ctx.WarpSigner, vm, vm, vm.warpDB, warpSignatureCacheSize, offchainWarpMessages, vm.warpOffChainRegistry)
 	if err != nil {
 		return err
 	}
@@ -489,10 +536,34 @@ func (vm *VM) Initialize(
 		}
 	}
 
+	if vm.config.WarpIndexEnabled {
+		vm.warpIndexer = warp.NewIndexer(vm.ctx.ChainID, vm.warpIndexDB)
+	}
+
+	if vm.config.WarpRelayerEnabled {
+		signatureAggregator := warp.NewSignatureAggregator(vm.ctx.SubnetID, warpValidators.NewState(vm.ctx), vm.client, aggregator.NewSignatureCache(warpSignatureCacheSize))
+		vm.warpRelayer, err = relayer.New(vm.config.WarpRelayerConfig(), vm.warpBackend, signatureAggregator, prefixdb.New(warpRelayerPrefix, db))
+		if err != nil {
+			return fmt.Errorf("failed to initialize warp relayer: %w", err)
+		}
+		// Subscribe before the chain bootstraps, so the messages accepted while
+		// catching up are queued until the relayer starts in normal operation.
+		if err := vm.warpRelayer.Subscribe(); err != nil {
+			return fmt.Errorf("failed to subscribe warp relayer: %w", err)
+		}
+	}
+
 	if err := vm.initializeChain(lastAcceptedHash, vm.ethConfig); err != nil {
 		return err
 	}
 
+	if retention := vm.warpRetentionPolicy(); retention.Enabled() {
+		vm.warpPruner = warp.NewPruner(vm.warpBackend, retention, vm.config.WarpPruningInterval.Duration, func() uint64 {
+			return vm.blockChain.LastAcceptedBlock().NumberU64()
+		})
+		vm.warpPruner.Start()
+	}
+
 	go vm.ctx.Log.RecoverAndPanic(vm.startContinuousProfiler)
 
 	vm.initializeStateSyncServer()
@@ -646,10 +717,12 @@ func (vm *VM) initChainState(lastAcceptedBlock *types.Block) error {
 func (vm *VM) SetState(_ context.Context, state snow.State) error {
 	switch state {
 	case snow.StateSyncing:
-		vm.bootstrapped = false
+		vm.bootstrapped.Set(false)
+		vm.stateSyncing.Set(true)
 		return nil
 	case snow.Bootstrapping:
-		vm.bootstrapped = false
+		vm.bootstrapped.Set(false)
+		vm.stateSyncing.Set(false)
 		if err := vm.StateSyncClient.Error(); err != nil {
 			return err
 		}
@@ -659,7 +732,15 @@ func (vm *VM) SetState(_ context.Context, state snow.State) error {
 		if err := vm.initBlockBuilding(); err != nil {
 			return fmt.Errorf("failed to initialize block building: %w", err)
 		}
-		vm.bootstrapped = true
+		// Deliver the messages queued while bootstrapping only once the node
+		// is in normal operation and can reach the destinations.
+		if vm.warpRelayer != nil {
+			if err := vm.warpRelayer.Start(); err != nil {
+				return fmt.Errorf("failed to start warp relayer: %w", err)
+			}
+		}
+		vm.bootstrapped.Set(true)
+		vm.stateSyncing.Set(false)
 		return nil
 	default:
 		return snow.ErrUnknownState
@@ -803,6 +884,12 @@ func (vm *VM) Shutdown(context.Context) error {
 		vm.cancel()
 	}
 	vm.Network.Shutdown()
+	if vm.warpRelayer != nil {
+		vm.warpRelayer.Stop()
+	}
+	if vm.warpPruner != nil {
+		vm.warpPruner.Stop()
+	}
 	if err := vm.StateSyncClient.Shutdown(); err != nil {
 		log.Error("error stopping state syncer", "err", err)
 	}
@@ -935,6 +1022,49 @@ func (vm *VM) GetBlockIDAtHeight(_ context.Context, blkHeight uint64) (ids.ID, e
 	return ids.ID(ethBlock.Hash()), nil
 }
 
+// GetAcceptedWarpMessages implements warp.AcceptedMessageReader by reading the
+// warp messages sent in the accepted block at [height] from its receipts.
+func (vm *VM) GetAcceptedWarpMessages(height uint64) ([]*avalancheWarp.UnsignedMessage, error) {
+	if lastAccepted := vm.blockChain.LastAcceptedBlock().NumberU64(); height > lastAccepted {
+		return nil, fmt.Errorf("block %d is not accepted, last accepted block is %d", height, lastAccepted)
+	}
+	ethBlock := vm.blockChain.GetBlockByNumber(height)
+	if ethBlock == nil {
+		return nil, fmt.Errorf("block %d: %w", height, database.ErrNotFound)
+	}
+	receipts := vm.blockChain.GetReceiptsByHash(ethBlock.Hash())
+	if receipts == nil && ethBlock.ReceiptHash() != types.EmptyRootHash {
+		return nil, fmt.Errorf("failed to fetch receipts of block %d", height)
+	}
+	var messages []*avalancheWarp.UnsignedMessage
+	for _, receipt := range receipts {
+		for _, txLog := range receipt.Logs {
+			// The warp precompile also emits WarpMessageConsumed events.
+			if txLog.Address != warpPrecompile.ContractAddress || len(txLog.Topics) == 0 || txLog.Topics[0] != warpPrecompile.WarpABI.Events["SendWarpMessage"].ID {
+				continue
+			}
+			message, err := warpPrecompile.UnpackSendWarpEventDataToMessage(txLog.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse warp log data into unsigned message (TxHash: %s, LogIndex: %d): %w", txLog.TxHash, txLog.Index, err)
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// warpRetentionPolicy returns the retention policy of the warp database.
+func (vm *VM) warpRetentionPolicy() warp.RetentionPolicy {
+	policy := warp.RetentionPolicy{
+		Blocks: vm.config.WarpRetentionBlocks,
+		Period: vm.config.WarpRetentionPeriod.Duration,
+	}
+	if vm.config.WarpPruneDelivered {
+		policy.Deliveries = vm.warpIndexer
+	}
+	return policy
+}
+
 func (vm *VM) Version(context.Context) (string, error) {
 	return Version, nil
 }
@@ -953,7 +1083,36 @@ func newHandler(name string, service interface{}) (http.Handler, error) {
 // CreateHandlers makes new http handlers that can handle API calls
 func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 	handler := rpc.NewServer(vm.config.APIMaxDuration.Duration)
-	enabledAPIs := vm.config.EthAPIs()
+	if vm.config.RPCAuthEnabled() {
+		auth, err := rpc.NewAuthenticator(vm.config.RPCAuthConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rpc authenticator: %w", err)
+		}
+		handler.SetAuthenticator(auth)
+	}
+	if vm.config.RPCRateLimitRefillRate > 0 {
+		rateLimitConfig := vm.config.RPCRateLimitConfig()
+		rateLimitConfig.CurrentBlock = func() uint64 {
+			return vm.blockChain.CurrentBlock().Number.Uint64()
+		}
+		rateLimiter, err := rpc.NewRateLimiter(rateLimitConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rpc rate limiter: %w", err)
+		}
+		handler.SetRateLimiter(rateLimiter)
+	}
+	// GraphQL is not an RPC service, so it is served on its own endpoint.
+	var (
+		enabledAPIs    []string
+		graphQLEnabled bool
+	)
+	for _, name := range vm.config.EthAPIs() {
+		if name == graphQLAPIName {
+			graphQLEnabled = true
+			continue
+		}
+		enabledAPIs = append(enabledAPIs, name)
+	}
 	if err := attachEthService(handler, vm.eth.APIs(), enabledAPIs); err != nil {
 		return nil, err
 	}
@@ -981,12 +1140,37 @@ func (vm *VM) CreateHandlers(context.Context) (map[string]http.Handler, error) {
 
 	if vm.config.WarpAPIEnabled {
 		validatorsState := warpValidators.NewState(vm.ctx)
-		if err := handler.RegisterName("warp", warp.NewAPI(vm.ctx.NetworkID, vm.ctx.SubnetID, vm.ctx.ChainID, validatorsState, vm.warpBackend, vm.client)); err != nil {
+		if err := handler.RegisterName("warp", warp.NewAPI(vm.ctx.NetworkID, vm.ctx.SubnetID, vm.ctx.ChainID, validatorsState, vm.warpBackend, vm.client, vm.warpIndexer)); err != nil {
 			return nil, err
 		}
 		enabledAPIs = append(enabledAPIs, "warp")
 	}
 
+	if vm.warpOffChainRegistry != nil {
+		warpAdminHandler := rpc.NewServer(vm.config.APIMaxDuration.Duration)
+		if err := warpAdminHandler.RegisterName("warp", warp.NewOffChainAPI(vm.warpOffChainRegistry)); err != nil {
+			return nil, err
+		}
+		apis[warpAdminEndpoint] = newTokenAuthHandler(vm.config.WarpOffChainAdminToken, warpAdminHandler)
+		enabledAPIs = append(enabledAPIs, "warp-admin")
+	}
+
+	if graphQLEnabled {
+		filterSystem := filters.NewFilterSystem(vm.eth.APIBackend, filters.Config{
+			Timeout: 5 * time.Minute,
+		})
+		graphQLHandler, err := graphql.New(vm.eth.APIBackend, filterSystem, vm.config.MaxBlocksPerRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create graphql handler: %w", err)
+		}
+		// The GraphQL endpoint is subject to the authentication, rate limiting
+		// and maximum duration of the RPC handler. Its requests are authorized
+		// and charged as calls to the "graphql" method, and the blocks read by
+		// the ranges of a request are limited to MaxBlocksPerRequest in total.
+		apis[graphQLEndpoint] = handler.WrapHTTPHandler(graphQLAPIName, graphQLHandler)
+		enabledAPIs = append(enabledAPIs, graphQLAPIName)
+	}
+
 	log.Info(fmt.Sprintf("Enabled APIs: %s", strings.Join(enabledAPIs, ", ")))
 	apis[ethRPCEndpoint] = handler
 	apis[ethWSEndpoint] = handler.WebsocketHandlerWithDuration(
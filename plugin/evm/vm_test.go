@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -50,6 +52,7 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/core/txpool"
 	"github.com/shubhamdubey02/subnet-evm/core/types"
 	"github.com/shubhamdubey02/subnet-evm/eth"
+	"github.com/shubhamdubey02/subnet-evm/ethclient/subnetevmclient"
 	"github.com/shubhamdubey02/subnet-evm/internal/ethapi"
 	"github.com/shubhamdubey02/subnet-evm/metrics"
 	"github.com/shubhamdubey02/subnet-evm/params"
@@ -57,6 +60,7 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/precompile/allowlist"
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/deployerallowlist"
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/feemanager"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/nativeminter"
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/rewardmanager"
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/txallowlist"
 	"github.com/shubhamdubey02/subnet-evm/rpc"
@@ -3270,3 +3274,133 @@ func TestCrossChainMessagestoVM(t *testing.T) {
 	require.NoError(err)
 	require.True(calledSendCrossChainAppResponseFn, "sendCrossChainAppResponseFn was not called")
 }
+
+func TestGraphQL(t *testing.T) {
+	require := require.New(t)
+	issuer, vm, _, _ := GenesisVM(t, true, genesisJSONSubnetEVM, `{"eth-apis": ["eth", "graphql"]}`, "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	tx := types.NewTransaction(0, testEthAddrs[1], big.NewInt(1), 21000, big.NewInt(testMinGasPrice), nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(vm.chainConfig.ChainID), testKeys[0])
+	require.NoError(err)
+	for _, err := range vm.txPool.AddRemotesSync([]*types.Transaction{signedTx}) {
+		require.NoError(err)
+	}
+	blk := issueAndAccept(t, issuer, vm)
+	vm.blockChain.DrainAcceptorQueue()
+
+	handlers, err := vm.CreateHandlers(context.Background())
+	require.NoError(err)
+	handler, ok := handlers[graphQLEndpoint]
+	require.True(ok)
+
+	query := fmt.Sprintf(`{"query": "{ block(number: 1) { hash blockGasCost feeConfig { targetBlockRate } activePrecompiles transactions { hash from { address } status logs { index } } account(address: \"%s\") { balance } } lastAccepted { number } }"}`, testEthAddrs[1])
+	req := httptest.NewRequest(http.MethodPost, graphQLEndpoint, strings.NewReader(query))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	require.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	var result struct {
+		Data struct {
+			Block struct {
+				Hash         common.Hash
+				BlockGasCost *hexutil.Big
+				FeeConfig    struct {
+					TargetBlockRate hexutil.Uint64
+				}
+				ActivePrecompiles string
+				Transactions      []struct {
+					Hash common.Hash
+					From struct {
+						Address common.Address
+					}
+					Status hexutil.Uint64
+					Logs   []struct{}
+				}
+				Account struct {
+					Balance *hexutil.Big
+				}
+			}
+			LastAccepted struct {
+				Number hexutil.Uint64
+			}
+		}
+	}
+	require.NoError(json.Unmarshal(res.Body.Bytes(), &result))
+	block := result.Data.Block
+	require.Equal(common.Hash(blk.ID()), block.Hash)
+	require.NotNil(block.BlockGasCost)
+	require.Equal(hexutil.Uint64(vm.chainConfig.FeeConfig.TargetBlockRate), block.FeeConfig.TargetBlockRate)
+	require.Equal("{}", block.ActivePrecompiles)
+	require.Len(block.Transactions, 1)
+	require.Equal(signedTx.Hash(), block.Transactions[0].Hash)
+	require.Equal(testEthAddrs[0], block.Transactions[0].From.Address)
+	require.Equal(hexutil.Uint64(types.ReceiptStatusSuccessful), block.Transactions[0].Status)
+	require.Empty(block.Transactions[0].Logs)
+	require.NotNil(block.Account.Balance)
+	require.Equal(hexutil.Uint64(1), result.Data.LastAccepted.Number)
+}
+
+func TestSubnetEVMClient(t *testing.T) {
+	require := require.New(t)
+	genesis := &core.Genesis{}
+	require.NoError(genesis.UnmarshalJSON([]byte(genesisJSONSubnetEVM)))
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		nativeminter.ConfigKey: nativeminter.NewConfig(utils.NewUint64(0), testEthAddrs[0:1], nil, nil, nil),
+	}
+	genesisJSON, err := genesis.MarshalJSON()
+	require.NoError(err)
+	configJSON := `{"eth-apis": ["eth", "internal-eth", "internal-blockchain", "internal-transaction", "debug-tracer"]}`
+	issuer, vm, _, _ := GenesisVM(t, true, string(genesisJSON), configJSON, "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	handlers, err := vm.CreateHandlers(context.Background())
+	require.NoError(err)
+	server := httptest.NewServer(handlers[ethRPCEndpoint])
+	defer server.Close()
+	client, err := subnetevmclient.Dial(server.URL)
+	require.NoError(err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Mint to a new address, accepting the block of the transaction while the
+	// client waits for it.
+	recipient := common.HexToAddress("0x1234")
+	type mintResult struct {
+		receipt *types.Receipt
+		err     error
+	}
+	results := make(chan mintResult, 1)
+	go func() {
+		receipt, err := client.MintNativeCoin(ctx, testKeys[0], recipient, big.NewInt(100))
+		results <- mintResult{receipt, err}
+	}()
+	issueAndAccept(t, issuer, vm)
+	result := <-results
+	require.NoError(result.err)
+
+	balance, err := client.Eth().BalanceAt(ctx, recipient, nil)
+	require.NoError(err)
+	require.Equal(big.NewInt(100), balance)
+
+	role, err := client.AllowListRole(ctx, nativeminter.ContractAddress, testEthAddrs[0], nil)
+	require.NoError(err)
+	require.Equal(allowlist.AdminRole, role)
+
+	states, err := client.PrecompileStateAt(ctx, nil)
+	require.NoError(err)
+	require.Contains(states, nativeminter.ConfigKey)
+	require.Equal(&allowlist.RoleCounts{Admin: 1}, states[nativeminter.ConfigKey].Roles)
+	require.IsType(&nativeminter.Config{}, states[nativeminter.ConfigKey].Config)
+
+	frame, err := client.TraceTransactionCalls(ctx, result.receipt.TxHash)
+	require.NoError(err)
+	require.Equal(testEthAddrs[0], frame.From)
+	require.Equal(&nativeminter.ContractAddress, frame.To)
+}
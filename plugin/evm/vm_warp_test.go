@@ -12,6 +12,7 @@ import (
 
 	_ "embed"
 
+	"github.com/MetalBlockchain/metalgo/database/memdb"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/snow/choices"
 	"github.com/MetalBlockchain/metalgo/snow/engine/snowman/block"
@@ -23,10 +24,12 @@ import (
 	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shubhamdubey02/subnet-evm/core"
 	"github.com/shubhamdubey02/subnet-evm/core/rawdb"
 	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/eth"
 	"github.com/shubhamdubey02/subnet-evm/eth/tracers"
 	"github.com/shubhamdubey02/subnet-evm/params"
 	"github.com/shubhamdubey02/subnet-evm/plugin/evm/message"
@@ -34,6 +37,7 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
 	"github.com/shubhamdubey02/subnet-evm/predicate"
 	"github.com/shubhamdubey02/subnet-evm/utils"
+	warpBackend "github.com/shubhamdubey02/subnet-evm/warp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,7 +57,7 @@ func TestSendWarpMessage(t *testing.T) {
 	}
 	genesisJSON, err := genesis.MarshalJSON()
 	require.NoError(err)
-	issuer, vm, _, _ := GenesisVM(t, true, string(genesisJSON), "", "")
+	issuer, vm, _, _ := GenesisVM(t, true, string(genesisJSON), `{"warp-index-enabled": true}`, "")
 
 	defer func() {
 		require.NoError(vm.Shutdown(context.Background()))
@@ -126,6 +130,20 @@ func TestSendWarpMessage(t *testing.T) {
 	// Verify the message signature after accepting the block.
 	rawSignatureBytes, err := vm.warpBackend.GetMessageSignature(unsignedMessageID)
 	require.NoError(err)
+
+	// Verify the message was indexed by its source address.
+	page, err := vm.warpIndexer.GetMessages(warpBackend.MessageFilter{SourceAddress: &testEthAddrs[0]})
+	require.NoError(err)
+	require.Len(page.Messages, 1)
+	require.Equal(unsignedMessageID, page.Messages[0].MessageID)
+	require.Equal(ethBlock1.Hash(), page.Messages[0].BlockHash)
+	require.Equal(warpBackend.MessageStatusPending, page.Messages[0].Status)
+
+	// Verify the message can be read back from the accepted block if pruned.
+	acceptedMessages, err := vm.GetAcceptedWarpMessages(ethBlock1.NumberU64())
+	require.NoError(err)
+	require.Len(acceptedMessages, 1)
+	require.Equal(unsignedMessageID, acceptedMessages[0].ID())
 	blsSignature, err := bls.SignatureFromBytes(rawSignatureBytes[:])
 	require.NoError(err)
 
@@ -155,6 +173,135 @@ func TestSendWarpMessage(t *testing.T) {
 	require.True(bls.Verify(vm.ctx.PublicKey, blsSignature, unsignedMessage.Bytes()))
 }
 
+func TestWarpIndexErrorDoesNotFailAccept(t *testing.T) {
+	require := require.New(t)
+	genesis := &core.Genesis{}
+	require.NoError(genesis.UnmarshalJSON([]byte(genesisJSONDurango)))
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		warp.ConfigKey: warp.NewDefaultConfig(utils.NewUint64(0)),
+	}
+	genesisJSON, err := genesis.MarshalJSON()
+	require.NoError(err)
+	issuer, vm, _, _ := GenesisVM(t, true, string(genesisJSON), `{"warp-index-enabled": true}`, "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	// Every write to the index fails.
+	indexDB := memdb.New()
+	require.NoError(indexDB.Close())
+	vm.warpIndexer = warpBackend.NewIndexer(vm.ctx.ChainID, indexDB)
+
+	warpSendMessageInput, err := warp.PackSendWarpMessage([]byte{1})
+	require.NoError(err)
+	tx := types.NewTransaction(uint64(0), warp.ContractAddress, big.NewInt(1), 100_000, big.NewInt(testMinGasPrice), warpSendMessageInput)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(vm.chainConfig.ChainID), testKeys[0])
+	require.NoError(err)
+	errs := vm.txPool.AddRemotesSync([]*types.Transaction{signedTx})
+	require.NoError(errs[0])
+
+	<-issuer
+	blk, err := vm.BuildBlock(context.Background())
+	require.NoError(err)
+	require.NoError(blk.Verify(context.Background()))
+	require.NoError(vm.SetPreference(context.Background(), blk.ID()))
+	require.NoError(blk.Accept(context.Background()))
+	require.Equal(choices.Accepted, blk.Status())
+}
+
+func TestConsumeWarpMessage(t *testing.T) {
+	require := require.New(t)
+	genesis := &core.Genesis{}
+	require.NoError(genesis.UnmarshalJSON([]byte(genesisJSONDurango)))
+	warpConfig := warp.NewDefaultConfig(utils.NewUint64(0))
+	warpConfig.ReplayProtection = true
+	genesis.Config.GenesisPrecompiles = params.Precompiles{
+		warp.ConfigKey: warpConfig,
+	}
+	genesisJSON, err := genesis.MarshalJSON()
+	require.NoError(err)
+	issuer, vm, _, _ := GenesisVM(t, true, string(genesisJSON), "", "")
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+	}()
+
+	sourceChainID := ids.GenerateTestID()
+	addressedPayload, err := payload.NewAddressedCall(testEthAddrs[1].Bytes(), []byte{1, 2, 3})
+	require.NoError(err)
+	unsignedMessage, err := avalancheWarp.NewUnsignedMessage(testNetworkID, sourceChainID, addressedPayload.Bytes())
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	blsSecretKey, err := bls.NewSecretKey()
+	require.NoError(err)
+	vm.ctx.ValidatorState = &validators.TestState{
+		GetSubnetIDF: func(ctx context.Context, chainID ids.ID) (ids.ID, error) {
+			return ids.Empty, nil
+		},
+		GetValidatorSetF: func(ctx context.Context, height uint64, subnetID ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+			return map[ids.NodeID]*validators.GetValidatorOutput{
+				nodeID: {
+					NodeID:    nodeID,
+					PublicKey: bls.PublicFromSecretKey(blsSecretKey),
+					Weight:    100,
+				},
+			}, nil
+		},
+	}
+	signers := set.NewBits()
+	signers.Add(0)
+	warpSignature := &avalancheWarp.BitSetSignature{Signers: signers.Bytes()}
+	copy(warpSignature.Signature[:], bls.SignatureToBytes(bls.Sign(blsSecretKey, unsignedMessage.Bytes())))
+	signedMessage, err := avalancheWarp.NewMessage(unsignedMessage, warpSignature)
+	require.NoError(err)
+
+	consumeInput, err := warp.PackConsumeWarpMessage(0)
+	require.NoError(err)
+	tx, err := types.SignTx(
+		predicate.NewPredicateTx(
+			vm.chainConfig.ChainID,
+			0,
+			&warp.ContractAddress,
+			1_000_000,
+			big.NewInt(225*params.GWei),
+			big.NewInt(params.GWei),
+			common.Big0,
+			consumeInput,
+			types.AccessList{},
+			warp.ContractAddress,
+			signedMessage.Bytes(),
+		),
+		types.LatestSignerForChainID(vm.chainConfig.ChainID),
+		testKeys[0],
+	)
+	require.NoError(err)
+	errs := vm.txPool.AddRemotesSync([]*types.Transaction{tx})
+	require.NoError(errs[0])
+
+	blockCtx := &block.Context{PChainHeight: 10}
+	vm.clock.Set(vm.clock.Time().Add(2 * time.Second))
+	<-issuer
+	blk, err := vm.BuildBlockWithContext(context.Background(), blockCtx)
+	require.NoError(err)
+	require.NoError(blk.(block.WithVerifyContext).VerifyWithContext(context.Background(), blockCtx))
+	require.NoError(vm.SetPreference(context.Background(), blk.ID()))
+	require.NoError(blk.Accept(context.Background()))
+	require.Equal(choices.Accepted, blk.Status())
+	vm.blockChain.DrainAcceptorQueue()
+
+	// The WarpMessageConsumed event is emitted by the warp precompile, but is
+	// not a message sent by this chain.
+	ethBlock := blk.(*chain.BlockWrapper).Block.(*Block).ethBlock
+	receipts := vm.blockChain.GetReceiptsByHash(ethBlock.Hash())
+	require.Len(receipts, 1)
+	require.Equal(types.ReceiptStatusSuccessful, receipts[0].Status)
+	require.Len(receipts[0].Logs, 1)
+	require.Equal(warp.WarpABI.Events["WarpMessageConsumed"].ID, receipts[0].Logs[0].Topics[0])
+	messages, err := vm.GetAcceptedWarpMessages(ethBlock.NumberU64())
+	require.NoError(err)
+	require.Empty(messages)
+}
+
 func TestValidateWarpMessage(t *testing.T) {
 	require := require.New(t)
 	sourceChainID := ids.GenerateTestID()
@@ -583,6 +730,29 @@ func TestReceiveWarpMessage(t *testing.T) {
 	txTraceResultBytes, err := json.Marshal(txTraceResult)
 	require.NoError(err)
 	require.JSONEq(string(txTraceResultBytes), string(blockTxTraceResultBytes))
+
+	debugAPI := eth.NewDebugAPI(vm.eth)
+	predicateResults, err := debugAPI.GetPredicateResults(context.Background(), ethBlock.Hash())
+	require.NoError(err)
+	require.Len(predicateResults, 1)
+	require.Equal(getVerifiedWarpMessageTx.Hash(), predicateResults[0].TxHash)
+	require.Len(predicateResults[0].Results, 1)
+	require.Equal(warp.ContractAddress, predicateResults[0].Results[0].Address)
+	require.Empty(predicateResults[0].Results[0].FailedIndices)
+
+	// Re-verify the predicate at a P-Chain height where it fails to report why.
+	txBytes, err := getVerifiedWarpMessageTx.MarshalBinary()
+	require.NoError(err)
+	validPChainHeight, invalidPChainHeight := hexutil.Uint64(minimumValidPChainHeight), hexutil.Uint64(minimumValidPChainHeight-1)
+	verification, err := debugAPI.VerifyPredicates(context.Background(), txBytes, &eth.PredicateBlockContext{PChainHeight: &validPChainHeight})
+	require.NoError(err)
+	require.Len(verification.Precompiles, 1)
+	require.Equal([]*eth.PredicateVerification{{Index: 0, Valid: true}}, verification.Precompiles[0].Predicates)
+	verification, err = debugAPI.VerifyPredicates(context.Background(), txBytes, &eth.PredicateBlockContext{PChainHeight: &invalidPChainHeight})
+	require.NoError(err)
+	require.Len(verification.Precompiles, 1)
+	require.False(verification.Precompiles[0].Predicates[0].Valid)
+	require.Contains(verification.Precompiles[0].Predicates[0].Error, getValidatorSetTestErr.Error())
 }
 
 func TestMessageSignatureRequestsToVM(t *testing.T) {
@@ -598,7 +768,7 @@ func TestMessageSignatureRequestsToVM(t *testing.T) {
 	require.NoError(t, err)
 
 	// Add the known message and get its signature to confirm.
-	err = vm.warpBackend.AddMessage(warpMessage)
+	err = vm.warpBackend.AddMessage(warpMessage, 1, 0)
 	require.NoError(t, err)
 	signature, err := vm.warpBackend.GetMessageSignature(warpMessage.ID())
 	require.NoError(t, err)
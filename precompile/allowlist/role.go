@@ -120,3 +120,23 @@ func FromBig(b *big.Int) (Role, error) {
 		return Role{}, ErrInvalidRole
 	}
 }
+
+// RoleCounts is the number of addresses holding each role in an allow list.
+type RoleCounts struct {
+	Admin   uint64 `json:"admin"`
+	Manager uint64 `json:"manager"`
+	Enabled uint64 `json:"enabled"`
+}
+
+// Add counts the role stored in [value], the value of a storage slot of an
+// allow list. Values that are not roles are ignored.
+func (c *RoleCounts) Add(value common.Hash) {
+	switch Role(value) {
+	case AdminRole:
+		c.Admin++
+	case ManagerRole:
+		c.Manager++
+	case EnabledRole:
+		c.Enabled++
+	}
+}
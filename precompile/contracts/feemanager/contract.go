@@ -110,6 +110,16 @@ func GetStoredFeeConfig(stateDB contract.StateDB) commontype.FeeConfig {
 	return feeConfig
 }
 
+// StorageSlots returns the storage slots holding the fee config and the block
+// number it last changed at, which are separate from the slots of the allow list.
+func StorageSlots() []common.Hash {
+	slots := []common.Hash{feeConfigLastChangedAtKey}
+	for i := minFeeConfigFieldKey; i <= numFeeConfigField; i++ {
+		slots = append(slots, common.Hash{byte(i)})
+	}
+	return slots
+}
+
 func GetFeeConfigLastChangedAt(stateDB contract.StateDB) *big.Int {
 	val := stateDB.GetState(ContractAddress, feeConfigLastChangedAtKey)
 	return val.Big()
@@ -167,6 +167,12 @@ func GetStoredRewardAddress(stateDB contract.StateDB) (common.Address, bool) {
 	return common.BytesToAddress(val.Bytes()), val == allowFeeRecipientsAddressValue
 }
 
+// StorageSlots returns the storage slot holding the reward address, which is
+// separate from the slots of the allow list.
+func StorageSlots() []common.Hash {
+	return []common.Hash{rewardAddressStorageKey}
+}
+
 // StoredRewardAddress stores the given [val] under rewardAddressStorageKey.
 func StoreRewardAddress(stateDB contract.StateDB, val common.Address) {
 	stateDB.SetState(ContractAddress, rewardAddressStorageKey, val.Hash())
@@ -48,6 +48,10 @@ var (
 type Config struct {
 	precompileconfig.Upgrade
 	QuorumNumerator uint64 `json:"quorumNumerator"`
+	// ReplayProtection activates consumeWarpMessage, which records the messages
+	// consumed by each contract in the precompile storage and reverts on reuse,
+	// and isMessageConsumed.
+	ReplayProtection bool `json:"replayProtection,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables
@@ -108,10 +112,15 @@ func (c *Config) Equal(s precompileconfig.Config) bool {
 		return false
 	}
 	equals := c.Upgrade.Equal(&other.Upgrade)
-	return equals && c.QuorumNumerator == other.QuorumNumerator
+	return equals && c.QuorumNumerator == other.QuorumNumerator && c.ReplayProtection == other.ReplayProtection
 }
 
 func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, blockHash common.Hash, blockNumber uint64, txHash common.Hash, logIndex int, topics []common.Hash, logData []byte) error {
+	// Only SendWarpMessage events carry a message to sign, WarpMessageConsumed
+	// events are ignored.
+	if len(topics) == 0 || topics[0] != WarpABI.Events["SendWarpMessage"].ID {
+		return nil
+	}
 	unsignedMessage, err := UnpackSendWarpEventDataToMessage(logData)
 	if err != nil {
 		return fmt.Errorf("failed to parse warp log data into unsigned message (TxHash: %s, LogIndex: %d): %w", txHash, logIndex, err)
@@ -125,7 +134,7 @@ func (c *Config) Accept(acceptCtx *precompileconfig.AcceptContext, blockHash com
 		"logData", common.Bytes2Hex(logData),
 		"warpMessageID", unsignedMessage.ID(),
 	)
-	if err := acceptCtx.Warp.AddMessage(unsignedMessage); err != nil {
+	if err := acceptCtx.Warp.AddMessage(unsignedMessage, blockNumber, acceptCtx.BlockTimestamp); err != nil {
 		return fmt.Errorf("failed to add warp message during accept (TxHash: %s, LogIndex: %d): %w", txHash, logIndex, err)
 	}
 	return nil
@@ -71,6 +71,12 @@ func TestEqualWarpConfig(t *testing.T) {
 			Expected: false,
 		},
 
+		"different replay protection": {
+			Config:   &Config{Upgrade: precompileconfig.Upgrade{BlockTimestamp: utils.NewUint64(3)}, ReplayProtection: true},
+			Other:    NewDefaultConfig(utils.NewUint64(3)),
+			Expected: false,
+		},
+
 		"same default config": {
 			Config:   NewDefaultConfig(utils.NewUint64(3)),
 			Other:    NewDefaultConfig(utils.NewUint64(3)),
@@ -321,6 +321,13 @@ func createWarpPrecompile() contract.StatefulPrecompiledContract {
 		"getVerifiedWarpMessage":   getVerifiedWarpMessage,
 		"sendWarpMessage":          sendWarpMessage,
 	}
+	// Replay protection functions are only activated once replay protection
+	// is configured by a warp upgrade, so chains that do not enable it keep
+	// reverting calls to their selectors.
+	replayProtectionFunctionMap := map[string]contract.RunStatefulPrecompileFunc{
+		"consumeWarpMessage": consumeWarpMessage,
+		"isMessageConsumed":  isMessageConsumed,
+	}
 
 	for name, function := range abiFunctionMap {
 		method, ok := WarpABI.Methods[name]
@@ -329,6 +336,13 @@ func createWarpPrecompile() contract.StatefulPrecompiledContract {
 		}
 		functions = append(functions, contract.NewStatefulPrecompileFunction(method.ID, function))
 	}
+	for name, function := range replayProtectionFunctionMap {
+		method, ok := WarpABI.Methods[name]
+		if !ok {
+			panic(fmt.Errorf("given method (%s) does not exist in the ABI", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, function, isReplayProtectionActivated))
+	}
 	// Construct the contract with no fallback function.
 	statefulContract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
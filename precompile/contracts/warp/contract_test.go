@@ -22,6 +22,7 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/utils"
 	"github.com/shubhamdubey02/subnet-evm/vmerrs"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestGetBlockchainID(t *testing.T) {
@@ -765,3 +766,244 @@ func TestPackEvents(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, unsignedWarpMessage.Bytes(), unpacked.Bytes())
 }
+
+func TestConsumeWarpMessage(t *testing.T) {
+	networkID := uint32(54321)
+	callerAddr := common.HexToAddress("0x0123")
+	sourceAddress := common.HexToAddress("0x456789")
+	sourceChainID := ids.GenerateTestID()
+	packagedPayloadBytes := []byte("mcsorley")
+	addressedPayload, err := payload.NewAddressedCall(
+		sourceAddress.Bytes(),
+		packagedPayloadBytes,
+	)
+	require.NoError(t, err)
+	unsignedWarpMsg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, addressedPayload.Bytes())
+	require.NoError(t, err)
+	warpMessage, err := avalancheWarp.NewMessage(unsignedWarpMsg, &avalancheWarp.BitSetSignature{}) // Create message with empty signature for testing
+	require.NoError(t, err)
+	warpMessagePredicateBytes := predicate.PackPredicate(warpMessage.Bytes())
+	messageID := common.Hash(unsignedWarpMsg.ID())
+	consumeWarpMsg, err := PackConsumeWarpMessage(0)
+	require.NoError(t, err)
+	noFailures := set.NewBits().Bytes()
+	consumeGas := GetVerifiedWarpMessageBaseCost + ConsumeWarpMessageGasCost + GasCostPerWarpMessageBytes*uint64(len(warpMessagePredicateBytes))
+	replayProtectionConfig := &Config{ReplayProtection: true}
+
+	// ConsumeWarpMessageGasCost covers the topics and data of the emitted event.
+	topics, data, err := PackWarpMessageConsumedEvent(messageID, callerAddr, common.Hash(sourceChainID))
+	require.NoError(t, err)
+	require.Len(t, data, 32)
+	require.Equal(t,
+		contract.ReadGasCostPerSlot+contract.WriteGasCostPerSlot+contract.LogGas+uint64(len(topics))*contract.LogTopicGas+uint64(len(data))*contract.LogDataGas,
+		ConsumeWarpMessageGasCost,
+	)
+
+	tests := map[string]testutils.PrecompileTest{
+		"consume message success": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetPredicateStorageSlots(ContractAddress, [][]byte{warpMessagePredicateBytes})
+			},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, ContractAddress).Return(noFailures)
+				mbc.EXPECT().Number().Return(big.NewInt(0)).AnyTimes()
+			},
+			SuppliedGas: consumeGas,
+			ReadOnly:    false,
+			ExpectedRes: func() []byte {
+				res, err := PackConsumeWarpMessageOutput(WarpMessage{
+					SourceChainID:       common.Hash(sourceChainID),
+					OriginSenderAddress: sourceAddress,
+					Payload:             packagedPayloadBytes,
+				})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+			AfterHook: func(t testing.TB, state contract.StateDB) {
+				require.Equal(t, trueValue, state.GetState(ContractAddress, consumedMessageKey(callerAddr, messageID)))
+
+				logsTopics, logsData := state.GetLogData()
+				require.Len(t, logsTopics, 1)
+				require.Equal(t, []common.Hash{
+					WarpABI.Events["WarpMessageConsumed"].ID,
+					messageID,
+					callerAddr.Hash(),
+				}, logsTopics[0])
+				require.Equal(t, common.Hash(sourceChainID).Bytes(), logsData[0])
+			},
+		},
+		"consume message already consumed": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetPredicateStorageSlots(ContractAddress, [][]byte{warpMessagePredicateBytes})
+				state.SetState(ContractAddress, consumedMessageKey(callerAddr, messageID), trueValue)
+			},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, ContractAddress).Return(noFailures)
+			},
+			SuppliedGas: consumeGas,
+			ReadOnly:    false,
+			ExpectedErr: errMessageAlreadyConsumed.Error(),
+		},
+		"consume message consumed by another contract": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetPredicateStorageSlots(ContractAddress, [][]byte{warpMessagePredicateBytes})
+				state.SetState(ContractAddress, consumedMessageKey(sourceAddress, messageID), trueValue)
+			},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, ContractAddress).Return(noFailures)
+				mbc.EXPECT().Number().Return(big.NewInt(0)).AnyTimes()
+			},
+			SuppliedGas: consumeGas,
+			ReadOnly:    false,
+			ExpectedRes: func() []byte {
+				res, err := PackConsumeWarpMessageOutput(WarpMessage{
+					SourceChainID:       common.Hash(sourceChainID),
+					OriginSenderAddress: sourceAddress,
+					Payload:             packagedPayloadBytes,
+				})
+				if err != nil {
+					panic(err)
+				}
+				return res
+			}(),
+		},
+		"consume message failed verification": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetPredicateStorageSlots(ContractAddress, [][]byte{warpMessagePredicateBytes})
+			},
+			SetupBlockContext: func(mbc *contract.MockBlockContext) {
+				mbc.EXPECT().GetPredicateResults(common.Hash{}, ContractAddress).Return(set.NewBits(0).Bytes())
+			},
+			SuppliedGas: GetVerifiedWarpMessageBaseCost + ConsumeWarpMessageGasCost,
+			ReadOnly:    false,
+			ExpectedErr: errNoVerifiedWarpMessage.Error(),
+		},
+		"consume message readOnly": {
+			Caller:      callerAddr,
+			InputFn:     func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:      replayProtectionConfig,
+			SuppliedGas: GetVerifiedWarpMessageBaseCost + ConsumeWarpMessageGasCost,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrWriteProtection.Error(),
+		},
+		"consume message insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     func(t testing.TB) []byte { return consumeWarpMsg },
+			Config:      replayProtectionConfig,
+			SuppliedGas: GetVerifiedWarpMessageBaseCost + ConsumeWarpMessageGasCost - 1,
+			ReadOnly:    false,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+
+	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
+}
+
+func TestIsMessageConsumed(t *testing.T) {
+	callerAddr := common.HexToAddress("0x0123")
+	messageID := common.Hash{1}
+	isMessageConsumedInput, err := PackIsMessageConsumed(messageID)
+	require.NoError(t, err)
+	packOutput := func(consumed bool) []byte {
+		res, err := PackIsMessageConsumedOutput(consumed)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+
+	replayProtectionConfig := &Config{ReplayProtection: true}
+
+	tests := map[string]testutils.PrecompileTest{
+		"consumed": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return isMessageConsumedInput },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetState(ContractAddress, consumedMessageKey(callerAddr, messageID), trueValue)
+			},
+			SuppliedGas: IsMessageConsumedGasCost,
+			ReadOnly:    true,
+			ExpectedRes: packOutput(true),
+		},
+		"not consumed": {
+			Caller:      callerAddr,
+			InputFn:     func(t testing.TB) []byte { return isMessageConsumedInput },
+			Config:      replayProtectionConfig,
+			SuppliedGas: IsMessageConsumedGasCost,
+			ReadOnly:    true,
+			ExpectedRes: packOutput(false),
+		},
+		"consumed by another contract": {
+			Caller:  callerAddr,
+			InputFn: func(t testing.TB) []byte { return isMessageConsumedInput },
+			Config:  replayProtectionConfig,
+			BeforeHook: func(t testing.TB, state contract.StateDB) {
+				state.SetState(ContractAddress, consumedMessageKey(common.Address{1}, messageID), trueValue)
+			},
+			SuppliedGas: IsMessageConsumedGasCost,
+			ReadOnly:    true,
+			ExpectedRes: packOutput(false),
+		},
+		"insufficient gas": {
+			Caller:      callerAddr,
+			InputFn:     func(t testing.TB) []byte { return isMessageConsumedInput },
+			Config:      replayProtectionConfig,
+			SuppliedGas: IsMessageConsumedGasCost - 1,
+			ReadOnly:    true,
+			ExpectedErr: vmerrs.ErrOutOfGas.Error(),
+		},
+	}
+
+	testutils.RunPrecompileTests(t, Module, state.NewTestStateDB, tests)
+}
+
+// TestReplayProtectionNotActivated checks that without replay protection,
+// calls to its functions fail without consuming gas, as calls to unknown
+// selectors did before the functions were added.
+func TestReplayProtectionNotActivated(t *testing.T) {
+	consumeWarpMsg, err := PackConsumeWarpMessage(0)
+	require.NoError(t, err)
+	isMessageConsumedInput, err := PackIsMessageConsumed(common.Hash{1})
+	require.NoError(t, err)
+	const suppliedGas = 1_000_000
+
+	for name, config := range map[string]*Config{
+		"not configured":             nil,
+		"replay protection disabled": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for _, input := range [][]byte{consumeWarpMsg, isMessageConsumedInput} {
+				require := require.New(t)
+
+				ctrl := gomock.NewController(t)
+				stateDB := state.NewTestStateDB(t)
+				blockContext := contract.NewMockBlockContext(ctrl)
+				accessibleState := contract.NewMockAccessibleState(ctrl)
+				accessibleState.EXPECT().GetStateDB().Return(stateDB).AnyTimes()
+				if config != nil {
+					require.NoError(Module.Configure(nil, config, stateDB, blockContext))
+				}
+
+				ret, remainingGas, err := Module.Contract.Run(accessibleState, common.Address{1}, ContractAddress, input, suppliedGas, false)
+				require.ErrorContains(err, "invalid non-activated function selector")
+				require.Nil(ret)
+				require.Equal(uint64(suppliedGas), remainingGas)
+			}
+		})
+	}
+}
@@ -55,6 +55,23 @@ func handleWarpMessage(accessibleState contract.AccessibleState, input []byte, s
 	if err != nil {
 		return nil, remainingGas, fmt.Errorf("%w: %s", errInvalidIndexInput, err)
 	}
+	warpMessage, remainingGas, err := loadVerifiedWarpMessage(accessibleState, warpIndexInput, remainingGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if warpMessage == nil {
+		return handler.packFailed(), remainingGas, nil
+	}
+	res, err := handler.handleMessage(warpMessage)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return res, remainingGas, nil
+}
+
+// loadVerifiedWarpMessage returns the warp message in the predicate at [warpIndexInput] of the
+// current transaction, or nil if there is no such predicate or it failed verification.
+func loadVerifiedWarpMessage(accessibleState contract.AccessibleState, warpIndexInput uint32, remainingGas uint64) (*warp.Message, uint64, error) {
 	if warpIndexInput > math.MaxInt32 {
 		return nil, remainingGas, fmt.Errorf("%w: larger than MaxInt32", errInvalidIndexInput)
 	}
@@ -64,7 +81,7 @@ func handleWarpMessage(accessibleState contract.AccessibleState, input []byte, s
 	predicateResults := accessibleState.GetBlockContext().GetPredicateResults(state.GetTxHash(), ContractAddress)
 	valid := exists && !set.BitsFromBytes(predicateResults).Contains(warpIndex)
 	if !valid {
-		return handler.packFailed(), remainingGas, nil
+		return nil, remainingGas, nil
 	}
 
 	// Note: we charge for the size of the message during both predicate verification and each time the message is read during
@@ -73,7 +90,8 @@ func handleWarpMessage(accessibleState contract.AccessibleState, input []byte, s
 	if overflow {
 		return nil, 0, vmerrs.ErrOutOfGas
 	}
-	if remainingGas, err = contract.DeductGas(remainingGas, msgBytesGas); err != nil {
+	remainingGas, err := contract.DeductGas(remainingGas, msgBytesGas)
+	if err != nil {
 		return nil, 0, err
 	}
 	// Note: since the predicate is verified in advance of execution, the precompile should not
@@ -86,11 +104,7 @@ func handleWarpMessage(accessibleState contract.AccessibleState, input []byte, s
 	if err != nil {
 		return nil, remainingGas, fmt.Errorf("%w: %s", errInvalidWarpMsg, err)
 	}
-	res, err := handler.handleMessage(warpMessage)
-	if err != nil {
-		return nil, remainingGas, err
-	}
-	return res, remainingGas, nil
+	return warpMessage, remainingGas, nil
 }
 
 type addressedPayloadHandler struct{}
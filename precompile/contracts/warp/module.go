@@ -46,10 +46,15 @@ func (*configurator) MakeConfig() precompileconfig.Config {
 	return new(Config)
 }
 
-// Configure is a no-op for warp since it does not need to store any information in the state
+// Configure records in the state whether replay protection is enabled. Consumed messages
+// are stored alongside it, so they are cleared when warp is disabled.
 func (*configurator) Configure(chainConfig precompileconfig.ChainConfig, cfg precompileconfig.Config, state contract.StateDB, _ contract.ConfigurationBlockContext) error {
-	if _, ok := cfg.(*Config); !ok {
+	config, ok := cfg.(*Config)
+	if !ok {
 		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
 	}
+	if config.ReplayProtection {
+		state.SetState(ContractAddress, replayProtectionKey, trueValue)
+	}
 	return nil
 }
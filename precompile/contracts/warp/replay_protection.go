@@ -0,0 +1,186 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/accounts/abi"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contract"
+	"github.com/shubhamdubey02/subnet-evm/vmerrs"
+)
+
+const (
+	IsMessageConsumedGasCost uint64 = contract.ReadGasCostPerSlot // Cost of reading the consumption slot
+	// ConsumeWarpMessageGasCost is charged in addition to the cost of reading the verified message.
+	// It covers reading and writing the consumption slot and emitting the WarpMessageConsumed event
+	// with 3 topics (signature + messageID + consumer) and the 32 byte sourceChainID as data.
+	ConsumeWarpMessageGasCost uint64 = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot + contract.LogGas + 3*contract.LogTopicGas + 32*contract.LogDataGas
+)
+
+var (
+	// replayProtectionKey is the storage slot of the warp precompile set when
+	// replay protection is enabled by its config.
+	replayProtectionKey = common.BytesToHash([]byte("replayProtection"))
+	trueValue           = common.BytesToHash([]byte{1})
+
+	errMessageAlreadyConsumed = errors.New("warp message already consumed")
+	errNoVerifiedWarpMessage  = errors.New("no verified warp message at index")
+	errInvalidMessageIDInput  = errors.New("invalid messageID input")
+)
+
+// consumedMessageKey returns the storage slot recording that [consumer] consumed [messageID].
+// Consumption is tracked per consumer, so a message delivered to one contract cannot be
+// consumed by another contract to prevent its delivery.
+func consumedMessageKey(consumer common.Address, messageID common.Hash) common.Hash {
+	return crypto.Keccak256Hash(consumer.Bytes(), messageID.Bytes())
+}
+
+// isReplayProtectionActivated returns true if the warp precompile was configured with replay protection.
+func isReplayProtectionActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetStateDB().GetState(ContractAddress, replayProtectionKey) == trueValue
+}
+
+// UnpackIsMessageConsumedInput attempts to unpack [input] into the common.Hash type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackIsMessageConsumedInput(input []byte) (common.Hash, error) {
+	res, err := WarpABI.UnpackInput("isMessageConsumed", input, false)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(common.Hash)).(*common.Hash)
+	return unpacked, nil
+}
+
+// PackIsMessageConsumed packs [messageID] of type common.Hash into the appropriate arguments for isMessageConsumed.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackIsMessageConsumed(messageID common.Hash) ([]byte, error) {
+	return WarpABI.Pack("isMessageConsumed", messageID)
+}
+
+// PackIsMessageConsumedOutput attempts to pack given [consumed] of type bool
+// to conform the ABI outputs.
+func PackIsMessageConsumedOutput(consumed bool) ([]byte, error) {
+	return WarpABI.PackOutput("isMessageConsumed", consumed)
+}
+
+// isMessageConsumed returns whether the caller consumed the warp message with the given messageID
+// through consumeWarpMessage.
+func isMessageConsumed(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, IsMessageConsumedGasCost); err != nil {
+		return nil, 0, err
+	}
+	messageID, err := UnpackIsMessageConsumedInput(input)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errInvalidMessageIDInput, err)
+	}
+	consumed := accessibleState.GetStateDB().GetState(ContractAddress, consumedMessageKey(caller, messageID)) == trueValue
+	packedOutput, err := PackIsMessageConsumedOutput(consumed)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
+
+// UnpackConsumeWarpMessageInput attempts to unpack [input] into the uint32 type argument
+// assumes that [input] does not include selector (omits first 4 func signature bytes)
+func UnpackConsumeWarpMessageInput(input []byte) (uint32, error) {
+	res, err := WarpABI.UnpackInput("consumeWarpMessage", input, false)
+	if err != nil {
+		return 0, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(uint32)).(*uint32)
+	return unpacked, nil
+}
+
+// PackConsumeWarpMessage packs [index] of type uint32 into the appropriate arguments for consumeWarpMessage.
+// the packed bytes include selector (first 4 func signature bytes).
+// This function is mostly used for tests.
+func PackConsumeWarpMessage(index uint32) ([]byte, error) {
+	return WarpABI.Pack("consumeWarpMessage", index)
+}
+
+// PackConsumeWarpMessageOutput attempts to pack given [message] of type WarpMessage
+// to conform the ABI outputs.
+func PackConsumeWarpMessageOutput(message WarpMessage) ([]byte, error) {
+	return WarpABI.PackOutput("consumeWarpMessage", message)
+}
+
+// UnpackConsumeWarpMessageOutput attempts to unpack [output] as WarpMessage
+// assumes that [output] does not include selector (omits first 4 func signature bytes)
+func UnpackConsumeWarpMessageOutput(output []byte) (WarpMessage, error) {
+	res, err := WarpABI.Unpack("consumeWarpMessage", output)
+	if err != nil {
+		return WarpMessage{}, err
+	}
+	unpacked := *abi.ConvertType(res[0], new(WarpMessage)).(*WarpMessage)
+	return unpacked, nil
+}
+
+// PackWarpMessageConsumedEvent packs the given arguments into WarpMessageConsumed events including topics and data.
+func PackWarpMessageConsumedEvent(messageID common.Hash, consumer common.Address, sourceChainID common.Hash) ([]common.Hash, []byte, error) {
+	return WarpABI.PackEvent("WarpMessageConsumed", messageID, consumer, sourceChainID)
+}
+
+// consumeWarpMessage returns the pre-verified warp message at the given index like getVerifiedWarpMessage,
+// and records that the caller consumed it. Reverts if the message is missing or invalid, or if the caller
+// already consumed it.
+func consumeWarpMessage(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetVerifiedWarpMessageBaseCost+ConsumeWarpMessageGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vmerrs.ErrWriteProtection
+	}
+	state := accessibleState.GetStateDB()
+	warpIndex, err := UnpackConsumeWarpMessageInput(input)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errInvalidIndexInput, err)
+	}
+	warpMessage, remainingGas, err := loadVerifiedWarpMessage(accessibleState, warpIndex, remainingGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if warpMessage == nil {
+		return nil, remainingGas, fmt.Errorf("%w: %d", errNoVerifiedWarpMessage, warpIndex)
+	}
+	addressedPayload, err := payload.ParseAddressedCall(warpMessage.UnsignedMessage.Payload)
+	if err != nil {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errInvalidAddressedPayload, err)
+	}
+
+	messageID := common.Hash(warpMessage.UnsignedMessage.ID())
+	key := consumedMessageKey(caller, messageID)
+	if state.GetState(ContractAddress, key) == trueValue {
+		return nil, remainingGas, fmt.Errorf("%w: %s", errMessageAlreadyConsumed, messageID)
+	}
+	state.SetState(ContractAddress, key, trueValue)
+
+	sourceChainID := common.Hash(warpMessage.SourceChainID)
+	topics, data, err := PackWarpMessageConsumedEvent(messageID, caller, sourceChainID)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	state.AddLog(
+		ContractAddress,
+		topics,
+		data,
+		accessibleState.GetBlockContext().Number().Uint64(),
+	)
+
+	packedOutput, err := PackConsumeWarpMessageOutput(WarpMessage{
+		SourceChainID:       sourceChainID,
+		OriginSenderAddress: common.BytesToAddress(addressedPayload.SourceAddress),
+		Payload:             addressedPayload.Payload,
+	})
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	return packedOutput, remainingGas, nil
+}
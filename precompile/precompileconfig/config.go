@@ -61,7 +61,7 @@ type SharedMemoryWriter interface {
 }
 
 type WarpMessageWriter interface {
-	AddMessage(unsignedMessage *warp.UnsignedMessage) error
+	AddMessage(unsignedMessage *warp.UnsignedMessage, blockNumber uint64, blockTimestamp uint64) error
 }
 
 // AcceptContext defines the context passed in to a precompileconfig's Accepter
@@ -69,6 +69,8 @@ type AcceptContext struct {
 	SnowCtx      *snow.Context
 	SharedMemory SharedMemoryWriter
 	Warp         WarpMessageWriter
+	// BlockTimestamp is the timestamp of the block being accepted.
+	BlockTimestamp uint64
 }
 
 // Accepter is an optional interface for StatefulPrecompiledContracts to implement.
@@ -0,0 +1,397 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	anonymousClientName = "anonymous"
+	jwtAlgorithm        = "HS256"
+	bearerPrefix        = "Bearer "
+	apiKeyHeader        = "X-API-Key"
+	jwtClientPrefix     = "jwt-"
+
+	// maxJWTClients is the number of clients authenticated with a JWT whose
+	// budgets are kept. The least recently seen client is evicted beyond it,
+	// and starts over with full budgets.
+	maxJWTClients = 4096
+	// maxAnonymousClients is the number of hosts sending requests without
+	// credentials whose budgets are kept, evicted as for JWT clients.
+	maxAnonymousClients = 4096
+)
+
+var (
+	// clientNameRegex matches the names of clients, which are part of metric names.
+	clientNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	// invalidClientNameChars matches the characters of a JWT subject that are
+	// replaced in the name of its client.
+	invalidClientNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+	errMissingCredentials = errors.New("missing credentials")
+	errInvalidAPIKey      = errors.New("invalid API key")
+	errInvalidJWT         = errors.New("invalid JWT")
+	errExpiredJWT         = errors.New("expired JWT")
+)
+
+// AccessTier sets the methods the clients of a tier may call and the budgets
+// of each client of the tier.
+type AccessTier struct {
+	Name string
+	// AllowedMethods are the patterns of the methods the clients may call.
+	// A pattern is either a method name or a prefix followed by "*", e.g.
+	// "eth_*". All methods are allowed if it is empty.
+	AllowedMethods []string
+	// DeniedMethods are the patterns of the methods the clients may not call,
+	// even if they match [AllowedMethods].
+	DeniedMethods []string
+	// RequestsPerSecond is the rate at which the request budget of a client
+	// refills, up to [RequestBurst] requests. Requests are not limited if it is 0.
+	RequestsPerSecond float64
+	RequestBurst      int
+	// CPURefillRate is the CPU time per second refilling the CPU budget of a
+	// client, up to [CPUMaxStored]. As for websocket connections, CPU time is
+	// only limited if the server has a maximum duration of at most [CPUMaxStored].
+	CPURefillRate time.Duration
+	CPUMaxStored  time.Duration
+}
+
+// APIKey is the secret authenticating a client of a tier.
+type APIKey struct {
+	Name string // Identifies the client in logs and metrics
+	Key  string
+	Tier string
+}
+
+// AuthConfig configures the authentication of the clients of a server.
+type AuthConfig struct {
+	Tiers []AccessTier
+	Keys  []APIKey
+	// JWTSecret is the HS256 secret of the JWTs accepted as credentials. The
+	// "sub" claim of a JWT identifies the client and the "tier" claim sets its
+	// tier. The metrics of these clients are aggregated by tier. JWTs are
+	// rejected if it is empty.
+	JWTSecret []byte
+	// AnonymousTier is the tier of requests without credentials, whose budgets
+	// are kept per remote host. Requests without credentials are rejected if it
+	// is empty.
+	AnonymousTier string
+}
+
+// Validate returns an error if [c] is not a valid authentication config.
+func (c AuthConfig) Validate() error {
+	tiers := make(map[string]struct{}, len(c.Tiers))
+	for _, tier := range c.Tiers {
+		if tier.Name == "" {
+			return errors.New("access tier without a name")
+		}
+		if _, ok := tiers[tier.Name]; ok {
+			return fmt.Errorf("duplicate access tier %q", tier.Name)
+		}
+		tiers[tier.Name] = struct{}{}
+		for _, patterns := range [][]string{tier.AllowedMethods, tier.DeniedMethods} {
+			for _, pattern := range patterns {
+				if pattern == "" || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+					return fmt.Errorf("access tier %q has invalid method pattern %q", tier.Name, pattern)
+				}
+			}
+		}
+		if tier.RequestsPerSecond < 0 {
+			return fmt.Errorf("access tier %q has negative requests per second %f", tier.Name, tier.RequestsPerSecond)
+		}
+		if tier.RequestsPerSecond > 0 && tier.RequestBurst <= 0 {
+			return fmt.Errorf("access tier %q limits requests without a positive request burst", tier.Name)
+		}
+		if tier.CPURefillRate < 0 || tier.CPUMaxStored < 0 {
+			return fmt.Errorf("access tier %q has a negative CPU budget", tier.Name)
+		}
+	}
+
+	names := make(map[string]struct{}, len(c.Keys))
+	keys := make(map[string]struct{}, len(c.Keys))
+	for _, key := range c.Keys {
+		if !clientNameRegex.MatchString(key.Name) || key.Name == anonymousClientName || strings.HasPrefix(key.Name, jwtClientPrefix) {
+			return fmt.Errorf("invalid API key name %q", key.Name)
+		}
+		if _, ok := names[key.Name]; ok {
+			return fmt.Errorf("duplicate API key name %q", key.Name)
+		}
+		names[key.Name] = struct{}{}
+		if key.Key == "" {
+			return fmt.Errorf("API key %q is empty", key.Name)
+		}
+		if _, ok := keys[key.Key]; ok {
+			return fmt.Errorf("API key %q is used by another client", key.Name)
+		}
+		keys[key.Key] = struct{}{}
+		if _, ok := tiers[key.Tier]; !ok {
+			return fmt.Errorf("API key %q has unknown access tier %q", key.Name, key.Tier)
+		}
+	}
+
+	if c.AnonymousTier != "" {
+		if _, ok := tiers[c.AnonymousTier]; !ok {
+			return fmt.Errorf("unknown anonymous access tier %q", c.AnonymousTier)
+		}
+	}
+	return nil
+}
+
+// Authenticator identifies the client of each request to a server and
+// enforces the access tier of the client.
+type Authenticator struct {
+	tiers     map[string]*AccessTier
+	keys      map[[sha256.Size]byte]*authClient // Clients by the hash of their API key
+	jwtSecret []byte
+	anonymous *AccessTier // Tier of the requests without credentials, if allowed
+
+	lock             sync.Mutex
+	jwtClients       *lru.BasicLRU[string, *authClient] // Clients authenticated with a JWT by subject
+	anonymousClients *lru.BasicLRU[string, *authClient] // Clients without credentials by remote host
+}
+
+// NewAuthenticator returns an Authenticator enforcing [config].
+func NewAuthenticator(config AuthConfig) (*Authenticator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	jwtClients := lru.NewBasicLRU[string, *authClient](maxJWTClients)
+	anonymousClients := lru.NewBasicLRU[string, *authClient](maxAnonymousClients)
+	a := &Authenticator{
+		tiers:            make(map[string]*AccessTier, len(config.Tiers)),
+		keys:             make(map[[sha256.Size]byte]*authClient, len(config.Keys)),
+		jwtSecret:        config.JWTSecret,
+		jwtClients:       &jwtClients,
+		anonymousClients: &anonymousClients,
+	}
+	for i := range config.Tiers {
+		tier := config.Tiers[i]
+		a.tiers[tier.Name] = &tier
+	}
+	for _, key := range config.Keys {
+		a.keys[sha256.Sum256([]byte(key.Key))] = newAuthClient(key.Name, key.Name, a.tiers[key.Tier])
+	}
+	if config.AnonymousTier != "" {
+		a.anonymous = a.tiers[config.AnonymousTier]
+	}
+	return a, nil
+}
+
+// authenticate returns the client of [r]. The credentials are either an API key
+// or a JWT passed as a bearer token, or an API key in the X-API-Key header.
+func (a *Authenticator) authenticate(r *http.Request) (*authClient, error) {
+	var token string
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		if !strings.HasPrefix(authorization, bearerPrefix) {
+			return nil, errMissingCredentials
+		}
+		token = strings.TrimPrefix(authorization, bearerPrefix)
+	} else {
+		token = r.Header.Get(apiKeyHeader)
+	}
+
+	switch {
+	case token == "" && a.anonymous != nil:
+		return a.authenticateAnonymous(r.RemoteAddr), nil
+	case token == "":
+		return nil, errMissingCredentials
+	case len(a.jwtSecret) > 0 && strings.Count(token, ".") == 2:
+		return a.authenticateJWT(token)
+	}
+	client, ok := a.keys[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+	return client, nil
+}
+
+// authenticateAnonymous returns the client of the requests without credentials
+// sent from the host of [remote]. Each host has its own budgets, so a single
+// host cannot exhaust the budgets of the other anonymous clients.
+func (a *Authenticator) authenticateAnonymous(remote string) *authClient {
+	host := remoteHost(remote)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	client, ok := a.anonymousClients.Get(host)
+	if !ok {
+		client = newAuthClient(anonymousClientName, anonymousClientName, a.anonymous)
+		a.anonymousClients.Add(host, client)
+	}
+	return client
+}
+
+// jwtClaims are the claims of a JWT used by the Authenticator.
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Tier      string `json:"tier"`
+	Expiry    *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// authenticateJWT verifies [token] and returns the client it identifies.
+func (a *Authenticator) authenticateJWT(token string) (*authClient, error) {
+	parts := strings.Split(token, ".")
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Algorithm != jwtAlgorithm {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", errInvalidJWT, header.Algorithm)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidJWT, err)
+	}
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: invalid signature", errInvalidJWT)
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if claims.Expiry != nil && now >= *claims.Expiry {
+		return nil, errExpiredJWT
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return nil, fmt.Errorf("%w: not valid before %d", errInvalidJWT, *claims.NotBefore)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", errInvalidJWT)
+	}
+	tier, ok := a.tiers[claims.Tier]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown access tier %q", errInvalidJWT, claims.Tier)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// A client keeps its budgets across tokens, unless its tier changes. The
+	// subjects are chosen by the issuer of the tokens, so the metrics of the
+	// clients are aggregated by tier.
+	client, ok := a.jwtClients.Get(claims.Subject)
+	if !ok || client.tier != tier {
+		client = newAuthClient(
+			jwtClientPrefix+invalidClientNameChars.ReplaceAllString(claims.Subject, "_"),
+			jwtClientPrefix+invalidClientNameChars.ReplaceAllString(tier.Name, "_"),
+			tier,
+		)
+		a.jwtClients.Add(claims.Subject, client)
+	}
+	return client, nil
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidJWT, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %s", errInvalidJWT, err)
+	}
+	return nil
+}
+
+// authClient is an authenticated client and its budgets, which are shared by
+// all of its connections.
+type authClient struct {
+	name           string
+	metricsName    string // Name of the client in metrics, shared by the JWT clients of a tier
+	tier           *AccessTier
+	requestLimiter *rate.Limiter
+	cpuLimiter     *rate.Limiter
+}
+
+func newAuthClient(name string, metricsName string, tier *AccessTier) *authClient {
+	c := &authClient{
+		name:        name,
+		metricsName: metricsName,
+		tier:        tier,
+	}
+	if tier.RequestsPerSecond > 0 {
+		c.requestLimiter = rate.NewLimiter(rate.Limit(tier.RequestsPerSecond), tier.RequestBurst)
+	}
+	if tier.CPURefillRate > 0 && tier.CPUMaxStored > 0 {
+		c.cpuLimiter = rate.NewLimiter(rate.Limit(tier.CPURefillRate), int(tier.CPUMaxStored))
+	}
+	return c
+}
+
+// authorize returns an error if the client may not call [method] now.
+func (c *authClient) authorize(method string) error {
+	if matchMethod(c.tier.DeniedMethods, method) ||
+		(len(c.tier.AllowedMethods) > 0 && !matchMethod(c.tier.AllowedMethods, method)) {
+		updateClientRequests(c.metricsName, "denied")
+		return &methodNotAllowedError{method: method}
+	}
+	if c.requestLimiter == nil {
+		return nil
+	}
+	reservation := c.requestLimiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		updateClientRequests(c.metricsName, "limited")
+		return &limitExceededError{message: "request rate limit exceeded", retryAfter: delay}
+	}
+	return nil
+}
+
+// matchMethod returns true if [method] matches any of [patterns].
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if pattern == method {
+			return true
+		}
+	}
+	return false
+}
+
+type authClientContextKey struct{}
+
+// authenticatedCodec is a codec of a connection opened by an authenticated client.
+type authenticatedCodec struct {
+	ServerCodec
+	client *authClient
+}
+
+// withAuthClient returns a copy of [ctx] carrying the client of a connection.
+func withAuthClient(ctx context.Context, client *authClient) context.Context {
+	if client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, authClientContextKey{}, client)
+}
+
+// writeUnauthorized responds to a request failing authentication with [err].
+func writeUnauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+}
@@ -0,0 +1,341 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testJWTSecret = []byte("secret")
+	testAuthTiers = []AccessTier{
+		{
+			Name:              "free",
+			AllowedMethods:    []string{"test_*"},
+			DeniedMethods:     []string{"test_panic"},
+			RequestsPerSecond: 1e-9,
+			RequestBurst:      2,
+		},
+		{
+			Name: "paid",
+		},
+	}
+	testAuthKeys = []APIKey{
+		{Name: "alice", Key: "free-key", Tier: "free"},
+		{Name: "bob", Key: "paid-key", Tier: "paid"},
+	}
+)
+
+// signTestJWT returns a JWT with [claims] signed with [secret].
+func signTestJWT(t *testing.T, secret []byte, alg string, claims jwtClaims) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		config      AuthConfig
+		expectedErr string
+	}{
+		"valid": {
+			config: AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys, AnonymousTier: "free"},
+		},
+		"duplicate tier": {
+			config:      AuthConfig{Tiers: []AccessTier{{Name: "free"}, {Name: "free"}}},
+			expectedErr: `duplicate access tier "free"`,
+		},
+		"invalid method pattern": {
+			config:      AuthConfig{Tiers: []AccessTier{{Name: "free", DeniedMethods: []string{"*_call"}}}},
+			expectedErr: `invalid method pattern "*_call"`,
+		},
+		"request rate without burst": {
+			config:      AuthConfig{Tiers: []AccessTier{{Name: "free", RequestsPerSecond: 1}}},
+			expectedErr: "without a positive request burst",
+		},
+		"invalid key name": {
+			config:      AuthConfig{Tiers: testAuthTiers, Keys: []APIKey{{Name: "alice/1", Key: "key", Tier: "free"}}},
+			expectedErr: `invalid API key name "alice/1"`,
+		},
+		"duplicate key": {
+			config:      AuthConfig{Tiers: testAuthTiers, Keys: []APIKey{{Name: "alice", Key: "key", Tier: "free"}, {Name: "bob", Key: "key", Tier: "paid"}}},
+			expectedErr: `API key "bob" is used by another client`,
+		},
+		"unknown key tier": {
+			config:      AuthConfig{Tiers: testAuthTiers, Keys: []APIKey{{Name: "alice", Key: "key", Tier: "gold"}}},
+			expectedErr: `unknown access tier "gold"`,
+		},
+		"unknown anonymous tier": {
+			config:      AuthConfig{Tiers: testAuthTiers, AnonymousTier: "gold"},
+			expectedErr: `unknown anonymous access tier "gold"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys, JWTSecret: testJWTSecret})
+	require.NoError(t, err)
+
+	past, future := time.Now().Add(-time.Hour).Unix(), time.Now().Add(time.Hour).Unix()
+	tests := map[string]struct {
+		header       http.Header
+		expectedName string
+		expectedErr  error
+	}{
+		"bearer API key": {
+			header:       http.Header{"Authorization": {"Bearer free-key"}},
+			expectedName: "alice",
+		},
+		"API key header": {
+			header:       http.Header{"X-Api-Key": {"paid-key"}},
+			expectedName: "bob",
+		},
+		"unknown API key": {
+			header:      http.Header{"Authorization": {"Bearer other-key"}},
+			expectedErr: errInvalidAPIKey,
+		},
+		"missing credentials": {
+			header:      http.Header{},
+			expectedErr: errMissingCredentials,
+		},
+		"JWT": {
+			header:       http.Header{"Authorization": {"Bearer " + signTestJWT(t, testJWTSecret, jwtAlgorithm, jwtClaims{Subject: "carol@example.com", Tier: "paid", Expiry: &future})}},
+			expectedName: "jwt-carol_example_com",
+		},
+		"expired JWT": {
+			header:      http.Header{"Authorization": {"Bearer " + signTestJWT(t, testJWTSecret, jwtAlgorithm, jwtClaims{Subject: "carol", Tier: "paid", Expiry: &past})}},
+			expectedErr: errExpiredJWT,
+		},
+		"JWT not valid yet": {
+			header:      http.Header{"Authorization": {"Bearer " + signTestJWT(t, testJWTSecret, jwtAlgorithm, jwtClaims{Subject: "carol", Tier: "paid", NotBefore: &future})}},
+			expectedErr: errInvalidJWT,
+		},
+		"JWT with wrong secret": {
+			header:      http.Header{"Authorization": {"Bearer " + signTestJWT(t, []byte("other"), jwtAlgorithm, jwtClaims{Subject: "carol", Tier: "paid"})}},
+			expectedErr: errInvalidJWT,
+		},
+		"JWT with unsupported algorithm": {
+			header:      http.Header{"Authorization": {"Bearer " + signTestJWT(t, testJWTSecret, "none", jwtClaims{Subject: "carol", Tier: "paid"})}},
+			expectedErr: errInvalidJWT,
+		},
+		"JWT with unknown tier": {
+			header:      http.Header{"Authorization": {"Bearer " + signTestJWT(t, testJWTSecret, jwtAlgorithm, jwtClaims{Subject: "carol", Tier: "gold"})}},
+			expectedErr: errInvalidJWT,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "http://url.com", nil)
+			r.Header = test.header
+			client, err := auth.authenticate(r)
+			require.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				require.Equal(t, test.expectedName, client.name)
+			}
+		})
+	}
+}
+
+func TestJWTClients(t *testing.T) {
+	require := require.New(t)
+
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, JWTSecret: testJWTSecret})
+	require.NoError(err)
+	authenticate := func(subject string, tier string) *authClient {
+		r := httptest.NewRequest(http.MethodPost, "http://url.com", nil)
+		r.Header.Set("Authorization", "Bearer "+signTestJWT(t, testJWTSecret, jwtAlgorithm, jwtClaims{Subject: subject, Tier: tier}))
+		client, err := auth.authenticate(r)
+		require.NoError(err)
+		return client
+	}
+
+	// The clients of a tier have their own budgets and share their metrics.
+	carol, dave := authenticate("carol", "free"), authenticate("dave", "free")
+	require.NotSame(carol, dave)
+	require.Equal("jwt-carol", carol.name)
+	require.Equal("jwt-free", carol.metricsName)
+	require.Equal(carol.metricsName, dave.metricsName)
+	require.Same(carol, authenticate("carol", "free"))
+	require.Equal("jwt-paid", authenticate("carol", "paid").metricsName)
+
+	// The least recently seen clients are evicted.
+	for i := 0; i < maxJWTClients; i++ {
+		authenticate(fmt.Sprintf("client-%d", i), "free")
+	}
+	require.Equal(maxJWTClients, auth.jwtClients.Len())
+	require.NotSame(dave, authenticate("dave", "free"))
+}
+
+func TestAnonymousClients(t *testing.T) {
+	require := require.New(t)
+
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, AnonymousTier: "free"})
+	require.NoError(err)
+	authenticate := func(remote string) *authClient {
+		r := httptest.NewRequest(http.MethodPost, "http://url.com", nil)
+		r.RemoteAddr = remote
+		client, err := auth.authenticate(r)
+		require.NoError(err)
+		return client
+	}
+
+	// Each host has its own budgets, shared by its connections.
+	first, second := authenticate("10.0.0.1:1234"), authenticate("10.0.0.2:1234")
+	require.NotSame(first, second)
+	require.Same(first, authenticate("10.0.0.1:5678"))
+	require.Equal(anonymousClientName, first.metricsName)
+
+	// An anonymous client exhausting its request budget does not limit other
+	// anonymous clients.
+	require.NoError(first.authorize("test_echo"))
+	require.NoError(first.authorize("test_echo"))
+	require.Error(first.authorize("test_echo"))
+	require.NoError(second.authorize("test_echo"))
+
+	// The least recently seen hosts are evicted.
+	for i := 0; i < maxAnonymousClients; i++ {
+		authenticate(fmt.Sprintf("10.1.%d.%d:1234", i/256, i%256))
+	}
+	require.Equal(maxAnonymousClients, auth.anonymousClients.Len())
+	require.NotSame(second, authenticate("10.0.0.2:1234"))
+}
+
+func TestHTTPAuth(t *testing.T) {
+	s := newTestServer()
+	defer s.Stop()
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys})
+	require.NoError(t, err)
+	s.SetAuthenticator(auth)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	// Requests without credentials are rejected.
+	c, err := Dial(ts.URL)
+	require.NoError(t, err)
+	err = c.Call(nil, "test_null")
+	var httpErr HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusUnauthorized, httpErr.StatusCode)
+
+	// The free tier may only call the allowed methods, within its request budget.
+	c.SetHeader("Authorization", "Bearer free-key")
+	require.ErrorContains(t, c.Call(nil, "rpc_modules"), "the method rpc_modules is not allowed")
+	require.ErrorContains(t, c.Call(nil, "test_panic"), "the method test_panic is not allowed")
+	require.NoError(t, c.Call(nil, "test_null"))
+	require.NoError(t, c.Call(nil, "test_null"))
+	err = c.Call(nil, "test_null")
+	var rpcErr Error
+	require.True(t, errors.As(err, &rpcErr))
+	require.Equal(t, errcodeLimitExceeded, rpcErr.ErrorCode())
+
+	// The paid tier is not limited.
+	c.SetHeader("Authorization", "Bearer paid-key")
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.Call(nil, "test_null"))
+	}
+	require.NoError(t, c.Call(nil, "rpc_modules"))
+}
+
+func TestWrapHTTPHandler(t *testing.T) {
+	require := require.New(t)
+
+	s := NewServer(time.Minute)
+	defer s.Stop()
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys})
+	require.NoError(err)
+	s.SetAuthenticator(auth)
+	h := s.WrapHTTPHandler("test_http", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request expires after the maximum duration of the server.
+		_, ok := ContextRequestTimeout(r.Context())
+		require.True(ok)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "http://url.com", nil)
+		if key != "" {
+			r.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(http.StatusUnauthorized, serve("").Code)
+
+	// The free tier may call test_http twice before exceeding its budget.
+	require.Equal(http.StatusNoContent, serve("free-key").Code)
+	require.Equal(http.StatusNoContent, serve("free-key").Code)
+	w := serve("free-key")
+	require.Equal(http.StatusTooManyRequests, w.Code)
+	require.NotEmpty(w.Header().Get("Retry-After"))
+
+	require.Equal(http.StatusNoContent, serve("paid-key").Code)
+
+	// Methods outside of the tier of the client are forbidden.
+	denied := s.WrapHTTPHandler("test_panic", http.NotFoundHandler())
+	r := httptest.NewRequest(http.MethodPost, "http://url.com", nil)
+	r.Header.Set("Authorization", "Bearer paid-key")
+	w = httptest.NewRecorder()
+	denied.ServeHTTP(w, r)
+	require.Equal(http.StatusNotFound, w.Code)
+	r.Header.Set("Authorization", "Bearer free-key")
+	w = httptest.NewRecorder()
+	denied.ServeHTTP(w, r)
+	require.Equal(http.StatusForbidden, w.Code)
+}
+
+func TestWebsocketAuth(t *testing.T) {
+	s := newTestServer()
+	defer s.Stop()
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys, AnonymousTier: "free"})
+	require.NoError(t, err)
+	s.SetAuthenticator(auth)
+	ts := httptest.NewServer(s.WebsocketHandler([]string{"*"}))
+	defer ts.Close()
+	wsURL := "ws:" + strings.TrimPrefix(ts.URL, "http:")
+
+	// Invalid credentials fail the handshake.
+	_, err = DialOptions(context.Background(), wsURL, WithHeader("Authorization", "Bearer other-key"))
+	require.Error(t, err)
+
+	// Connections without credentials are served in the anonymous tier.
+	c, err := DialOptions(context.Background(), wsURL)
+	require.NoError(t, err)
+	defer c.Close()
+	require.ErrorContains(t, c.Call(nil, "rpc_modules"), "the method rpc_modules is not allowed")
+	require.NoError(t, c.Call(nil, "test_null"))
+
+	c, err = DialOptions(context.Background(), wsURL, WithHeader("Authorization", "Bearer paid-key"))
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, c.Call(nil, "rpc_modules"))
+}
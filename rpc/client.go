@@ -97,6 +97,7 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -128,12 +129,17 @@ func (c *Client) newClientConn(conn ServerCodec, apiMaxDuration, refillRate, max
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
+	if codec, ok := conn.(*authenticatedCodec); ok {
+		ctx = withAuthClient(ctx, codec.client)
+	}
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
 
 	// When [apiMaxDuration] or [refillRate]/[maxStored] is 0 (as is the case for
 	// all client invocations of this function), it is ignored.
 	handler.deadlineContext = apiMaxDuration
 	handler.addLimiter(refillRate, maxStored)
+	handler.addClientLimiter()
+	handler.rateLimiter = c.rateLimiter
 	return &clientConn{conn, handler}
 }
 
@@ -261,6 +267,7 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig,
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		rateLimiter:          cfg.rateLimiter,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -50,6 +50,7 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *RateLimiter
 }
 
 func (cfg *clientConfig) initHeaders() {
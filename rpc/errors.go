@@ -26,7 +26,10 @@
 
 package rpc
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // HTTPError is returned by client operations when the HTTP status code of the
 // response is not a 2xx status.
@@ -65,12 +68,17 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(methodNotAllowedError)
+	_ Error = new(limitExceededError)
+
+	_ DataError = new(limitExceededError)
 )
 
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
 	errcodeResponseTooLarge = -32003
+	errcodeLimitExceeded    = -32005
 	errcodePanic            = -32603
 	errcodeMarshalError     = -32603
 
@@ -164,3 +172,32 @@ type internalServerError struct {
 func (e *internalServerError) ErrorCode() int { return e.code }
 
 func (e *internalServerError) Error() string { return e.message }
+
+// methodNotAllowedError is returned when the access tier of a client does not
+// allow the method.
+type methodNotAllowedError struct{ method string }
+
+func (e *methodNotAllowedError) ErrorCode() int { return -32601 }
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("the method %s is not allowed", e.method)
+}
+
+// limitExceededError is returned when a client exceeds its budget. If the
+// budget refills, [retryAfter] is the time until it covers the call.
+type limitExceededError struct {
+	message    string
+	retryAfter time.Duration
+}
+
+func (e *limitExceededError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *limitExceededError) Error() string { return e.message }
+
+// ErrorData returns the number of seconds after which the call may be retried.
+func (e *limitExceededError) ErrorData() interface{} {
+	if e.retryAfter <= 0 {
+		return nil
+	}
+	return map[string]float64{"retryAfter": e.retryAfter.Seconds()}
+}
@@ -80,6 +80,8 @@ type handler struct {
 
 	deadlineContext time.Duration // limits execution after some time.Duration
 	limiter         *rate.Limiter
+	client          *authClient  // set if the connection is authenticated
+	rateLimiter     *RateLimiter // charges the cost of calls if set
 }
 
 type callProc struct {
@@ -108,6 +110,10 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
 	}
+	if client, ok := connCtx.Value(authClientContextKey{}).(*authClient); ok {
+		h.client = client
+		h.log = h.log.New("client", client.name)
+	}
 	h.unsubscribeCb = newCallback(reflect.Value{}, reflect.ValueOf(h.unsubscribe))
 	return h
 }
@@ -193,6 +199,20 @@ func (h *handler) addLimiter(refillRate, maxStored time.Duration) {
 	h.limiter = rate.NewLimiter(rate.Limit(refillRate), int(maxStored))
 }
 
+// addClientLimiter replaces the rate limiter of the handler with the CPU
+// budget of its authenticated client, which is shared by all connections of
+// the client. As in [addLimiter], the budget is ignored if it would make the
+// rate limiting trivial.
+func (h *handler) addClientLimiter() {
+	if h.client == nil || h.client.cpuLimiter == nil {
+		return
+	}
+	if h.client.tier.CPUMaxStored < h.deadlineContext || h.deadlineContext <= 0 {
+		return
+	}
+	h.limiter = h.client.cpuLimiter
+}
+
 // handleBatch executes all messages in a batch and returns the responses.
 func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	// Emit error response for empty batches:
@@ -589,6 +609,11 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if h.client != nil && !msg.isUnsubscribe() {
+		if err := h.client.authorize(msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -601,6 +626,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if h.rateLimiter != nil && callb != h.unsubscribeCb {
+		if err := h.rateLimiter.charge(h.rateLimitKey(), msg.Method, msg.Params); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
@@ -622,6 +652,9 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 		if metrics.EnabledExpensive {
 			updateServeTimeHistogram(msg.Method, answer.Error == nil, time.Since(start))
 		}
+		if h.client != nil {
+			updateClientServeTime(h.client.metricsName, answer.Error == nil, time.Since(start))
+		}
 	}
 
 	return answer
@@ -643,6 +676,11 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if h.rateLimiter != nil {
+		if err := h.rateLimiter.charge(h.rateLimitKey(), msg.Method, msg.Params); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
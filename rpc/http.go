@@ -330,6 +330,14 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), code)
 		return
 	}
+	var client *authClient
+	if s.auth != nil {
+		var err error
+		if client, err = s.auth.authenticate(r); err != nil {
+			writeUnauthorized(w, err)
+			return
+		}
+	}
 
 	// Create request-scoped context.
 	connInfo := PeerInfo{Transport: "http", RemoteAddr: r.RemoteAddr}
@@ -339,6 +347,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
+	ctx = withAuthClient(ctx, client)
 
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
@@ -349,6 +358,56 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.serveSingleRequest(ctx, codec)
 }
 
+// WrapHTTPHandler returns a handler serving requests with [next], which serves
+// them outside of the server, such as GraphQL queries. Each request is treated
+// as a call to [method]: its client is authenticated and authorized by the
+// authenticator of the server, its cost is charged by the rate limiter of the
+// server, and its context expires after the maximum duration of the server.
+func (s *Server) WrapHTTPHandler(method string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var client *authClient
+		if s.auth != nil {
+			var err error
+			if client, err = s.auth.authenticate(r); err != nil {
+				writeUnauthorized(w, err)
+				return
+			}
+			if err := client.authorize(method); err != nil {
+				writeLimitError(w, err)
+				return
+			}
+		}
+		if s.rateLimiter != nil {
+			if err := s.rateLimiter.charge(rateLimitKey(client, r.RemoteAddr), method, nil); err != nil {
+				writeLimitError(w, err)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		if s.maximumDuration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.maximumDuration)
+			defer cancel()
+		}
+		next.ServeHTTP(w, r.WithContext(withAuthClient(ctx, client)))
+	})
+}
+
+// writeLimitError responds to a request denied by its access tier or rate
+// limited with [err].
+func writeLimitError(w http.ResponseWriter, err error) {
+	var limitErr *limitExceededError
+	if !errors.As(err, &limitErr) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if limitErr.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.retryAfter.Seconds()))))
+	}
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
+
 // validateRequest returns a non-zero response code and error message if the
 // request is invalid.
 func validateRequest(r *http.Request) (int, error) {
@@ -42,6 +42,13 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	// clientName is the prefix of the per-client request counters and timers.
+	clientName = "rpc/clients"
+
+	// rateLimitName is the prefix of the per-method rate limiting counters.
+	rateLimitName         = "rpc/ratelimit"
+	rateLimitClientsGauge = metrics.NewRegisteredGauge("rpc/ratelimit/clients", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
@@ -58,3 +65,29 @@ func updateServeTimeHistogram(method string, success bool, elapsed time.Duration
 	}
 	metrics.GetOrRegisterHistogramLazy(h, nil, sampler).Update(elapsed.Microseconds())
 }
+
+// updateClientRequests tracks a request of an authenticated client, where
+// [note] is the outcome of the request.
+func updateClientRequests(client string, note string) {
+	metrics.GetOrRegisterCounter(fmt.Sprintf("%s/%s/%s", clientName, client, note), nil).Inc(1)
+}
+
+// updateClientServeTime tracks the serving time of a request of an authenticated client.
+func updateClientServeTime(client string, success bool, elapsed time.Duration) {
+	note := "success"
+	if !success {
+		note = "failure"
+	}
+	updateClientRequests(client, note)
+	metrics.GetOrRegisterTimer(fmt.Sprintf("%s/%s/duration", clientName, client), nil).Update(elapsed)
+}
+
+// updateRateLimitMetrics tracks the [cost] of a call to [method], which was
+// rejected by the rate limiter unless [allowed].
+func updateRateLimitMetrics(method string, cost float64, allowed bool) {
+	if !allowed {
+		metrics.GetOrRegisterCounter(fmt.Sprintf("%s/%s/limited", rateLimitName, method), nil).Inc(1)
+		return
+	}
+	metrics.GetOrRegisterCounterFloat64(fmt.Sprintf("%s/%s/cost", rateLimitName, method), nil).Inc(cost)
+}
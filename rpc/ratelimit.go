@@ -0,0 +1,205 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// MethodCost sets the cost of the calls to the methods matching a pattern.
+type MethodCost struct {
+	// Method is either a method name or a prefix followed by "*", e.g. "debug_trace*".
+	Method string
+	Cost   float64
+	// CostPerBlock is added to [Cost] for each block in the range of the filter
+	// passed as the first parameter of a call, as in eth_getLogs.
+	CostPerBlock float64
+}
+
+// RateLimitConfig configures the cost based rate limiting of the clients of a server.
+type RateLimitConfig struct {
+	// Costs are the costs of the methods. The first matching cost applies to a
+	// call, or [DefaultCost] if none matches.
+	Costs       []MethodCost
+	DefaultCost float64
+	// RefillRate is the cost per second refilling the budget of a client, up to
+	// [MaxStored]. Calls that cost more than [MaxStored] are always rejected.
+	RefillRate float64
+	MaxStored  float64
+	// MaxClients is the maximum number of clients whose budget is tracked. The
+	// least recently seen client is forgotten, and starts over with a full budget.
+	MaxClients int
+	// CurrentBlock returns the number of the block that block tags, such as
+	// "latest", refer to in filters.
+	CurrentBlock func() uint64
+}
+
+// Validate returns an error if [c] is not a valid rate limit config.
+func (c RateLimitConfig) Validate() error {
+	if c.RefillRate <= 0 {
+		return fmt.Errorf("refill rate must be positive, found %f", c.RefillRate)
+	}
+	if c.MaxStored <= 0 {
+		return fmt.Errorf("max stored must be positive, found %f", c.MaxStored)
+	}
+	if c.DefaultCost < 0 {
+		return fmt.Errorf("default cost must be non-negative, found %f", c.DefaultCost)
+	}
+	if c.MaxClients <= 0 {
+		return fmt.Errorf("max clients must be positive, found %d", c.MaxClients)
+	}
+	for _, cost := range c.Costs {
+		if cost.Method == "" || strings.Contains(strings.TrimSuffix(cost.Method, "*"), "*") {
+			return fmt.Errorf("invalid method pattern %q", cost.Method)
+		}
+		if cost.Cost < 0 || cost.CostPerBlock < 0 {
+			return fmt.Errorf("cost of %q must be non-negative", cost.Method)
+		}
+	}
+	return nil
+}
+
+// RateLimiter charges the cost of each call to the budget of its client, which
+// is either its authenticated client or its IP address, and rejects the calls
+// exceeding the budget.
+type RateLimiter struct {
+	config RateLimitConfig
+
+	lock    sync.Mutex
+	buckets *lru.BasicLRU[string, *tokenBucket]
+}
+
+// NewRateLimiter returns a RateLimiter enforcing [config].
+func NewRateLimiter(config RateLimitConfig) (*RateLimiter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	buckets := lru.NewBasicLRU[string, *tokenBucket](config.MaxClients)
+	return &RateLimiter{
+		config:  config,
+		buckets: &buckets,
+	}, nil
+}
+
+// charge takes the cost of calling [method] with [params] from the budget of
+// [client], or returns an error if the budget does not cover the cost.
+func (l *RateLimiter) charge(client string, method string, params json.RawMessage) error {
+	cost := l.cost(method, params)
+	if cost > l.config.MaxStored {
+		updateRateLimitMetrics(method, cost, false)
+		return &limitExceededError{message: fmt.Sprintf("cost %g of %s exceeds the rate limit budget %g", cost, method, l.config.MaxStored)}
+	}
+
+	l.lock.Lock()
+	bucket, ok := l.buckets.Get(client)
+	if !ok {
+		bucket = &tokenBucket{tokens: l.config.MaxStored, last: time.Now()}
+		l.buckets.Add(client, bucket)
+	}
+	retryAfter, ok := bucket.take(time.Now(), cost, l.config.RefillRate, l.config.MaxStored)
+	rateLimitClientsGauge.Update(int64(l.buckets.Len()))
+	l.lock.Unlock()
+
+	updateRateLimitMetrics(method, cost, ok)
+	if !ok {
+		return &limitExceededError{message: "rate limit exceeded", retryAfter: retryAfter}
+	}
+	return nil
+}
+
+// cost returns the cost of calling [method] with [params].
+func (l *RateLimiter) cost(method string, params json.RawMessage) float64 {
+	for _, cost := range l.config.Costs {
+		if !matchMethod([]string{cost.Method}, method) {
+			continue
+		}
+		if cost.CostPerBlock == 0 {
+			return cost.Cost
+		}
+		return cost.Cost + cost.CostPerBlock*float64(l.blockRange(params))
+	}
+	return l.config.DefaultCost
+}
+
+// blockRange returns the number of blocks in the range of the filter passed as
+// the first of [params]. The range is 1 if there is no such filter, so invalid
+// calls are charged as a single block.
+func (l *RateLimiter) blockRange(params json.RawMessage) uint64 {
+	var args []struct {
+		BlockHash *common.Hash `json:"blockHash"`
+		FromBlock *BlockNumber `json:"fromBlock"`
+		ToBlock   *BlockNumber `json:"toBlock"`
+	}
+	if err := json.Unmarshal(params, &args); err != nil || len(args) == 0 || args[0].BlockHash != nil {
+		return 1
+	}
+	from, to := l.resolve(args[0].FromBlock), l.resolve(args[0].ToBlock)
+	if to < from {
+		return 1
+	}
+	return to - from + 1
+}
+
+// resolve returns the number of the block [number] refers to, where a missing
+// number refers to the latest block.
+func (l *RateLimiter) resolve(number *BlockNumber) uint64 {
+	if number != nil && *number >= 0 {
+		return uint64(*number)
+	}
+	if l.config.CurrentBlock == nil {
+		return 0
+	}
+	return l.config.CurrentBlock()
+}
+
+// tokenBucket is the budget of a client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take removes [cost] tokens from the bucket after refilling it at [rate]
+// tokens per second up to [max] tokens. If the bucket holds less than [cost]
+// tokens, it is left as is and the time until it holds [cost] tokens is returned.
+func (b *tokenBucket) take(now time.Time, cost, rate, max float64) (time.Duration, bool) {
+	b.tokens = math.Min(max, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens < cost {
+		return time.Duration((cost - b.tokens) / rate * float64(time.Second)), false
+	}
+	b.tokens -= cost
+	return 0, true
+}
+
+// rateLimitKey returns the key of the budget of the client of [h].
+func (h *handler) rateLimitKey() string {
+	return rateLimitKey(h.client, PeerInfoFromContext(h.rootCtx).RemoteAddr)
+}
+
+// rateLimitKey returns the key of the budget of [client], or of the host of
+// [remote] for requests without credentials. Anonymous clients all have the
+// same name, so they are keyed by their host rather than sharing a budget.
+func rateLimitKey(client *authClient, remote string) string {
+	if client != nil && client.name != anonymousClientName {
+		return "client:" + client.name
+	}
+	return remoteHost(remote)
+}
+
+// remoteHost returns the host of the remote address [remote].
+func remoteHost(remote string) string {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
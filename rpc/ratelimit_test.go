@@ -0,0 +1,215 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRateLimitConfig = RateLimitConfig{
+	Costs: []MethodCost{
+		{Method: "eth_getLogs", Cost: 10, CostPerBlock: 1},
+		{Method: "debug_trace*", Cost: 100},
+	},
+	DefaultCost:  1,
+	RefillRate:   1,
+	MaxStored:    200,
+	MaxClients:   2,
+	CurrentBlock: func() uint64 { return 100 },
+}
+
+func TestRateLimitConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		modify      func(*RateLimitConfig)
+		expectedErr string
+	}{
+		"valid": {
+			modify: func(*RateLimitConfig) {},
+		},
+		"zero refill rate": {
+			modify:      func(c *RateLimitConfig) { c.RefillRate = 0 },
+			expectedErr: "refill rate must be positive",
+		},
+		"zero max stored": {
+			modify:      func(c *RateLimitConfig) { c.MaxStored = 0 },
+			expectedErr: "max stored must be positive",
+		},
+		"zero max clients": {
+			modify:      func(c *RateLimitConfig) { c.MaxClients = 0 },
+			expectedErr: "max clients must be positive",
+		},
+		"invalid method pattern": {
+			modify:      func(c *RateLimitConfig) { c.Costs = []MethodCost{{Method: "debug_*_block"}} },
+			expectedErr: `invalid method pattern "debug_*_block"`,
+		},
+		"negative cost": {
+			modify:      func(c *RateLimitConfig) { c.Costs = []MethodCost{{Method: "eth_call", Cost: -1}} },
+			expectedErr: `cost of "eth_call" must be non-negative`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := testRateLimitConfig
+			test.modify(&config)
+			err := config.Validate()
+			if test.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestRateLimiterCost(t *testing.T) {
+	limiter, err := NewRateLimiter(testRateLimitConfig)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		method       string
+		params       string
+		expectedCost float64
+	}{
+		"default cost": {
+			method:       "eth_blockNumber",
+			params:       `[]`,
+			expectedCost: 1,
+		},
+		"method pattern": {
+			method:       "debug_traceTransaction",
+			params:       `["0x01"]`,
+			expectedCost: 100,
+		},
+		"block range": {
+			method:       "eth_getLogs",
+			params:       `[{"fromBlock": "0x10", "toBlock": "0x19"}]`,
+			expectedCost: 20,
+		},
+		"block range to latest": {
+			method:       "eth_getLogs",
+			params:       `[{"fromBlock": "0x5a", "toBlock": "latest"}]`,
+			expectedCost: 21,
+		},
+		"block range without bounds": {
+			method:       "eth_getLogs",
+			params:       `[{}]`,
+			expectedCost: 11,
+		},
+		"block hash": {
+			method:       "eth_getLogs",
+			params:       `[{"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001"}]`,
+			expectedCost: 11,
+		},
+		"reversed block range": {
+			method:       "eth_getLogs",
+			params:       `[{"fromBlock": "0x19", "toBlock": "0x10"}]`,
+			expectedCost: 11,
+		},
+		"invalid params": {
+			method:       "eth_getLogs",
+			params:       `"invalid"`,
+			expectedCost: 11,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expectedCost, limiter.cost(test.method, json.RawMessage(test.params)))
+		})
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := &tokenBucket{tokens: 10, last: now}
+
+	retryAfter, ok := bucket.take(now, 8, 2, 10)
+	require.True(t, ok)
+	require.Zero(t, retryAfter)
+
+	// The bucket holds 2 tokens, so 4 more tokens take 2 seconds to refill.
+	retryAfter, ok = bucket.take(now, 6, 2, 10)
+	require.False(t, ok)
+	require.Equal(t, 2*time.Second, retryAfter)
+
+	retryAfter, ok = bucket.take(now.Add(2*time.Second), 6, 2, 10)
+	require.True(t, ok)
+	require.Zero(t, retryAfter)
+
+	// The bucket refills up to its maximum.
+	_, ok = bucket.take(now.Add(time.Hour), 10, 2, 10)
+	require.True(t, ok)
+	_, ok = bucket.take(now.Add(time.Hour), 1, 2, 10)
+	require.False(t, ok)
+}
+
+func TestRateLimitKey(t *testing.T) {
+	require := require.New(t)
+
+	auth, err := NewAuthenticator(AuthConfig{Tiers: testAuthTiers, Keys: testAuthKeys, AnonymousTier: "free"})
+	require.NoError(err)
+	anonymous, err := auth.authenticate(httptest.NewRequest("POST", "/", nil))
+	require.NoError(err)
+	keyed := httptest.NewRequest("POST", "/", nil)
+	keyed.Header.Set(apiKeyHeader, "free-key")
+	alice, err := auth.authenticate(keyed)
+	require.NoError(err)
+
+	// Anonymous clients are keyed by their host, as without authentication.
+	require.Equal("10.0.0.1", rateLimitKey(anonymous, "10.0.0.1:1234"))
+	require.Equal("10.0.0.2", rateLimitKey(anonymous, "10.0.0.2:1234"))
+	require.Equal("10.0.0.1", rateLimitKey(nil, "10.0.0.1:1234"))
+	// Authenticated clients are keyed by their name from any host.
+	require.Equal("client:alice", rateLimitKey(alice, "10.0.0.1:1234"))
+	require.Equal("client:alice", rateLimitKey(alice, "10.0.0.2:1234"))
+
+	// An anonymous client exhausting its budget does not limit other anonymous clients.
+	limiter, err := NewRateLimiter(testRateLimitConfig)
+	require.NoError(err)
+	first, second := rateLimitKey(anonymous, "10.0.0.1:1234"), rateLimitKey(anonymous, "10.0.0.2:1234")
+	require.NoError(limiter.charge(first, "debug_traceTransaction", nil))
+	require.NoError(limiter.charge(first, "debug_traceTransaction", nil))
+	require.Error(limiter.charge(first, "debug_traceTransaction", nil))
+	require.NoError(limiter.charge(second, "debug_traceTransaction", nil))
+}
+
+func TestHTTPRateLimit(t *testing.T) {
+	s := newTestServer()
+	defer s.Stop()
+	limiter, err := NewRateLimiter(RateLimitConfig{
+		Costs:       []MethodCost{{Method: "test_echo", Cost: 3}},
+		DefaultCost: 1,
+		RefillRate:  1e-6,
+		MaxStored:   4,
+		MaxClients:  1,
+	})
+	require.NoError(t, err)
+	s.SetRateLimiter(limiter)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c, err := Dial(ts.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.Call(nil, "test_echo", "x", 1))
+	require.NoError(t, c.Call(nil, "test_null"))
+
+	// The budget is exhausted, so the error hints when to retry.
+	err = c.Call(nil, "test_null")
+	var dataErr DataError
+	require.True(t, errors.As(err, &dataErr))
+	require.Equal(t, errcodeLimitExceeded, dataErr.(Error).ErrorCode())
+	data, ok := dataErr.ErrorData().(map[string]interface{})
+	require.True(t, ok)
+	require.Greater(t, data["retryAfter"], float64(0))
+
+	// Unknown methods are not charged.
+	require.ErrorContains(t, c.Call(nil, "test_unknown"), "does not exist")
+}
@@ -62,6 +62,8 @@ type Server struct {
 	run                atomic.Bool
 	batchItemLimit     int
 	batchResponseLimit int
+	auth               *Authenticator
+	rateLimiter        *RateLimiter
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -94,6 +96,24 @@ func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
 	s.batchResponseLimit = maxResponseSize
 }
 
+// SetAuthenticator requires the clients of HTTP and websocket requests to
+// authenticate with [auth], which enforces the access tier of each client.
+//
+// This method should be called before processing any requests via ServeHTTP or
+// WebsocketHandler.
+func (s *Server) SetAuthenticator(auth *Authenticator) {
+	s.auth = auth
+}
+
+// SetRateLimiter charges the cost of the calls of HTTP and websocket requests
+// to the budgets of their clients with [limiter].
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP or WebsocketHandler.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -119,6 +139,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption, apiMaxDurati
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		rateLimiter:        s.rateLimiter,
 	}
 	c := initClient(codec, &s.services, cfg, apiMaxDuration, refillRate, maxStored)
 	<-codec.closed()
@@ -154,6 +175,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.deadlineContext = s.maximumDuration
+	h.addClientLimiter()
+	h.rateLimiter = s.rateLimiter
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
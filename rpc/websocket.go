@@ -69,12 +69,23 @@ func (s *Server) WebsocketHandlerWithDuration(allowedOrigins []string, apiMaxDur
 		CheckOrigin:     wsHandshakeValidator(allowedOrigins),
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var client *authClient
+		if s.auth != nil {
+			var err error
+			if client, err = s.auth.authenticate(r); err != nil {
+				writeUnauthorized(w, err)
+				return
+			}
+		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header)
+		if client != nil {
+			codec = &authenticatedCodec{ServerCodec: codec, client: client}
+		}
 		s.ServeCodec(codec, 0, apiMaxDuration, refillRate, maxStored)
 	})
 }
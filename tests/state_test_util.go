@@ -56,6 +56,11 @@ import (
 // See https://github.com/ethereum/EIPs/issues/176 for the test format specification.
 type StateTest struct {
 	json stJSON
+
+	// ChainConfig, if set, is used to run the subtests instead of the chain
+	// config of their fork, so subnet-evm configs enabling precompiles or
+	// setting the fee config can be tested.
+	ChainConfig *params.ChainConfig
 }
 
 // StateSubtest selects a specific configuration of a General State Test.
@@ -230,11 +235,15 @@ func (t *StateTest) Run(subtest StateSubtest, vmconfig vm.Config, snapshotter bo
 
 // RunNoVerify runs a specific subtest and returns the statedb and post-state root
 func (t *StateTest) RunNoVerify(subtest StateSubtest, vmconfig vm.Config, snapshotter bool) (*snapshot.Tree, *state.StateDB, common.Hash, error) {
-	config, eips, err := GetChainConfig(subtest.Fork)
-	if err != nil {
-		return nil, nil, common.Hash{}, UnsupportedForkError{subtest.Fork}
+	config := t.ChainConfig
+	if config == nil {
+		forkConfig, eips, err := GetChainConfig(subtest.Fork)
+		if err != nil {
+			return nil, nil, common.Hash{}, UnsupportedForkError{subtest.Fork}
+		}
+		config = forkConfig
+		vmconfig.ExtraEips = eips
 	}
-	vmconfig.ExtraEips = eips
 	block := t.genesis(config).ToBlock()
 	snaps, statedb := MakePreState(rawdb.NewMemoryDatabase(), t.json.Pre, snapshotter)
 
@@ -274,6 +283,13 @@ func (t *StateTest) RunNoVerify(subtest StateSubtest, vmconfig vm.Config, snapsh
 	if config.IsSubnetEVM(0) && t.json.Env.Random != nil {
 		context.Difficulty = big.NewInt(0)
 	}
+	if t.ChainConfig != nil {
+		// Configure the precompiles enabled at the block, as committing a
+		// genesis with the chain config would.
+		if err := core.ApplyPrecompileActivations(config, nil, &context, statedb); err != nil {
+			return nil, nil, common.Hash{}, err
+		}
+	}
 	evm := vm.NewEVM(context, txContext, statedb, config, vmconfig)
 	// Execute the message.
 	snapshot := statedb.Snapshot()
@@ -6,6 +6,8 @@ package aggregator
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 
@@ -15,6 +17,15 @@ import (
 	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
 )
 
+const (
+	// initialSlowValidatorDelay is how long a validator is given to respond
+	// before signatures are also requested from further validators. The delay
+	// backs off every time validators are found to be slow.
+	initialSlowValidatorDelay       = time.Second
+	maxSlowValidatorDelay           = 10 * time.Second
+	slowValidatorDelayBackoffFactor = 2
+)
+
 type AggregateSignatureResult struct {
 	// Weight of validators included in the aggregate signature.
 	SignatureWeight uint64
@@ -24,130 +35,260 @@ type AggregateSignatureResult struct {
 	Message *avalancheWarp.Message
 }
 
+// signatureFetchResult is the outcome of requesting the signature of the
+// validator at [index]. [sig] is nil if the request failed.
 type signatureFetchResult struct {
-	sig    *bls.Signature
-	index  int
-	weight uint64
+	sig   *bls.Signature
+	index int
 }
 
 // Aggregator requests signatures from validators and
 // aggregates them into a single signature.
+//
+// Signatures are requested from the highest weight validators first, and only
+// from as many validators as needed to reach the quorum. Validators that fail
+// to respond in time are complemented by requests to further validators.
 type Aggregator struct {
 	validators  []*avalancheWarp.Validator
 	totalWeight uint64
 	client      SignatureGetter
+	cache       *SignatureCache
 }
 
 // New returns a signature aggregator that will attempt to aggregate signatures from [validators].
 func New(client SignatureGetter, validators []*avalancheWarp.Validator, totalWeight uint64) *Aggregator {
+	return NewWithCache(client, validators, totalWeight, nil)
+}
+
+// NewWithCache returns a signature aggregator that will attempt to aggregate signatures
+// from [validators], reusing and adding to the signatures in [cache]. [cache] may be nil.
+func NewWithCache(client SignatureGetter, validators []*avalancheWarp.Validator, totalWeight uint64, cache *SignatureCache) *Aggregator {
 	return &Aggregator{
 		client:      client,
 		validators:  validators,
 		totalWeight: totalWeight,
+		cache:       cache,
 	}
 }
 
 // Returns an aggregate signature over [unsignedMessage].
 // The returned signature's weight exceeds the threshold given by [quorumNum].
+//
+// If the threshold cannot be reached, the returned error wraps
+// [avalancheWarp.ErrInsufficientWeight] and, if any signature was collected,
+// the partial aggregate signature is returned alongside it.
 func (a *Aggregator) AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*AggregateSignatureResult, error) {
+	var (
+		messageID        = unsignedMessage.ID()
+		publicKeys       = make([][]byte, len(a.validators))
+		signatures       = make([]*bls.Signature, 0, len(a.validators))
+		signersBitset    = set.NewBits()
+		signaturesWeight = uint64(0)
+		// Validators without a cached signature, by decreasing weight.
+		pending = make([]int, 0, len(a.validators))
+	)
+	addSignature := func(index int, sig *bls.Signature) {
+		signatures = append(signatures, sig)
+		signersBitset.Add(index)
+		signaturesWeight += a.validators[index].Weight
+	}
+	passedThreshold := func(weight uint64) bool {
+		return avalancheWarp.VerifyWeight(weight, a.totalWeight, quorumNum, warp.WarpQuorumDenominator) == nil
+	}
+
+	for i, validator := range a.validators {
+		publicKeys[i] = validator.PublicKeyBytes
+		if len(publicKeys[i]) == 0 {
+			publicKeys[i] = bls.PublicKeyToCompressedBytes(validator.PublicKey)
+		}
+		if a.cache != nil {
+			if sig, ok := a.cache.Get(messageID, publicKeys[i]); ok {
+				addSignature(i, sig)
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return a.validators[pending[i]].Weight > a.validators[pending[j]].Weight
+	})
+	if len(signatures) > 0 {
+		log.Debug("Using cached warp signatures",
+			"numSigners", len(signatures),
+			"signatureWeight", signaturesWeight,
+			"msgID", messageID,
+		)
+	}
+
 	// Create a child context to cancel signature fetching if we reach signature threshold.
 	signatureFetchCtx, signatureFetchCancel := context.WithCancel(ctx)
 	defer signatureFetchCancel()
 
-	// Fetch signatures from validators concurrently.
-	signatureFetchResultChan := make(chan *signatureFetchResult)
-	for i, validator := range a.validators {
-		var (
-			i         = i
-			validator = validator
-			// TODO: update from a single nodeID to the original slice and use extra nodeIDs as backup.
-			nodeID = validator.NodeIDs[0]
-		)
-		go func() {
-			log.Debug("Fetching warp signature",
-				"nodeID", nodeID,
-				"index", i,
-				"msgID", unsignedMessage.ID(),
-			)
-
-			signature, err := a.client.GetSignature(signatureFetchCtx, nodeID, unsignedMessage)
-			if err != nil {
-				log.Debug("Failed to fetch warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"err", err,
-					"msgID", unsignedMessage.ID(),
-				)
-				signatureFetchResultChan <- nil
-				return
-			}
+	var (
+		// Buffered so requests outstanding when aggregation returns never block.
+		signatureFetchResultChan = make(chan *signatureFetchResult, len(pending))
+		next                     = 0
+		outstanding              = 0
+		// Weight of the outstanding requests that are not slow.
+		outstandingWeight = uint64(0)
+		// Time at which each outstanding request that is not slow becomes slow.
+		slowDeadlines = make(map[int]time.Time)
+		slowDelay     = initialSlowValidatorDelay
+		slowTimer     = time.NewTimer(slowDelay)
+	)
+	defer slowTimer.Stop()
 
-			log.Debug("Retrieved warp signature",
-				"nodeID", nodeID,
-				"msgID", unsignedMessage.ID(),
-				"index", i,
-			)
-
-			if !bls.Verify(validator.PublicKey, signature, unsignedMessage.Bytes()) {
-				log.Debug("Failed to verify warp signature",
-					"nodeID", nodeID,
-					"index", i,
-					"msgID", unsignedMessage.ID(),
-				)
-				signatureFetchResultChan <- nil
-				return
+	fetchMore := func() {
+		for next < len(pending) && signatureFetchCtx.Err() == nil && !passedThreshold(signaturesWeight+outstandingWeight) {
+			index := pending[next]
+			next++
+			outstanding++
+			outstandingWeight += a.validators[index].Weight
+			slowDeadlines[index] = time.Now().Add(slowDelay)
+			go a.fetchSignature(signatureFetchCtx, unsignedMessage, index, signatureFetchResultChan)
+		}
+	}
+	resetSlowTimer := func() {
+		if !slowTimer.Stop() {
+			select {
+			case <-slowTimer.C:
+			default:
 			}
-
-			signatureFetchResultChan <- &signatureFetchResult{
-				sig:    signature,
-				index:  i,
-				weight: validator.Weight,
+		}
+		var earliest time.Time
+		for _, deadline := range slowDeadlines {
+			if earliest.IsZero() || deadline.Before(earliest) {
+				earliest = deadline
 			}
-		}()
+		}
+		if !earliest.IsZero() {
+			slowTimer.Reset(time.Until(earliest))
+		}
 	}
 
-	var (
-		signatures                = make([]*bls.Signature, 0, len(a.validators))
-		signersBitset             = set.NewBits()
-		signaturesWeight          = uint64(0)
-		signaturesPassedThreshold = false
-	)
+	for fetchMore(); outstanding > 0 && !passedThreshold(signaturesWeight); fetchMore() {
+		resetSlowTimer()
+
+		var signatureFetchResult *signatureFetchResult
+		select {
+		case signatureFetchResult = <-signatureFetchResultChan:
+		case now := <-slowTimer.C:
+			// Keep waiting for the slow validators, but request signatures
+			// from further validators in case they do not respond. Validators
+			// requested from now on are given longer to respond.
+			for index, deadline := range slowDeadlines {
+				if now.Before(deadline) {
+					continue
+				}
+				log.Debug("Warp signature request is slow",
+					"nodeID", a.validators[index].NodeIDs[0],
+					"index", index,
+					"msgID", messageID,
+				)
+				delete(slowDeadlines, index)
+				outstandingWeight -= a.validators[index].Weight
+			}
+			slowDelay = min(slowDelay*slowValidatorDelayBackoffFactor, maxSlowValidatorDelay)
+			continue
+		}
 
-	for i := 0; i < len(a.validators); i++ {
-		signatureFetchResult := <-signatureFetchResultChan
-		if signatureFetchResult == nil {
+		outstanding--
+		weight := a.validators[signatureFetchResult.index].Weight
+		if _, ok := slowDeadlines[signatureFetchResult.index]; ok {
+			delete(slowDeadlines, signatureFetchResult.index)
+			outstandingWeight -= weight
+		}
+		if signatureFetchResult.sig == nil {
 			continue
 		}
 
-		signatures = append(signatures, signatureFetchResult.sig)
-		signersBitset.Add(signatureFetchResult.index)
-		signaturesWeight += signatureFetchResult.weight
+		addSignature(signatureFetchResult.index, signatureFetchResult.sig)
+		if a.cache != nil {
+			a.cache.Add(messageID, publicKeys[signatureFetchResult.index], signatureFetchResult.sig)
+		}
 		log.Debug("Updated weight",
 			"totalWeight", signaturesWeight,
-			"addedWeight", signatureFetchResult.weight,
+			"addedWeight", weight,
+			"msgID", messageID,
+		)
+	}
+
+	// If the signature weight meets the requested threshold, cancel signature fetching
+	if passedThreshold(signaturesWeight) {
+		log.Debug("Verify weight passed, exiting aggregation early",
+			"quorumNum", quorumNum,
+			"totalWeight", a.totalWeight,
+			"signatureWeight", signaturesWeight,
+			"msgID", messageID,
+		)
+		signatureFetchCancel()
+		return a.newResult(unsignedMessage, signatures, signersBitset, signaturesWeight)
+	}
+
+	// If I failed to fetch sufficient signature stake, return an error along
+	// with the signatures collected so far.
+	err := fmt.Errorf("%w: collected %d of %d weight", avalancheWarp.ErrInsufficientWeight, signaturesWeight, a.totalWeight)
+	if len(signatures) == 0 {
+		return nil, err
+	}
+	res, resErr := a.newResult(unsignedMessage, signatures, signersBitset, signaturesWeight)
+	if resErr != nil {
+		return nil, resErr
+	}
+	return res, err
+}
+
+// fetchSignature requests the signature of the validator at [index] over
+// [unsignedMessage] and sends the verified signature, or nil on failure, to
+// [results].
+func (a *Aggregator) fetchSignature(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, index int, results chan<- *signatureFetchResult) {
+	var (
+		validator = a.validators[index]
+		// TODO: update from a single nodeID to the original slice and use extra nodeIDs as backup.
+		nodeID = validator.NodeIDs[0]
+	)
+	log.Debug("Fetching warp signature",
+		"nodeID", nodeID,
+		"index", index,
+		"msgID", unsignedMessage.ID(),
+	)
+
+	signature, err := a.client.GetSignature(ctx, nodeID, unsignedMessage)
+	if err != nil {
+		log.Debug("Failed to fetch warp signature",
+			"nodeID", nodeID,
+			"index", index,
+			"err", err,
 			"msgID", unsignedMessage.ID(),
 		)
+		results <- &signatureFetchResult{index: index}
+		return
+	}
 
-		// If the signature weight meets the requested threshold, cancel signature fetching
-		if err := avalancheWarp.VerifyWeight(signaturesWeight, a.totalWeight, quorumNum, warp.WarpQuorumDenominator); err == nil {
-			log.Debug("Verify weight passed, exiting aggregation early",
-				"quorumNum", quorumNum,
-				"totalWeight", a.totalWeight,
-				"signatureWeight", signaturesWeight,
-				"msgID", unsignedMessage.ID(),
-			)
-			signatureFetchCancel()
-			signaturesPassedThreshold = true
-			break
-		}
+	log.Debug("Retrieved warp signature",
+		"nodeID", nodeID,
+		"msgID", unsignedMessage.ID(),
+		"index", index,
+	)
+
+	if !bls.Verify(validator.PublicKey, signature, unsignedMessage.Bytes()) {
+		log.Debug("Failed to verify warp signature",
+			"nodeID", nodeID,
+			"index", index,
+			"msgID", unsignedMessage.ID(),
+		)
+		results <- &signatureFetchResult{index: index}
+		return
 	}
 
-	// If I failed to fetch sufficient signature stake, return an error
-	if !signaturesPassedThreshold {
-		return nil, avalancheWarp.ErrInsufficientWeight
+	results <- &signatureFetchResult{
+		sig:   signature,
+		index: index,
 	}
+}
 
-	// Otherwise, return the aggregate signature
+// newResult returns [unsignedMessage] signed by the aggregate of [signatures].
+func (a *Aggregator) newResult(unsignedMessage *avalancheWarp.UnsignedMessage, signatures []*bls.Signature, signersBitset set.Bits, signaturesWeight uint64) (*AggregateSignatureResult, error) {
 	aggregateSignature, err := bls.AggregateSignatures(signatures)
 	if err != nil {
 		return nil, fmt.Errorf("failed to aggregate BLS signatures: %w", err)
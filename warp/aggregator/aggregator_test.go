@@ -238,7 +238,8 @@ func TestAggregateSignatures(t *testing.T) {
 			},
 			aggregatorFunc: func(ctrl *gomock.Controller, _ context.CancelFunc) *Aggregator {
 				// Assert that the context passed into each goroutine is canceled
-				// because the parent context is canceled.
+				// because the parent context is canceled. No further validators
+				// are requested once the context is canceled.
 				client := NewMockSignatureGetter(ctrl)
 				client.EXPECT().GetSignature(gomock.Any(), nodeID1, gomock.Any()).DoAndReturn(
 					func(ctx context.Context, _ ids.NodeID, _ *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
@@ -247,7 +248,7 @@ func TestAggregateSignatures(t *testing.T) {
 						require.ErrorIs(t, err, context.Canceled)
 						return nil, err
 					},
-				).Times(1)
+				).MaxTimes(1)
 				client.EXPECT().GetSignature(gomock.Any(), nodeID2, gomock.Any()).DoAndReturn(
 					func(ctx context.Context, _ ids.NodeID, _ *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
 						<-ctx.Done()
@@ -382,3 +383,85 @@ func TestAggregateSignatures(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateSignaturesIncremental(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.ID{'y', 'e', 'e', 't'}, []byte("hello world"))
+	require.NoError(err)
+
+	var (
+		sks     []*bls.SecretKey
+		vdrs    []*avalancheWarp.Validator
+		weights = []uint64{10, 40, 30, 20}
+	)
+	for _, weight := range weights {
+		sk, vdr := newValidator(t, weight)
+		sks = append(sks, sk)
+		vdrs = append(vdrs, vdr)
+	}
+	sign := func(i int) *bls.Signature { return bls.Sign(sks[i], unsignedMsg.Bytes()) }
+
+	client := NewMockSignatureGetter(ctrl)
+	cache := NewSignatureCache(10)
+
+	// Only the highest weight validators needed for the quorum are requested.
+	// The validator with weight 30 fails, so the one with weight 20 is
+	// requested in its place. The quorum is not reached, and the partial
+	// signature is returned.
+	client.EXPECT().GetSignature(gomock.Any(), vdrs[1].NodeIDs[0], gomock.Any()).Return(sign(1), nil)
+	client.EXPECT().GetSignature(gomock.Any(), vdrs[2].NodeIDs[0], gomock.Any()).Return(nil, errors.New("test error"))
+	client.EXPECT().GetSignature(gomock.Any(), vdrs[3].NodeIDs[0], gomock.Any()).Return(sign(3), nil)
+	client.EXPECT().GetSignature(gomock.Any(), vdrs[0].NodeIDs[0], gomock.Any()).Return(nil, errors.New("test error"))
+	res, err := NewWithCache(client, vdrs, 100, cache).AggregateSignatures(context.Background(), unsignedMsg, 70)
+	require.ErrorIs(err, avalancheWarp.ErrInsufficientWeight)
+	require.Equal(uint64(60), res.SignatureWeight)
+	expectedSig, err := bls.AggregateSignatures([]*bls.Signature{sign(1), sign(3)})
+	require.NoError(err)
+	gotSig, ok := res.Message.Signature.(*avalancheWarp.BitSetSignature)
+	require.True(ok)
+	require.Equal(bls.SignatureToBytes(expectedSig), gotSig.Signature[:])
+
+	// The cached signatures are reused, so only the missing weight is requested.
+	client.EXPECT().GetSignature(gomock.Any(), vdrs[2].NodeIDs[0], gomock.Any()).Return(sign(2), nil)
+	res, err = NewWithCache(client, vdrs, 100, cache).AggregateSignatures(context.Background(), unsignedMsg, 70)
+	require.NoError(err)
+	require.Equal(uint64(90), res.SignatureWeight)
+
+	// Once all the needed signatures are cached, no requests are made.
+	res, err = NewWithCache(client, vdrs, 100, cache).AggregateSignatures(context.Background(), unsignedMsg, 90)
+	require.NoError(err)
+	require.Equal(uint64(90), res.SignatureWeight)
+}
+
+func TestAggregateSignaturesSlowValidator(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	unsignedMsg, err := avalancheWarp.NewUnsignedMessage(1338, ids.ID{'y', 'e', 'e', 't'}, []byte("hello world"))
+	require.NoError(err)
+	sk1, vdr1 := newValidator(t, 60)
+	sk2, vdr2 := newValidator(t, 40)
+	vdrs := []*avalancheWarp.Validator{vdr1, vdr2}
+
+	// The heaviest validator is enough for the quorum but does not respond
+	// in time, so the second one is requested as well.
+	client := NewMockSignatureGetter(ctrl)
+	slowResponse := make(chan struct{})
+	client.EXPECT().GetSignature(gomock.Any(), vdr1.NodeIDs[0], gomock.Any()).DoAndReturn(
+		func(context.Context, ids.NodeID, *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+			<-slowResponse
+			return bls.Sign(sk1, unsignedMsg.Bytes()), nil
+		},
+	)
+	client.EXPECT().GetSignature(gomock.Any(), vdr2.NodeIDs[0], gomock.Any()).DoAndReturn(
+		func(context.Context, ids.NodeID, *avalancheWarp.UnsignedMessage) (*bls.Signature, error) {
+			close(slowResponse)
+			return bls.Sign(sk2, unsignedMsg.Bytes()), nil
+		},
+	)
+	res, err := New(client, vdrs, 100).AggregateSignatures(context.Background(), unsignedMsg, 100)
+	require.NoError(err)
+	require.Equal(uint64(100), res.SignatureWeight)
+}
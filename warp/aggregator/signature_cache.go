@@ -0,0 +1,60 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package aggregator
+
+import (
+	"sync"
+
+	"github.com/MetalBlockchain/metalgo/cache"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+)
+
+// SignatureCache caches the verified signatures of individual validators over
+// warp messages, so that repeated aggregations of the same message only
+// request signatures from the validators that did not sign it yet.
+//
+// Signatures are keyed by the public key of the signer, so cached signatures
+// remain valid across validator set changes.
+type SignatureCache struct {
+	lock sync.Mutex
+	// messageID -> compressed public key -> signature
+	signatures *cache.LRU[ids.ID, map[string]*bls.Signature]
+}
+
+// NewSignatureCache returns a SignatureCache holding the signatures of up to
+// [size] messages.
+func NewSignatureCache(size int) *SignatureCache {
+	return &SignatureCache{
+		signatures: &cache.LRU[ids.ID, map[string]*bls.Signature]{Size: size},
+	}
+}
+
+// Get returns the cached signature of the validator with [publicKeyBytes]
+// over [messageID].
+func (c *SignatureCache) Get(messageID ids.ID, publicKeyBytes []byte) (*bls.Signature, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	signatures, ok := c.signatures.Get(messageID)
+	if !ok {
+		return nil, false
+	}
+	signature, ok := signatures[string(publicKeyBytes)]
+	return signature, ok
+}
+
+// Add caches [signature] of the validator with [publicKeyBytes] over
+// [messageID]. The signature must have been verified by the caller.
+func (c *SignatureCache) Add(messageID ids.ID, publicKeyBytes []byte, signature *bls.Signature) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	signatures, ok := c.signatures.Get(messageID)
+	if !ok {
+		signatures = make(map[string]*bls.Signature)
+		c.signatures.Put(messageID, signatures)
+	}
+	signatures[string(publicKeyBytes)] = signature
+}
@@ -7,9 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/MetalBlockchain/metalgo/cache"
 	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/prefixdb"
 	"github.com/MetalBlockchain/metalgo/ids"
 	"github.com/MetalBlockchain/metalgo/snow/choices"
 	"github.com/MetalBlockchain/metalgo/snow/consensus/snowman"
@@ -17,6 +21,7 @@ import (
 	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -31,11 +36,20 @@ type BlockClient interface {
 	GetBlock(ctx context.Context, blockID ids.ID) (snowman.Block, error)
 }
 
+// AcceptedMessageReader reads the warp messages sent in accepted blocks. It is
+// used to regenerate tracked messages that are missing from the warp database.
+type AcceptedMessageReader interface {
+	// GetAcceptedWarpMessages returns the unsigned warp messages sent in the
+	// accepted block at [height].
+	GetAcceptedWarpMessages(height uint64) ([]*avalancheWarp.UnsignedMessage, error)
+}
+
 // Backend tracks signature-eligible warp messages and provides an interface to fetch them.
 // The backend is also used to query for warp message signatures by the signature request handler.
 type Backend interface {
-	// AddMessage signs [unsignedMessage] and adds it to the warp backend database
-	AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) error
+	// AddMessage signs [unsignedMessage] accepted in the block at [blockNumber] with
+	// timestamp [blockTimestamp] and adds it to the warp backend database
+	AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage, blockNumber uint64, blockTimestamp uint64) error
 
 	// GetMessageSignature returns the signature of the requested message hash.
 	GetMessageSignature(messageID ids.ID) ([bls.SignatureLen]byte, error)
@@ -46,6 +60,15 @@ type Backend interface {
 	// GetMessage retrieves the [unsignedMessage] from the warp backend database if available
 	GetMessage(messageHash ids.ID) (*avalancheWarp.UnsignedMessage, error)
 
+	// SubscribeMessages registers a subscription for the messages added
+	// through AddMessage.
+	SubscribeMessages(ch chan<- *avalancheWarp.UnsignedMessage) event.Subscription
+
+	// PruneMessages deletes the messages that [policy] no longer retains given the
+	// [lastAcceptedHeight] and the current time [now], and returns how many were deleted.
+	// Pruned messages are regenerated from their accepted block when they are requested.
+	PruneMessages(policy RetentionPolicy, lastAcceptedHeight uint64, now time.Time) (int, error)
+
 	// Clear clears the entire db
 	Clear() error
 }
@@ -55,34 +78,61 @@ type backend struct {
 	networkID                 uint32
 	sourceChainID             ids.ID
 	db                        database.Database
+	retentionDB               database.Database
+	locatorDB                 database.Database
 	warpSigner                avalancheWarp.Signer
 	blockClient               BlockClient
+	messageReader             AcceptedMessageReader
 	messageSignatureCache     *cache.LRU[ids.ID, [bls.SignatureLen]byte]
 	blockSignatureCache       *cache.LRU[ids.ID, [bls.SignatureLen]byte]
 	messageCache              *cache.LRU[ids.ID, *avalancheWarp.UnsignedMessage]
 	offchainAddressedCallMsgs map[ids.ID]*avalancheWarp.UnsignedMessage
+	offchainRegistry          *OffChainRegistry
+	messageFeed               event.Feed
+	stats                     *backendStats
+
+	// messageCount and messageBytes are the number and the total size of the
+	// messages stored in [db].
+	messageCount atomic.Int64
+	messageBytes atomic.Int64
+	// pruneLock serializes pruning passes. deliveryCursor is the retention key
+	// from which the next pass checks deliveries.
+	pruneLock      sync.Mutex
+	deliveryCursor []byte
 }
 
 // NewBackend creates a new Backend, and initializes the signature cache and message tracking database.
+// [messageReader] may be nil, in which case missing messages cannot be regenerated.
+// [offchainRegistry] may be nil, in which case only [offchainMessages] are signed off-chain.
 func NewBackend(
 	networkID uint32,
 	sourceChainID ids.ID,
 	warpSigner avalancheWarp.Signer,
 	blockClient BlockClient,
+	messageReader AcceptedMessageReader,
 	db database.Database,
 	cacheSize int,
 	offchainMessages [][]byte,
+	offchainRegistry *OffChainRegistry,
 ) (Backend, error) {
 	b := &backend{
 		networkID:                 networkID,
 		sourceChainID:             sourceChainID,
 		db:                        db,
+		retentionDB:               prefixdb.New(retentionPrefix, db),
+		locatorDB:                 prefixdb.New(locatorPrefix, db),
 		warpSigner:                warpSigner,
 		blockClient:               blockClient,
+		messageReader:             messageReader,
 		messageSignatureCache:     &cache.LRU[ids.ID, [bls.SignatureLen]byte]{Size: cacheSize},
 		blockSignatureCache:       &cache.LRU[ids.ID, [bls.SignatureLen]byte]{Size: cacheSize},
 		messageCache:              &cache.LRU[ids.ID, *avalancheWarp.UnsignedMessage]{Size: cacheSize},
 		offchainAddressedCallMsgs: make(map[ids.ID]*avalancheWarp.UnsignedMessage),
+		offchainRegistry:          offchainRegistry,
+		stats:                     newBackendStats(),
+	}
+	if err := b.initSize(); err != nil {
+		return nil, fmt.Errorf("failed to count warp messages: %w", err)
 	}
 	return b, b.initOffChainMessages(offchainMessages)
 }
@@ -116,18 +166,31 @@ func (b *backend) Clear() error {
 	b.messageSignatureCache.Flush()
 	b.blockSignatureCache.Flush()
 	b.messageCache.Flush()
-	return database.Clear(b.db, batchSize)
+	if err := database.Clear(b.db, batchSize); err != nil {
+		return err
+	}
+	return b.initSize()
 }
 
-func (b *backend) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) error {
+func (b *backend) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage, blockNumber uint64, blockTimestamp uint64) error {
 	messageID := unsignedMessage.ID()
 
 	// In the case when a node restarts, and possibly changes its bls key, the cache gets emptied but the database does not.
 	// So to avoid having incorrect signatures saved in the database after a bls key change, we save the full message in the database.
 	// Whereas for the cache, after the node restart, the cache would be emptied so we can directly save the signatures.
+	has, err := b.db.Has(messageID[:])
+	if err != nil {
+		return fmt.Errorf("failed to check warp message in db: %w", err)
+	}
 	if err := b.db.Put(messageID[:], unsignedMessage.Bytes()); err != nil {
 		return fmt.Errorf("failed to put warp signature in db: %w", err)
 	}
+	if !has {
+		b.addSize(1, int64(len(unsignedMessage.Bytes())))
+	}
+	if err := b.trackMessage(messageID, blockNumber, time.Unix(int64(blockTimestamp), 0)); err != nil {
+		return fmt.Errorf("failed to track warp message for retention: %w", err)
+	}
 
 	var signature [bls.SignatureLen]byte
 	sig, err := b.warpSigner.Sign(unsignedMessage)
@@ -138,11 +201,27 @@ func (b *backend) AddMessage(unsignedMessage *avalancheWarp.UnsignedMessage) err
 	copy(signature[:], sig)
 	b.messageSignatureCache.Put(messageID, signature)
 	log.Debug("Adding warp message to backend", "messageID", messageID)
+	b.messageFeed.Send(unsignedMessage)
 	return nil
 }
 
+func (b *backend) SubscribeMessages(ch chan<- *avalancheWarp.UnsignedMessage) event.Subscription {
+	return b.messageFeed.Subscribe(ch)
+}
+
 func (b *backend) GetMessageSignature(messageID ids.ID) ([bls.SignatureLen]byte, error) {
 	log.Debug("Getting warp message from backend", "messageID", messageID)
+	// Registered off-chain messages are signed on every request rather than
+	// cached, so they stop being signed once they expire or are unregistered.
+	if message, ok := b.getRegisteredMessage(messageID); ok {
+		sig, err := b.warpSigner.Sign(message)
+		if err != nil {
+			return [bls.SignatureLen]byte{}, fmt.Errorf("failed to sign warp message: %w", err)
+		}
+		var signature [bls.SignatureLen]byte
+		copy(signature[:], sig)
+		return signature, nil
+	}
 	if sig, ok := b.messageSignatureCache.Get(messageID); ok {
 		return sig, nil
 	}
@@ -203,8 +282,14 @@ func (b *backend) GetMessage(messageID ids.ID) (*avalancheWarp.UnsignedMessage,
 	if message, ok := b.offchainAddressedCallMsgs[messageID]; ok {
 		return message, nil
 	}
+	if message, ok := b.getRegisteredMessage(messageID); ok {
+		return message, nil
+	}
 
 	unsignedMessageBytes, err := b.db.Get(messageID[:])
+	if err == database.ErrNotFound {
+		return b.regenerateMessage(messageID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get warp message %s from db: %w", messageID.String(), err)
 	}
@@ -217,3 +302,12 @@ func (b *backend) GetMessage(messageID ids.ID) (*avalancheWarp.UnsignedMessage,
 
 	return unsignedMessage, nil
 }
+
+// getRegisteredMessage returns [messageID] if it is registered in the
+// off-chain registry and has not expired.
+func (b *backend) getRegisteredMessage(messageID ids.ID) (*avalancheWarp.UnsignedMessage, bool) {
+	if b.offchainRegistry == nil {
+		return nil, false
+	}
+	return b.offchainRegistry.Get(messageID)
+}
@@ -47,7 +47,7 @@ func TestClearDB(t *testing.T) {
 	sk, err := bls.NewSecretKey()
 	require.NoError(t, err)
 	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
-	backendIntf, err := NewBackend(networkID, sourceChainID, warpSigner, nil, db, 500, nil)
+	backendIntf, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, db, 500, nil, nil)
 	require.NoError(t, err)
 	backend, ok := backendIntf.(*backend)
 	require.True(t, ok)
@@ -62,7 +62,7 @@ func TestClearDB(t *testing.T) {
 		require.NoError(t, err)
 		messageID := hashing.ComputeHash256Array(unsignedMsg.Bytes())
 		messageIDs = append(messageIDs, messageID)
-		err = backend.AddMessage(unsignedMsg)
+		err = backend.AddMessage(unsignedMsg, 1, 0)
 		require.NoError(t, err)
 		// ensure that the message was added
 		_, err = backend.GetMessageSignature(messageID)
@@ -91,11 +91,11 @@ func TestAddAndGetValidMessage(t *testing.T) {
 	sk, err := bls.NewSecretKey()
 	require.NoError(t, err)
 	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
-	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, db, 500, nil)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, db, 500, nil, nil)
 	require.NoError(t, err)
 
 	// Add testUnsignedMessage to the warp backend
-	err = backend.AddMessage(testUnsignedMessage)
+	err = backend.AddMessage(testUnsignedMessage, 1, 0)
 	require.NoError(t, err)
 
 	// Verify that a signature is returned successfully, and compare to expected signature.
@@ -114,7 +114,7 @@ func TestAddAndGetUnknownMessage(t *testing.T) {
 	sk, err := bls.NewSecretKey()
 	require.NoError(t, err)
 	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
-	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, db, 500, nil)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, db, 500, nil, nil)
 	require.NoError(t, err)
 
 	// Try getting a signature for a message that was not added.
@@ -146,7 +146,7 @@ func TestGetBlockSignature(t *testing.T) {
 	sk, err := bls.NewSecretKey()
 	require.NoError(err)
 	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
-	backend, err := NewBackend(networkID, sourceChainID, warpSigner, testVM, db, 500, nil)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, testVM, nil, db, 500, nil, nil)
 	require.NoError(err)
 
 	blockHashPayload, err := payload.NewHash(blkID)
@@ -172,11 +172,11 @@ func TestZeroSizedCache(t *testing.T) {
 	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
 
 	// Verify zero sized cache works normally, because the lru cache will be initialized to size 1 for any size parameter <= 0.
-	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, db, 0, nil)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, db, 0, nil, nil)
 	require.NoError(t, err)
 
 	// Add testUnsignedMessage to the warp backend
-	err = backend.AddMessage(testUnsignedMessage)
+	err = backend.AddMessage(testUnsignedMessage, 1, 0)
 	require.NoError(t, err)
 
 	// Verify that a signature is returned successfully, and compare to expected signature.
@@ -226,7 +226,7 @@ func TestOffChainMessages(t *testing.T) {
 			require := require.New(t)
 			db := memdb.New()
 
-			backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, db, 0, test.offchainMessages)
+			backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, db, 0, test.offchainMessages, nil)
 			require.ErrorIs(err, test.err)
 			if test.check != nil {
 				test.check(require, backend)
@@ -35,13 +35,13 @@ func TestMessageSignatureHandler(t *testing.T) {
 	offchainMessage, err := avalancheWarp.NewUnsignedMessage(snowCtx.NetworkID, snowCtx.ChainID, addressedPayload.Bytes())
 	require.NoError(t, err)
 
-	backend, err := warp.NewBackend(snowCtx.NetworkID, snowCtx.ChainID, warpSigner, &block.TestVM{TestVM: common.TestVM{T: t}}, database, 100, [][]byte{offchainMessage.Bytes()})
+	backend, err := warp.NewBackend(snowCtx.NetworkID, snowCtx.ChainID, warpSigner, &block.TestVM{TestVM: common.TestVM{T: t}}, nil, database, 100, [][]byte{offchainMessage.Bytes()}, nil)
 	require.NoError(t, err)
 
 	msg, err := avalancheWarp.NewUnsignedMessage(snowCtx.NetworkID, snowCtx.ChainID, []byte("test"))
 	require.NoError(t, err)
 	messageID := msg.ID()
-	require.NoError(t, backend.AddMessage(msg))
+	require.NoError(t, backend.AddMessage(msg, 1, 0))
 	signature, err := backend.GetMessageSignature(messageID)
 	require.NoError(t, err)
 	offchainSignature, err := backend.GetMessageSignature(offchainMessage.ID())
@@ -154,9 +154,11 @@ func TestBlockSignatureHandler(t *testing.T) {
 		snowCtx.ChainID,
 		warpSigner,
 		testVM,
+		nil,
 		database,
 		100,
 		nil,
+		nil,
 	)
 	require.NoError(t, err)
 
@@ -0,0 +1,340 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	warpPrecompile "github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+)
+
+const (
+	// MessageStatusPending is the status of a sent message that has not been
+	// observed being delivered on this chain. Deliveries to other chains are
+	// not observed, so messages sent to them remain pending.
+	MessageStatusPending = "pending"
+	// MessageStatusDelivered is the status of a sent message that was
+	// verified by an accepted transaction on this chain.
+	MessageStatusDelivered = "delivered"
+
+	defaultMessagesLimit = 100
+	maxMessagesLimit     = 1000
+	// maxIndexScan bounds the number of index entries read by a single query,
+	// so that selective filters over a large range cannot stall the node. A
+	// query that reaches it returns a cursor to resume from.
+	maxIndexScan = 10_000
+
+	positionLen = 8 + 4 // block number || log index
+)
+
+var (
+	blockPrefix    = []byte{'b'} // blockPrefix + position -> sent message
+	addressPrefix  = []byte{'a'} // addressPrefix + source address + position -> nil
+	deliveryPrefix = []byte{'d'} // deliveryPrefix + message ID -> delivery
+
+	sendWarpMessageEventID = warpPrecompile.WarpABI.Events["SendWarpMessage"].ID
+
+	errInvalidMessagesLimit = fmt.Errorf("limit must be at most %d", maxMessagesLimit)
+	errInvalidMessageStatus = errors.New("status must be one of \"\", \"pending\" or \"delivered\"")
+	errInvalidCursor        = fmt.Errorf("cursor must be %d bytes", positionLen)
+)
+
+// IndexedMessage is a warp message sent from this chain.
+type IndexedMessage struct {
+	MessageID     ids.ID         `json:"messageID"`
+	SourceAddress common.Address `json:"sourceAddress"`
+	BlockNumber   hexutil.Uint64 `json:"blockNumber"`
+	BlockHash     common.Hash    `json:"blockHash"`
+	TxHash        common.Hash    `json:"transactionHash"`
+	LogIndex      hexutil.Uint   `json:"logIndex"`
+	Message       hexutil.Bytes  `json:"message"`
+
+	// Status and Delivery are not persisted with the message, they are filled
+	// in from the deliveries when the message is queried.
+	Status   string           `json:"status,omitempty"`
+	Delivery *MessageDelivery `json:"delivery,omitempty"`
+}
+
+// MessageDelivery describes the accepted transaction that verified a warp
+// message on this chain.
+type MessageDelivery struct {
+	DestinationChainID ids.ID         `json:"destinationChainID"`
+	DestinationAddress common.Address `json:"destinationAddress"`
+	BlockNumber        hexutil.Uint64 `json:"blockNumber"`
+	BlockHash          common.Hash    `json:"blockHash"`
+	TxHash             common.Hash    `json:"transactionHash"`
+}
+
+// MessageFilter selects the sent warp messages returned by GetMessages. All
+// fields are optional. The destination fields only match messages delivered
+// on this chain.
+type MessageFilter struct {
+	SourceAddress      *common.Address `json:"sourceAddress"`
+	DestinationChainID *ids.ID         `json:"destinationChainID"`
+	DestinationAddress *common.Address `json:"destinationAddress"`
+	FromBlock          *hexutil.Uint64 `json:"fromBlock"`
+	ToBlock            *hexutil.Uint64 `json:"toBlock"`
+	Status             string          `json:"status"`
+	Limit              hexutil.Uint64  `json:"limit"`
+	// Cursor is the NextCursor of a previous page.
+	Cursor hexutil.Bytes `json:"cursor"`
+}
+
+// MessagesPage is a page of sent warp messages ordered by block number and
+// log index. NextCursor is set if there may be more messages matching the
+// filter.
+type MessagesPage struct {
+	Messages   []*IndexedMessage `json:"messages"`
+	NextCursor hexutil.Bytes     `json:"nextCursor,omitempty"`
+}
+
+// Indexer indexes the warp messages sent from this chain by source address and
+// block number, and records which warp messages were delivered on this chain.
+type Indexer struct {
+	chainID ids.ID
+	db      database.Database
+}
+
+// NewIndexer returns an Indexer for the chain [chainID] storing its index in [db].
+func NewIndexer(chainID ids.ID, db database.Database) *Indexer {
+	return &Indexer{
+		chainID: chainID,
+		db:      db,
+	}
+}
+
+// IndexBlock indexes the warp messages sent by the accepted [block] and the
+// warp messages verified by its successful transactions. [receipts] must be
+// the receipts of [block].
+func (i *Indexer) IndexBlock(block *types.Block, receipts types.Receipts) error {
+	batch := i.db.NewBatch()
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			if log.Address != warpPrecompile.ContractAddress || len(log.Topics) < 2 || log.Topics[0] != sendWarpMessageEventID {
+				continue
+			}
+			unsignedMessage, err := warpPrecompile.UnpackSendWarpEventDataToMessage(log.Data)
+			if err != nil {
+				return fmt.Errorf("failed to parse warp log data into unsigned message (TxHash: %s, LogIndex: %d): %w", log.TxHash, log.Index, err)
+			}
+			msg := &IndexedMessage{
+				MessageID:     unsignedMessage.ID(),
+				SourceAddress: common.BytesToAddress(log.Topics[1].Bytes()),
+				BlockNumber:   hexutil.Uint64(block.NumberU64()),
+				BlockHash:     block.Hash(),
+				TxHash:        log.TxHash,
+				LogIndex:      hexutil.Uint(log.Index),
+				Message:       unsignedMessage.Bytes(),
+			}
+			msgBytes, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			position := makePosition(block.NumberU64(), log.Index)
+			if err := batch.Put(concat(blockPrefix, position), msgBytes); err != nil {
+				return err
+			}
+			if err := batch.Put(concat(addressPrefix, msg.SourceAddress[:], position), nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := i.indexDeliveries(batch, block, receipts); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// indexDeliveries writes a delivery to [batch] for every warp predicate of a
+// successful transaction in [block] that passed verification.
+func (i *Indexer) indexDeliveries(batch database.Batch, block *types.Block, receipts types.Receipts) error {
+	resultsBytes, ok := predicate.GetPredicateResultBytes(block.Extra())
+	if !ok {
+		return nil
+	}
+	results, err := predicate.ParseResults(resultsBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse predicate results of block %s: %w", block.Hash(), err)
+	}
+	txs := block.Transactions()
+	if len(txs) != len(receipts) {
+		return fmt.Errorf("block %s has %d transactions but %d receipts", block.Hash(), len(txs), len(receipts))
+	}
+	for txIndex, tx := range txs {
+		if receipts[txIndex].Status != types.ReceiptStatusSuccessful || tx.To() == nil {
+			continue
+		}
+		// Predicates are numbered in the order they appear in the access
+		// list, and the bits set in the results mark the failed ones.
+		failed := set.BitsFromBytes(results.GetResults(tx.Hash(), warpPrecompile.ContractAddress))
+		predicateIndex := 0
+		for _, tuple := range tx.AccessList() {
+			if tuple.Address != warpPrecompile.ContractAddress {
+				continue
+			}
+			index := predicateIndex
+			predicateIndex++
+			if failed.Contains(index) {
+				continue
+			}
+			messageBytes, err := predicate.UnpackPredicate(utils.HashSliceToBytes(tuple.StorageKeys))
+			if err != nil {
+				continue
+			}
+			msg, err := avalancheWarp.ParseMessage(messageBytes)
+			if err != nil {
+				continue
+			}
+			delivery, err := json.Marshal(&MessageDelivery{
+				DestinationChainID: i.chainID,
+				DestinationAddress: *tx.To(),
+				BlockNumber:        hexutil.Uint64(block.NumberU64()),
+				BlockHash:          block.Hash(),
+				TxHash:             tx.Hash(),
+			})
+			if err != nil {
+				return err
+			}
+			messageID := msg.UnsignedMessage.ID()
+			if err := batch.Put(concat(deliveryPrefix, messageID[:]), delivery); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetDelivery returns the delivery of [messageID] on this chain, or nil if it
+// has not been observed.
+func (i *Indexer) GetDelivery(messageID ids.ID) (*MessageDelivery, error) {
+	deliveryBytes, err := i.db.Get(concat(deliveryPrefix, messageID[:]))
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	delivery := new(MessageDelivery)
+	if err := json.Unmarshal(deliveryBytes, delivery); err != nil {
+		return nil, fmt.Errorf("failed to parse delivery of message %s: %w", messageID, err)
+	}
+	return delivery, nil
+}
+
+// GetMessages returns the page of sent warp messages matching [filter].
+func (i *Indexer) GetMessages(filter MessageFilter) (*MessagesPage, error) {
+	limit := uint64(filter.Limit)
+	switch {
+	case limit == 0:
+		limit = defaultMessagesLimit
+	case limit > maxMessagesLimit:
+		return nil, errInvalidMessagesLimit
+	}
+	switch filter.Status {
+	case "", MessageStatusPending, MessageStatusDelivered:
+	default:
+		return nil, errInvalidMessageStatus
+	}
+
+	var start []byte
+	if filter.FromBlock != nil {
+		start = makePosition(uint64(*filter.FromBlock), 0)
+	}
+	if len(filter.Cursor) != 0 {
+		if len(filter.Cursor) != positionLen {
+			return nil, errInvalidCursor
+		}
+		if start == nil || string(filter.Cursor) > string(start) {
+			start = filter.Cursor
+		}
+	}
+	prefix := blockPrefix
+	if filter.SourceAddress != nil {
+		prefix = concat(addressPrefix, filter.SourceAddress[:])
+	}
+	it := i.db.NewIteratorWithStartAndPrefix(concat(prefix, start), prefix)
+	defer it.Release()
+
+	page := &MessagesPage{Messages: []*IndexedMessage{}}
+	for scanned := 0; it.Next(); scanned++ {
+		position := it.Key()[len(prefix):]
+		if filter.ToBlock != nil && binary.BigEndian.Uint64(position) > uint64(*filter.ToBlock) {
+			return page, it.Error()
+		}
+		if uint64(len(page.Messages)) == limit || scanned == maxIndexScan {
+			page.NextCursor = common.CopyBytes(position)
+			return page, nil
+		}
+		msg, err := i.getMessage(position)
+		if err != nil {
+			return nil, err
+		}
+		if msg.Delivery, err = i.GetDelivery(msg.MessageID); err != nil {
+			return nil, err
+		}
+		msg.Status = MessageStatusPending
+		if msg.Delivery != nil {
+			msg.Status = MessageStatusDelivered
+		}
+		if filter.matches(msg) {
+			page.Messages = append(page.Messages, msg)
+		}
+	}
+	return page, it.Error()
+}
+
+func (i *Indexer) getMessage(position []byte) (*IndexedMessage, error) {
+	msgBytes, err := i.db.Get(concat(blockPrefix, position))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexed message at %x: %w", position, err)
+	}
+	msg := new(IndexedMessage)
+	if err := json.Unmarshal(msgBytes, msg); err != nil {
+		return nil, fmt.Errorf("failed to parse indexed message at %x: %w", position, err)
+	}
+	return msg, nil
+}
+
+func (f *MessageFilter) matches(msg *IndexedMessage) bool {
+	if f.Status != "" && f.Status != msg.Status {
+		return false
+	}
+	if f.DestinationChainID == nil && f.DestinationAddress == nil {
+		return true
+	}
+	if msg.Delivery == nil {
+		return false
+	}
+	if f.DestinationChainID != nil && *f.DestinationChainID != msg.Delivery.DestinationChainID {
+		return false
+	}
+	return f.DestinationAddress == nil || *f.DestinationAddress == msg.Delivery.DestinationAddress
+}
+
+func makePosition(blockNumber uint64, logIndex uint) []byte {
+	position := make([]byte, positionLen)
+	binary.BigEndian.PutUint64(position, blockNumber)
+	binary.BigEndian.PutUint32(position[8:], uint32(logIndex))
+	return position
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return b
+}
@@ -0,0 +1,195 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/params"
+	warpPrecompile "github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/trie"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newIndexerTestMessage(t *testing.T, sourceAddress common.Address, data string) *avalancheWarp.UnsignedMessage {
+	addressedCall, err := payload.NewAddressedCall(sourceAddress.Bytes(), []byte(data))
+	require.NoError(t, err)
+	msg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, addressedCall.Bytes())
+	require.NoError(t, err)
+	return msg
+}
+
+// newIndexerTestBlock returns a block with a transaction sending each of
+// [sent], followed by a transaction verifying each of [delivered] of which
+// the ones in [failed] fail verification.
+func newIndexerTestBlock(t *testing.T, number uint64, sent []*avalancheWarp.UnsignedMessage, delivered []*avalancheWarp.UnsignedMessage, failed set.Bits, to common.Address) (*types.Block, types.Receipts) {
+	var (
+		txs      types.Transactions
+		receipts types.Receipts
+		logIndex uint
+	)
+	for i, msg := range sent {
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(i), To: &warpPrecompile.ContractAddress})
+		addressedCall, err := payload.ParseAddressedCall(msg.Payload)
+		require.NoError(t, err)
+		topics, data, err := warpPrecompile.PackSendWarpMessageEvent(common.BytesToAddress(addressedCall.SourceAddress), common.Hash(msg.ID()), msg.Bytes())
+		require.NoError(t, err)
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{
+			Status: types.ReceiptStatusSuccessful,
+			TxHash: tx.Hash(),
+			Logs: []*types.Log{{
+				Address: warpPrecompile.ContractAddress,
+				Topics:  topics,
+				Data:    data,
+				TxHash:  tx.Hash(),
+				Index:   logIndex,
+			}},
+		})
+		logIndex++
+	}
+
+	results := predicate.NewResults()
+	if len(delivered) > 0 {
+		var accessList types.AccessList
+		for _, unsignedMsg := range delivered {
+			msg, err := avalancheWarp.NewMessage(unsignedMsg, &avalancheWarp.BitSetSignature{})
+			require.NoError(t, err)
+			accessList = append(accessList, types.AccessTuple{
+				Address:     warpPrecompile.ContractAddress,
+				StorageKeys: utils.BytesToHashSlice(predicate.PackPredicate(msg.Bytes())),
+			})
+		}
+		tx := types.NewTx(&types.DynamicFeeTx{To: &to, AccessList: accessList})
+		txs = append(txs, tx)
+		receipts = append(receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()})
+		results.SetTxResults(tx.Hash(), predicate.TxResults{warpPrecompile.ContractAddress: failed.Bytes()})
+	}
+	resultsBytes, err := results.Bytes()
+	require.NoError(t, err)
+
+	header := &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  append(make([]byte, params.DynamicFeeExtraDataSize), resultsBytes...),
+	}
+	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil)), receipts
+}
+
+func messageIDs(page *MessagesPage) []ids.ID {
+	messageIDs := make([]ids.ID, len(page.Messages))
+	for i, msg := range page.Messages {
+		messageIDs[i] = msg.MessageID
+	}
+	return messageIDs
+}
+
+func TestIndexer(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		chainID  = ids.GenerateTestID()
+		sender1  = common.Address{1}
+		sender2  = common.Address{2}
+		receiver = common.Address{3}
+		msg1     = newIndexerTestMessage(t, sender1, "1")
+		msg2     = newIndexerTestMessage(t, sender2, "2")
+		msg3     = newIndexerTestMessage(t, sender1, "3")
+		indexer  = NewIndexer(chainID, memdb.New())
+	)
+	block1, receipts1 := newIndexerTestBlock(t, 1, []*avalancheWarp.UnsignedMessage{msg1, msg2}, nil, set.Bits{}, common.Address{})
+	require.NoError(indexer.IndexBlock(block1, receipts1))
+	// msg1 is delivered back to this chain, but the predicate of msg2 fails.
+	block2, receipts2 := newIndexerTestBlock(t, 2, []*avalancheWarp.UnsignedMessage{msg3}, []*avalancheWarp.UnsignedMessage{msg1, msg2}, set.NewBits(1), receiver)
+	require.NoError(indexer.IndexBlock(block2, receipts2))
+
+	page, err := indexer.GetMessages(MessageFilter{})
+	require.NoError(err)
+	require.Equal([]ids.ID{msg1.ID(), msg2.ID(), msg3.ID()}, messageIDs(page))
+	require.Nil(page.NextCursor)
+
+	require.Equal(sender1, page.Messages[0].SourceAddress)
+	require.Equal(hexutil.Uint64(1), page.Messages[0].BlockNumber)
+	require.Equal(block1.Hash(), page.Messages[0].BlockHash)
+	require.Equal(hexutil.Bytes(msg1.Bytes()), page.Messages[0].Message)
+	require.Equal(MessageStatusDelivered, page.Messages[0].Status)
+	require.Equal(&MessageDelivery{
+		DestinationChainID: chainID,
+		DestinationAddress: receiver,
+		BlockNumber:        2,
+		BlockHash:          block2.Hash(),
+		TxHash:             block2.Transactions()[1].Hash(),
+	}, page.Messages[0].Delivery)
+	require.Equal(MessageStatusPending, page.Messages[1].Status)
+	require.Nil(page.Messages[1].Delivery)
+
+	fromBlock, toBlock := hexutil.Uint64(2), hexutil.Uint64(1)
+	tests := map[string]struct {
+		filter   MessageFilter
+		expected []ids.ID
+	}{
+		"source address": {
+			filter:   MessageFilter{SourceAddress: &sender1},
+			expected: []ids.ID{msg1.ID(), msg3.ID()},
+		},
+		"from block": {
+			filter:   MessageFilter{FromBlock: &fromBlock},
+			expected: []ids.ID{msg3.ID()},
+		},
+		"to block": {
+			filter:   MessageFilter{SourceAddress: &sender1, ToBlock: &toBlock},
+			expected: []ids.ID{msg1.ID()},
+		},
+		"pending": {
+			filter:   MessageFilter{Status: MessageStatusPending},
+			expected: []ids.ID{msg2.ID(), msg3.ID()},
+		},
+		"destination address": {
+			filter:   MessageFilter{DestinationAddress: &receiver},
+			expected: []ids.ID{msg1.ID()},
+		},
+		"other destination chain": {
+			filter:   MessageFilter{DestinationChainID: &sourceChainID},
+			expected: []ids.ID{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			page, err := indexer.GetMessages(test.filter)
+			require.NoError(err)
+			require.Equal(test.expected, messageIDs(page))
+		})
+	}
+
+	// Page through all messages one at a time.
+	var paged []ids.ID
+	filter := MessageFilter{Limit: 1}
+	for {
+		page, err := indexer.GetMessages(filter)
+		require.NoError(err)
+		paged = append(paged, messageIDs(page)...)
+		if page.NextCursor == nil {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	require.Equal([]ids.ID{msg1.ID(), msg2.ID(), msg3.ID()}, paged)
+
+	_, err = indexer.GetMessages(MessageFilter{Limit: maxMessagesLimit + 1})
+	require.ErrorIs(err, errInvalidMessagesLimit)
+	_, err = indexer.GetMessages(MessageFilter{Status: "unknown"})
+	require.ErrorIs(err, errInvalidMessageStatus)
+	_, err = indexer.GetMessages(MessageFilter{Cursor: []byte{1}})
+	require.ErrorIs(err, errInvalidCursor)
+}
@@ -0,0 +1,224 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/set"
+	"github.com/MetalBlockchain/metalgo/utils/timer/mockable"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errMessageExpired        = errors.New("expiry is in the past")
+	errSourceAddressDenied   = errors.New("source address is not allowed to register off-chain messages")
+	errExpiryExceedsTTL      = errors.New("expiry exceeds the maximum off-chain message ttl")
+	errOffChainMessageAbsent = errors.New("off-chain message is not registered")
+)
+
+// OffChainPolicy defines which off-chain messages may be registered in an
+// OffChainRegistry.
+type OffChainPolicy struct {
+	// AllowedSourceAddresses are the source addresses of the AddressedCall
+	// messages that may be registered.
+	AllowedSourceAddresses []common.Address
+	// TTL is both the lifetime of messages registered without an explicit
+	// expiry and the maximum lifetime of any registered message. Zero means
+	// messages do not expire.
+	TTL time.Duration
+}
+
+// RegisteredOffChainMessage is an off-chain message the node signs until
+// [Expiry]. A zero [Expiry] never expires.
+type RegisteredOffChainMessage struct {
+	Message *avalancheWarp.UnsignedMessage
+	Expiry  time.Time
+}
+
+// OffChainRegistry holds the off-chain AddressedCall messages registered at
+// runtime that the node is willing to sign. Registrations are persisted, and
+// expired messages are no longer signed.
+type OffChainRegistry struct {
+	networkID      uint32
+	sourceChainID  ids.ID
+	allowedSources set.Set[common.Address]
+	ttl            time.Duration
+	db             database.Database
+	clock          mockable.Clock
+
+	lock     sync.RWMutex
+	messages map[ids.ID]RegisteredOffChainMessage
+}
+
+// NewOffChainRegistry returns an OffChainRegistry applying [policy] and
+// loads the messages previously registered in [db].
+func NewOffChainRegistry(networkID uint32, sourceChainID ids.ID, policy OffChainPolicy, db database.Database) (*OffChainRegistry, error) {
+	r := &OffChainRegistry{
+		networkID:      networkID,
+		sourceChainID:  sourceChainID,
+		allowedSources: set.Of(policy.AllowedSourceAddresses...),
+		ttl:            policy.TTL,
+		db:             db,
+		messages:       make(map[ids.ID]RegisteredOffChainMessage),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		value := it.Value()
+		if len(value) < 8 {
+			return nil, fmt.Errorf("invalid off-chain message entry %x", it.Key())
+		}
+		msg, err := avalancheWarp.ParseUnsignedMessage(value[8:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse off-chain message %x: %w", it.Key(), err)
+		}
+		r.messages[msg.ID()] = RegisteredOffChainMessage{
+			Message: msg,
+			Expiry:  unixToTime(binary.BigEndian.Uint64(value)),
+		}
+	}
+	return r, it.Error()
+}
+
+// Register registers [unsignedMessage] to be signed until [expiry] and returns
+// the effective expiry. A zero [expiry] uses the policy's TTL. The message must
+// be an AddressedCall sent from an allowed source address and cannot outlive
+// the policy's TTL.
+func (r *OffChainRegistry) Register(unsignedMessage *avalancheWarp.UnsignedMessage, expiry time.Time) (time.Time, error) {
+	if unsignedMessage.NetworkID != r.networkID {
+		return time.Time{}, avalancheWarp.ErrWrongNetworkID
+	}
+	if unsignedMessage.SourceChainID != r.sourceChainID {
+		return time.Time{}, avalancheWarp.ErrWrongSourceChainID
+	}
+	addressedCall, err := payload.ParseAddressedCall(unsignedMessage.Payload)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w as AddressedCall: %w", errParsingOffChainMessage, err)
+	}
+	if len(addressedCall.SourceAddress) != common.AddressLength || !r.allowedSources.Contains(common.BytesToAddress(addressedCall.SourceAddress)) {
+		return time.Time{}, fmt.Errorf("%w: 0x%x", errSourceAddressDenied, addressedCall.SourceAddress)
+	}
+
+	now := r.clock.Time()
+	if expiry.IsZero() && r.ttl > 0 {
+		expiry = now.Add(r.ttl)
+	}
+	if !expiry.IsZero() && !expiry.After(now) {
+		return time.Time{}, errMessageExpired
+	}
+	if r.ttl > 0 && (expiry.IsZero() || expiry.After(now.Add(r.ttl))) {
+		return time.Time{}, errExpiryExceedsTTL
+	}
+
+	messageID := unsignedMessage.ID()
+	value := make([]byte, 8, 8+len(unsignedMessage.Bytes()))
+	binary.BigEndian.PutUint64(value, timeToUnix(expiry))
+	value = append(value, unsignedMessage.Bytes()...)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.db.Put(messageID[:], value); err != nil {
+		return time.Time{}, fmt.Errorf("failed to put off-chain message in db: %w", err)
+	}
+	r.messages[messageID] = RegisteredOffChainMessage{
+		Message: unsignedMessage,
+		Expiry:  expiry,
+	}
+	log.Info("Registered off-chain warp message", "messageID", messageID, "expiry", expiry)
+	return expiry, nil
+}
+
+// Unregister stops signing [messageID].
+func (r *OffChainRegistry) Unregister(messageID ids.ID) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.messages[messageID]; !ok {
+		return fmt.Errorf("%w: %s", errOffChainMessageAbsent, messageID)
+	}
+	return r.delete(messageID)
+}
+
+// Get returns the registered message with [messageID] if it has not expired.
+func (r *OffChainRegistry) Get(messageID ids.ID) (*avalancheWarp.UnsignedMessage, bool) {
+	r.lock.RLock()
+	registered, ok := r.messages[messageID]
+	r.lock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if r.expired(registered) {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		// The message may have been registered again since the read lock was
+		// released.
+		registered, ok := r.messages[messageID]
+		if !ok {
+			return nil, false
+		}
+		if !r.expired(registered) {
+			return registered.Message, true
+		}
+		if err := r.delete(messageID); err != nil {
+			log.Warn("Failed to delete expired off-chain warp message", "messageID", messageID, "err", err)
+		}
+		return nil, false
+	}
+	return registered.Message, true
+}
+
+// Messages returns the registered messages that have not expired, ordered by
+// message ID.
+func (r *OffChainRegistry) Messages() []RegisteredOffChainMessage {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	messages := make([]RegisteredOffChainMessage, 0, len(r.messages))
+	for _, registered := range r.messages {
+		if !r.expired(registered) {
+			messages = append(messages, registered)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].Message.ID().Compare(messages[j].Message.ID()) < 0
+	})
+	return messages
+}
+
+func (r *OffChainRegistry) expired(registered RegisteredOffChainMessage) bool {
+	return !registered.Expiry.IsZero() && !registered.Expiry.After(r.clock.Time())
+}
+
+// delete removes [messageID] from the registry. Assumes the lock is held.
+func (r *OffChainRegistry) delete(messageID ids.ID) error {
+	delete(r.messages, messageID)
+	return r.db.Delete(messageID[:])
+}
+
+func timeToUnix(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+func unixToTime(unix uint64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(unix), 0)
+}
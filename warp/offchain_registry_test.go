@@ -0,0 +1,149 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffChainRegistryRegister(t *testing.T) {
+	allowedSource := common.BytesToAddress(testSourceAddress)
+	deniedCall, err := payload.NewAddressedCall(common.Address{1}.Bytes(), testPayload)
+	require.NoError(t, err)
+	deniedMessage, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, deniedCall.Bytes())
+	require.NoError(t, err)
+	wrongChainMessage, err := avalancheWarp.NewUnsignedMessage(networkID, ids.GenerateTestID(), testUnsignedMessage.Payload)
+	require.NoError(t, err)
+	hashPayload, err := payload.NewHash(ids.GenerateTestID())
+	require.NoError(t, err)
+	hashMessage, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, hashPayload.Bytes())
+	require.NoError(t, err)
+
+	now := time.Unix(1_000_000, 0)
+	tests := map[string]struct {
+		message        *avalancheWarp.UnsignedMessage
+		expiry         time.Time
+		expectedExpiry time.Time
+		expectedErr    error
+	}{
+		"default ttl": {
+			message:        testUnsignedMessage,
+			expectedExpiry: now.Add(time.Hour),
+		},
+		"explicit expiry": {
+			message:        testUnsignedMessage,
+			expiry:         now.Add(time.Minute),
+			expectedExpiry: now.Add(time.Minute),
+		},
+		"expired": {
+			message:     testUnsignedMessage,
+			expiry:      now,
+			expectedErr: errMessageExpired,
+		},
+		"expiry exceeds ttl": {
+			message:     testUnsignedMessage,
+			expiry:      now.Add(2 * time.Hour),
+			expectedErr: errExpiryExceedsTTL,
+		},
+		"source address not allowed": {
+			message:     deniedMessage,
+			expectedErr: errSourceAddressDenied,
+		},
+		"wrong source chain": {
+			message:     wrongChainMessage,
+			expectedErr: avalancheWarp.ErrWrongSourceChainID,
+		},
+		"not an addressed call": {
+			message:     hashMessage,
+			expectedErr: errParsingOffChainMessage,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			registry, err := NewOffChainRegistry(networkID, sourceChainID, OffChainPolicy{
+				AllowedSourceAddresses: []common.Address{allowedSource},
+				TTL:                    time.Hour,
+			}, memdb.New())
+			require.NoError(err)
+			registry.clock.Set(now)
+
+			expiry, err := registry.Register(test.message, test.expiry)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				require.Empty(registry.Messages())
+				return
+			}
+			require.Equal(test.expectedExpiry, expiry)
+			message, ok := registry.Get(test.message.ID())
+			require.True(ok)
+			require.Equal(test.message.Bytes(), message.Bytes())
+		})
+	}
+}
+
+func TestOffChainRegistrySigning(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
+	expectedSignature, err := warpSigner.Sign(testUnsignedMessage)
+	require.NoError(err)
+
+	db := memdb.New()
+	policy := OffChainPolicy{AllowedSourceAddresses: []common.Address{common.BytesToAddress(testSourceAddress)}}
+	registry, err := NewOffChainRegistry(networkID, sourceChainID, policy, db)
+	require.NoError(err)
+	now := time.Unix(1_000_000, 0)
+	registry.clock.Set(now)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, memdb.New(), 500, nil, registry)
+	require.NoError(err)
+
+	messageID := testUnsignedMessage.ID()
+	_, err = backend.GetMessageSignature(messageID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	_, err = registry.Register(testUnsignedMessage, now.Add(time.Minute))
+	require.NoError(err)
+	signature, err := backend.GetMessageSignature(messageID)
+	require.NoError(err)
+	require.Equal(expectedSignature, signature[:])
+
+	// Registrations are persisted.
+	reloaded, err := NewOffChainRegistry(networkID, sourceChainID, policy, db)
+	require.NoError(err)
+	reloaded.clock.Set(now)
+	require.Len(reloaded.Messages(), 1)
+	require.Equal(now.Add(time.Minute), reloaded.Messages()[0].Expiry)
+
+	// Expired messages are no longer signed and are deleted.
+	registry.clock.Set(now.Add(time.Minute))
+	_, err = backend.GetMessageSignature(messageID)
+	require.ErrorIs(err, database.ErrNotFound)
+	has, err := db.Has(messageID[:])
+	require.NoError(err)
+	require.False(has)
+
+	// Unregistered messages are no longer signed.
+	_, err = registry.Register(testUnsignedMessage, time.Time{})
+	require.NoError(err)
+	_, err = backend.GetMessage(messageID)
+	require.NoError(err)
+	require.NoError(registry.Unregister(messageID))
+	_, err = backend.GetMessageSignature(messageID)
+	require.ErrorIs(err, database.ErrNotFound)
+	require.ErrorIs(registry.Unregister(messageID), errOffChainMessageAbsent)
+}
@@ -0,0 +1,75 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// OffChainMessage is a registered off-chain message as returned by the
+// OffChainAPI. A zero Expiry never expires.
+type OffChainMessage struct {
+	MessageID ids.ID         `json:"messageID"`
+	Message   hexutil.Bytes  `json:"message"`
+	Expiry    hexutil.Uint64 `json:"expiry"`
+}
+
+// OffChainAPI manages the off-chain messages of an OffChainRegistry. It must
+// only be served behind authentication.
+type OffChainAPI struct {
+	registry *OffChainRegistry
+}
+
+// NewOffChainAPI returns the off-chain message admin API of [registry].
+func NewOffChainAPI(registry *OffChainRegistry) *OffChainAPI {
+	return &OffChainAPI{registry: registry}
+}
+
+// RegisterOffChainMessage registers [message] to be signed until the unix
+// timestamp [expiry], or for the configured ttl if [expiry] is omitted.
+func (a *OffChainAPI) RegisterOffChainMessage(ctx context.Context, message hexutil.Bytes, expiry *hexutil.Uint64) (*OffChainMessage, error) {
+	unsignedMessage, err := avalancheWarp.ParseUnsignedMessage(message)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errParsingOffChainMessage, err)
+	}
+	var expiryTime time.Time
+	if expiry != nil {
+		expiryTime = unixToTime(uint64(*expiry))
+	}
+	expiryTime, err = a.registry.Register(unsignedMessage, expiryTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register off-chain message: %w", err)
+	}
+	return newOffChainMessage(RegisteredOffChainMessage{Message: unsignedMessage, Expiry: expiryTime}), nil
+}
+
+// UnregisterOffChainMessage stops signing the off-chain message [messageID].
+func (a *OffChainAPI) UnregisterOffChainMessage(ctx context.Context, messageID ids.ID) error {
+	return a.registry.Unregister(messageID)
+}
+
+// GetOffChainMessages returns the registered off-chain messages that have not
+// expired.
+func (a *OffChainAPI) GetOffChainMessages(ctx context.Context) []*OffChainMessage {
+	registered := a.registry.Messages()
+	messages := make([]*OffChainMessage, len(registered))
+	for i, msg := range registered {
+		messages[i] = newOffChainMessage(msg)
+	}
+	return messages
+}
+
+func newOffChainMessage(registered RegisteredOffChainMessage) *OffChainMessage {
+	return &OffChainMessage{
+		MessageID: registered.Message.ID(),
+		Message:   registered.Message.Bytes(),
+		Expiry:    hexutil.Uint64(timeToUnix(registered.Expiry)),
+	}
+}
@@ -0,0 +1,141 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+)
+
+const (
+	defaultMaxAttempts   = 10
+	defaultRetryInterval = 5 * time.Second
+	// defaultAggregationTimeout bounds the signature aggregation of a delivery
+	// attempt, so a message that cannot reach the quorum does not block the
+	// delivery of the others.
+	defaultAggregationTimeout = time.Minute
+	maxRetryInterval          = 10 * time.Minute
+)
+
+var (
+	errNoDestinations  = errors.New("warp relayer requires at least one destination")
+	errMissingEndpoint = errors.New("missing rpc endpoint")
+	errMultipleKeys    = errors.New("only one of the private key and the private key file may be set")
+)
+
+// Destination describes where and how accepted warp messages are delivered.
+type Destination struct {
+	// RPCEndpoint is the URL of the RPC endpoint of the destination chain.
+	RPCEndpoint string `json:"rpc-endpoint"`
+	// SourceAddresses restricts the destination to messages sent by these
+	// addresses on this chain. All messages are delivered if it is empty.
+	SourceAddresses []common.Address `json:"source-addresses,omitempty"`
+	// To is the contract on the destination chain receiving the messages.
+	To common.Address `json:"to"`
+	// Calldata is passed to [To] in the delivery transaction. The message is
+	// the only predicate of the transaction, so it can be read with
+	// getVerifiedWarpMessage(0).
+	Calldata hexutil.Bytes `json:"calldata,omitempty"`
+}
+
+// ID returns an identifier of the destination that is stable across restarts.
+func (d *Destination) ID() common.Hash {
+	return crypto.Keccak256Hash([]byte(d.RPCEndpoint), d.To.Bytes(), d.Calldata)
+}
+
+// accepts returns true if messages sent by [sourceAddress] are delivered to d.
+func (d *Destination) accepts(sourceAddress common.Address) bool {
+	if len(d.SourceAddresses) == 0 {
+		return true
+	}
+	for _, addr := range d.SourceAddresses {
+		if addr == sourceAddress {
+			return true
+		}
+	}
+	return false
+}
+
+// Config configures the warp relayer.
+type Config struct {
+	// PrivateKey is the hex encoded key of the account paying for the
+	// delivery transactions on every destination.
+	PrivateKey string
+	// PrivateKeyFile is the path of a file holding the hex encoded key, used
+	// instead of [PrivateKey].
+	PrivateKeyFile string
+	// Destinations lists the chains accepted messages are delivered to.
+	Destinations []Destination
+	// QuorumNumerator is the share of the source subnet's stake that must
+	// sign a message before it is delivered.
+	QuorumNumerator uint64
+	// MaxAttempts is the number of times a delivery is attempted before it
+	// is dropped.
+	MaxAttempts int
+	// RetryInterval is the delay before the first retry of a failed delivery.
+	// It doubles with every attempt.
+	RetryInterval time.Duration
+	// AggregationTimeout is the time given to the validators to sign a
+	// message in a delivery attempt. An attempt timing out fails.
+	AggregationTimeout time.Duration
+}
+
+// setDefaults fills in the unset fields of the config.
+func (c *Config) setDefaults() {
+	if c.QuorumNumerator == 0 {
+		c.QuorumNumerator = warp.WarpDefaultQuorumNumerator
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.RetryInterval == 0 {
+		c.RetryInterval = defaultRetryInterval
+	}
+	if c.AggregationTimeout == 0 {
+		c.AggregationTimeout = defaultAggregationTimeout
+	}
+}
+
+// Validate returns an error if the config is invalid and parses the key.
+func (c Config) Validate() (*ecdsa.PrivateKey, error) {
+	if len(c.Destinations) == 0 {
+		return nil, errNoDestinations
+	}
+	for i, dest := range c.Destinations {
+		if len(dest.RPCEndpoint) == 0 {
+			return nil, fmt.Errorf("destination %d: %w", i, errMissingEndpoint)
+		}
+	}
+	if c.QuorumNumerator != 0 && (c.QuorumNumerator < warp.WarpQuorumNumeratorMinimum || c.QuorumNumerator > warp.WarpQuorumDenominator) {
+		return nil, fmt.Errorf("quorum numerator %d must be in [%d, %d]", c.QuorumNumerator, warp.WarpQuorumNumeratorMinimum, warp.WarpQuorumDenominator)
+	}
+	if c.MaxAttempts < 0 {
+		return nil, fmt.Errorf("max attempts %d must not be negative", c.MaxAttempts)
+	}
+	if c.AggregationTimeout < 0 {
+		return nil, fmt.Errorf("aggregation timeout %s must not be negative", c.AggregationTimeout)
+	}
+	if c.PrivateKeyFile != "" {
+		if c.PrivateKey != "" {
+			return nil, errMultipleKeys
+		}
+		key, err := crypto.LoadECDSA(c.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key file: %w", err)
+		}
+		return key, nil
+	}
+	key, err := crypto.HexToECDSA(c.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,648 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package relayer implements a warp relayer running inside the VM. It delivers
+// the warp messages accepted on this chain to the configured destinations.
+package relayer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/ethclient"
+	"github.com/shubhamdubey02/subnet-evm/interfaces"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/shubhamdubey02/subnet-evm/warp/aggregator"
+)
+
+const (
+	messageChanSize = 64
+
+	receiptPollInterval = time.Second
+	receiptTimeout      = 2 * time.Minute
+
+	// replacementBumpPercent is the fee increase of a transaction replacing
+	// a pending delivery transaction, above the minimum bump of the txpool.
+	replacementBumpPercent = 12
+)
+
+var (
+	errDeliveryReverted = errors.New("delivery transaction reverted")
+	errReceiptTimeout   = errors.New("timed out waiting for delivery receipt")
+)
+
+// MessageSource provides the warp messages accepted on this chain.
+type MessageSource interface {
+	SubscribeMessages(ch chan<- *avalancheWarp.UnsignedMessage) event.Subscription
+}
+
+// SignatureAggregator aggregates the signatures of the validators of this
+// chain's subnet over a warp message.
+type SignatureAggregator interface {
+	AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error)
+}
+
+// Client is the subset of [ethclient.Client] used to deliver messages to a
+// destination chain.
+type Client interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	AcceptedNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateBaseFee(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg interfaces.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	Close()
+}
+
+func dialClient(ctx context.Context, endpoint string) (Client, error) {
+	return ethclient.DialContext(ctx, endpoint)
+}
+
+// destination is a configured destination and its connection.
+type destination struct {
+	Destination
+	client  Client
+	chainID *big.Int
+}
+
+// delivery is the persisted state of the delivery of a message to a destination.
+type delivery struct {
+	Message     []byte      `json:"message"`
+	Destination common.Hash `json:"destination"`
+	Attempts    int         `json:"attempts"`
+	NextAttempt time.Time   `json:"nextAttempt"`
+	// Nonce is the nonce of the delivery transactions once one was sent. Every
+	// resubmission reuses it, so at most one of them can be accepted.
+	Nonce *uint64 `json:"nonce,omitempty"`
+	// ChainID is the chain of the destination [Nonce] was assigned on.
+	ChainID *big.Int `json:"chainID,omitempty"`
+	// TxHashes are the hashes of the delivery transactions sent with [Nonce],
+	// the last one replacing the others.
+	TxHashes  []common.Hash `json:"txHashes,omitempty"`
+	GasFeeCap *big.Int      `json:"gasFeeCap,omitempty"`
+	GasTipCap *big.Int      `json:"gasTipCap,omitempty"`
+
+	key      []byte
+	unsigned *avalancheWarp.UnsignedMessage
+}
+
+// resetTransactions forgets the delivery transactions of [d], once none of
+// them can be accepted anymore.
+func (d *delivery) resetTransactions() {
+	d.Nonce = nil
+	d.ChainID = nil
+	d.TxHashes = nil
+	d.GasFeeCap = nil
+	d.GasTipCap = nil
+}
+
+// deliveryKey returns the database key of the delivery of [messageID] to
+// [destinationID].
+func deliveryKey(messageID [32]byte, destinationID common.Hash) []byte {
+	return append(messageID[:], destinationID[:]...)
+}
+
+// Relayer delivers the warp messages accepted on this chain to the configured
+// destinations. Deliveries that have not completed are persisted, so they are
+// resumed after a restart.
+type Relayer struct {
+	config         Config
+	key            *ecdsa.PrivateKey
+	from           common.Address
+	source         MessageSource
+	aggregator     SignatureAggregator
+	db             database.Database
+	destinations   map[common.Hash]*destination
+	dial           func(ctx context.Context, endpoint string) (Client, error)
+	receiptTimeout time.Duration
+	stats          *relayerStats
+
+	lock    sync.Mutex
+	pending map[string]*delivery
+
+	wake   chan struct{}
+	sub    event.Subscription
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a Relayer delivering the messages of [source], signed by
+// [aggregator], as described by [config]. In-flight deliveries are stored in [db].
+func New(config Config, source MessageSource, aggregator SignatureAggregator, db database.Database) (*Relayer, error) {
+	key, err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+	config.setDefaults()
+
+	r := &Relayer{
+		config:         config,
+		key:            key,
+		from:           crypto.PubkeyToAddress(key.PublicKey),
+		source:         source,
+		aggregator:     aggregator,
+		db:             db,
+		destinations:   make(map[common.Hash]*destination, len(config.Destinations)),
+		dial:           dialClient,
+		receiptTimeout: receiptTimeout,
+		stats:          newStats(),
+		pending:        make(map[string]*delivery),
+		wake:           make(chan struct{}, 1),
+	}
+	for _, dest := range config.Destinations {
+		r.destinations[dest.ID()] = &destination{Destination: dest}
+	}
+	return r, nil
+}
+
+// Subscribe loads the persisted deliveries and starts persisting a delivery
+// for each new message of the source. It is called before the chain
+// bootstraps, so the messages accepted while catching up are queued and
+// relayed once [Start] is called.
+func (r *Relayer) Subscribe() error {
+	if err := r.loadPending(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctx = ctx
+	r.cancel = cancel
+
+	messages := make(chan *avalancheWarp.UnsignedMessage, messageChanSize)
+	r.sub = r.source.SubscribeMessages(messages)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.receiveLoop(ctx, messages)
+	}()
+	return nil
+}
+
+// Start starts delivering the queued messages. It subscribes to the source
+// first if [Subscribe] was not called.
+func (r *Relayer) Start() error {
+	if r.cancel == nil {
+		if err := r.Subscribe(); err != nil {
+			return err
+		}
+	}
+	log.Info("Starting warp relayer", "address", r.from, "destinations", len(r.destinations), "pending", r.numPending())
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.deliverLoop(r.ctx)
+	}()
+	return nil
+}
+
+// Stop stops the relayer and waits for in-progress work to exit. Pending
+// deliveries remain persisted.
+func (r *Relayer) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.sub.Unsubscribe()
+	r.cancel()
+	r.wg.Wait()
+	for _, dest := range r.destinations {
+		if dest.client != nil {
+			dest.client.Close()
+		}
+	}
+}
+
+func (r *Relayer) numPending() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.pending)
+}
+
+// loadPending reads the persisted deliveries from the database.
+func (r *Relayer) loadPending() error {
+	it := r.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		d := new(delivery)
+		if err := json.Unmarshal(it.Value(), d); err != nil {
+			return fmt.Errorf("failed to parse pending delivery %x: %w", it.Key(), err)
+		}
+		d.key = common.CopyBytes(it.Key())
+		if _, ok := r.destinations[d.Destination]; !ok {
+			log.Warn("Dropping warp delivery to removed destination", "destination", d.Destination)
+			if err := r.db.Delete(d.key); err != nil {
+				return err
+			}
+			continue
+		}
+		unsigned, err := avalancheWarp.ParseUnsignedMessage(d.Message)
+		if err != nil {
+			return fmt.Errorf("failed to parse pending warp message: %w", err)
+		}
+		d.unsigned = unsigned
+		r.pending[string(d.key)] = d
+	}
+	r.stats.pendingDeliveries.Update(int64(len(r.pending)))
+	return it.Error()
+}
+
+func (r *Relayer) receiveLoop(ctx context.Context, messages <-chan *avalancheWarp.UnsignedMessage) {
+	for {
+		select {
+		case msg := <-messages:
+			if err := r.enqueue(msg); err != nil {
+				log.Error("Failed to enqueue warp message for delivery", "messageID", msg.ID(), "err", err)
+			}
+		case <-r.sub.Err():
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// enqueue persists a delivery of [msg] to each destination accepting it.
+func (r *Relayer) enqueue(msg *avalancheWarp.UnsignedMessage) error {
+	r.stats.messagesReceived.Inc(1)
+	addressedCall, err := payload.ParseAddressedCall(msg.Payload)
+	if err != nil {
+		log.Debug("Skipping warp message without addressed call payload", "messageID", msg.ID())
+		return nil
+	}
+	sourceAddress := common.BytesToAddress(addressedCall.SourceAddress)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	for id, dest := range r.destinations {
+		if !dest.accepts(sourceAddress) {
+			continue
+		}
+		key := deliveryKey(msg.ID(), id)
+		if _, ok := r.pending[string(key)]; ok {
+			continue
+		}
+		d := &delivery{
+			Message:     msg.Bytes(),
+			Destination: id,
+			NextAttempt: now,
+			key:         key,
+			unsigned:    msg,
+		}
+		if err := r.persist(d); err != nil {
+			return err
+		}
+		r.pending[string(key)] = d
+		log.Debug("Enqueued warp message for delivery", "messageID", msg.ID(), "destination", dest.RPCEndpoint)
+	}
+	r.stats.pendingDeliveries.Update(int64(len(r.pending)))
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (r *Relayer) persist(d *delivery) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return r.db.Put(d.key, b)
+}
+
+// due returns the deliveries to be attempted now, sorted by their scheduled
+// time, and the time the next delivery is due.
+func (r *Relayer) due(now time.Time) ([]*delivery, time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var (
+		due  []*delivery
+		next time.Time
+	)
+	for _, d := range r.pending {
+		if !d.NextAttempt.After(now) {
+			due = append(due, d)
+		} else if next.IsZero() || d.NextAttempt.Before(next) {
+			next = d.NextAttempt
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttempt.Before(due[j].NextAttempt) })
+	return due, next
+}
+
+func (r *Relayer) deliverLoop(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-r.wake:
+		case <-ctx.Done():
+			return
+		}
+		due, _ := r.due(time.Now())
+		for _, d := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			r.attempt(ctx, d)
+		}
+
+		// Schedule the next round for the earliest pending delivery.
+		due, next := r.due(time.Now())
+		if len(due) > 0 {
+			next = time.Now()
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// attempt delivers [d] once and updates its state with the outcome.
+func (r *Relayer) attempt(ctx context.Context, d *delivery) {
+	start := time.Now()
+	r.stats.deliveryAttempts.Inc(1)
+	dest := r.destinations[d.Destination]
+	err := r.deliver(ctx, dest, d)
+	if ctx.Err() != nil {
+		// The relayer is stopping, the delivery will be resumed after a restart.
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	defer func() { r.stats.pendingDeliveries.Update(int64(len(r.pending))) }()
+
+	if err == nil {
+		r.stats.deliveriesSucceeded.Inc(1)
+		r.stats.deliveryDuration.UpdateSince(start)
+		log.Info("Delivered warp message", "messageID", d.unsigned.ID(), "destination", dest.RPCEndpoint, "txHash", d.TxHashes[len(d.TxHashes)-1])
+		delete(r.pending, string(d.key))
+		if err := r.db.Delete(d.key); err != nil {
+			log.Error("Failed to delete warp delivery", "messageID", d.unsigned.ID(), "err", err)
+		}
+		return
+	}
+
+	r.stats.deliveryErrors.Inc(1)
+	d.Attempts++
+	if d.Attempts >= r.config.MaxAttempts {
+		r.stats.deliveriesDropped.Inc(1)
+		log.Error("Dropping warp message after failed deliveries", "messageID", d.unsigned.ID(), "destination", dest.RPCEndpoint, "attempts", d.Attempts, "err", err)
+		delete(r.pending, string(d.key))
+		if err := r.db.Delete(d.key); err != nil {
+			log.Error("Failed to delete warp delivery", "messageID", d.unsigned.ID(), "err", err)
+		}
+		return
+	}
+	delay := r.config.RetryInterval << (d.Attempts - 1)
+	if delay <= 0 || delay > maxRetryInterval {
+		delay = maxRetryInterval
+	}
+	d.NextAttempt = time.Now().Add(delay)
+	log.Warn("Failed to deliver warp message", "messageID", d.unsigned.ID(), "destination", dest.RPCEndpoint, "attempts", d.Attempts, "retryIn", delay, "err", err)
+	if err := r.persist(d); err != nil {
+		log.Error("Failed to persist warp delivery", "messageID", d.unsigned.ID(), "err", err)
+	}
+}
+
+// deliver submits [d] to [dest] and waits for the delivery transaction to be
+// accepted.
+func (r *Relayer) deliver(ctx context.Context, dest *destination, d *delivery) error {
+	if dest.client == nil {
+		client, err := r.dial(ctx, dest.RPCEndpoint)
+		if err != nil {
+			return fmt.Errorf("failed to dial destination: %w", err)
+		}
+		dest.client = client
+	}
+	client := dest.client
+
+	// If transactions were submitted by previous attempts, wait for them
+	// instead of delivering the message twice.
+	if d.Nonce != nil {
+		receipt, err := r.waitForSubmitted(ctx, client, d)
+		switch {
+		case err != nil:
+			return err
+		case receipt != nil && receipt.Status == types.ReceiptStatusSuccessful:
+			return nil
+		case receipt != nil:
+			d.resetTransactions()
+			return errDeliveryReverted
+		}
+	}
+
+	if dest.chainID == nil {
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get chain ID: %w", err)
+		}
+		dest.chainID = chainID
+	}
+	// The signatures are requested until the context is cancelled, so the
+	// aggregation is bounded to not block the other deliveries.
+	aggregateCtx, cancel := context.WithTimeout(ctx, r.config.AggregationTimeout)
+	result, err := r.aggregator.AggregateSignatures(aggregateCtx, d.unsigned, r.config.QuorumNumerator)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	signedMessage := result.Message.Bytes()
+
+	// A pending delivery transaction is replaced with the same nonce and
+	// higher fees, so only one of them can be accepted.
+	var nonce uint64
+	if d.Nonce != nil {
+		nonce = *d.Nonce
+	} else {
+		pending, err := client.PendingNonceAt(ctx, r.from)
+		if err != nil {
+			return fmt.Errorf("failed to get nonce: %w", err)
+		}
+		nonce = r.nextNonce(dest.chainID, pending)
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	baseFee, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to estimate base fee: %w", err)
+	}
+	gasFeeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, common.Big2), gasTipCap)
+	if d.Nonce != nil {
+		gasTipCap = bumpFee(gasTipCap, d.GasTipCap)
+		gasFeeCap = bumpFee(gasFeeCap, d.GasFeeCap)
+	}
+	gas, err := client.EstimateGas(ctx, interfaces.CallMsg{
+		From:      r.from,
+		To:        &dest.To,
+		GasFeeCap: gasFeeCap,
+		GasTipCap: gasTipCap,
+		Data:      dest.Calldata,
+		AccessList: types.AccessList{{
+			Address:     warp.ContractAddress,
+			StorageKeys: utils.BytesToHashSlice(predicate.PackPredicate(signedMessage)),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	tx := predicate.NewPredicateTx(dest.chainID, nonce, &dest.To, gas, gasFeeCap, gasTipCap, common.Big0, dest.Calldata, nil, warp.ContractAddress, signedMessage)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(dest.chainID), r.key)
+	if err != nil {
+		return err
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return fmt.Errorf("failed to send delivery transaction: %w", err)
+	}
+	txHash := signedTx.Hash()
+	d.Nonce = &nonce
+	d.ChainID = dest.chainID
+	d.TxHashes = append(d.TxHashes, txHash)
+	d.GasFeeCap, d.GasTipCap = gasFeeCap, gasTipCap
+	if err := r.persist(d); err != nil {
+		return err
+	}
+	log.Debug("Sent warp delivery transaction", "messageID", d.unsigned.ID(), "destination", dest.RPCEndpoint, "txHash", txHash, "nonce", nonce)
+
+	receipt, err := r.waitForReceipt(ctx, client, txHash)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		d.resetTransactions()
+		return errDeliveryReverted
+	}
+	return nil
+}
+
+// waitForSubmitted waits for one of the delivery transactions of [d] to be
+// accepted and returns its receipt. It returns a nil receipt if none of them
+// was accepted before the timeout, in which case they are replaced by the next
+// transaction, or if their nonce was used by another transaction of the
+// account, in which case they are forgotten.
+func (r *Relayer) waitForSubmitted(ctx context.Context, client Client, d *delivery) (*types.Receipt, error) {
+	receipt, err := r.waitForReceipt(ctx, client, d.TxHashes[len(d.TxHashes)-1])
+	if !errors.Is(err, errReceiptTimeout) {
+		return receipt, err
+	}
+
+	// The accepted nonce is read before the receipts, so a transaction
+	// accepted in between is not mistaken for another transaction.
+	accepted, err := client.AcceptedNonceAt(ctx, r.from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	for _, txHash := range d.TxHashes {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		switch {
+		case err == nil:
+			return receipt, nil
+		case !errors.Is(err, interfaces.NotFound):
+			return nil, err
+		}
+	}
+	if accepted > *d.Nonce {
+		log.Warn("Warp delivery nonce was used by another transaction", "messageID", d.unsigned.ID(), "nonce", *d.Nonce)
+		d.resetTransactions()
+	}
+	return nil, nil
+}
+
+// nextNonce returns the nonce of a new delivery transaction on [chainID], whose
+// pending nonce is [pending]. The transactions of other deliveries may not be
+// in the pool of the destination yet, or anymore if they timed out, so the
+// nonce is also above the nonces assigned to the deliveries in flight, which
+// are persisted with them.
+func (r *Relayer) nextNonce(chainID *big.Int, pending uint64) uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	nonce := pending
+	for _, d := range r.pending {
+		if d.Nonce != nil && d.ChainID != nil && d.ChainID.Cmp(chainID) == 0 && *d.Nonce >= nonce {
+			nonce = *d.Nonce + 1
+		}
+	}
+	return nonce
+}
+
+// bumpFee returns [fee], raised to replace a transaction paying [prev].
+func bumpFee(fee, prev *big.Int) *big.Int {
+	if prev == nil {
+		return fee
+	}
+	minFee := new(big.Int).Mul(prev, big.NewInt(100+replacementBumpPercent))
+	minFee.Div(minFee, big.NewInt(100))
+	if minFee.Cmp(prev) <= 0 {
+		minFee.Add(prev, common.Big1)
+	}
+	if fee.Cmp(minFee) < 0 {
+		return minFee
+	}
+	return fee
+}
+
+// waitForReceipt polls [client] for the receipt of [txHash].
+func (r *Relayer) waitForReceipt(ctx context.Context, client Client, txHash common.Hash) (*types.Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.receiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, interfaces.NotFound) && ctx.Err() == nil {
+			return nil, err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errReceiptTimeout
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
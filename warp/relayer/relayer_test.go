@@ -0,0 +1,463 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/shubhamdubey02/subnet-evm/core/types"
+	"github.com/shubhamdubey02/subnet-evm/interfaces"
+	"github.com/shubhamdubey02/subnet-evm/precompile/contracts/warp"
+	"github.com/shubhamdubey02/subnet-evm/predicate"
+	"github.com/shubhamdubey02/subnet-evm/utils"
+	"github.com/shubhamdubey02/subnet-evm/warp/aggregator"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	networkID     uint32 = 54321
+	sourceChainID        = ids.GenerateTestID()
+)
+
+type testSource struct {
+	feed event.Feed
+}
+
+func (s *testSource) SubscribeMessages(ch chan<- *avalancheWarp.UnsignedMessage) event.Subscription {
+	return s.feed.Subscribe(ch)
+}
+
+type testAggregator struct{}
+
+func (testAggregator) AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error) {
+	msg, err := avalancheWarp.NewMessage(unsignedMessage, &avalancheWarp.BitSetSignature{})
+	if err != nil {
+		return nil, err
+	}
+	return &aggregator.AggregateSignatureResult{Message: msg}, nil
+}
+
+// blockingAggregator waits for its context to be cancelled before failing to
+// aggregate the signatures of [blocked], and signs any other message.
+type blockingAggregator struct {
+	blocked ids.ID
+}
+
+func (a blockingAggregator) AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error) {
+	if unsignedMessage.ID() != a.blocked {
+		return testAggregator{}.AggregateSignatures(ctx, unsignedMessage, quorumNum)
+	}
+	<-ctx.Done()
+	return nil, avalancheWarp.ErrInsufficientWeight
+}
+
+// testClient accepts every transaction sent to it unless [sendErrs] is
+// non-zero, in which case the send fails and [sendErrs] is decremented, or
+// [holdTxs] is non-zero, in which case the transaction stays pending and
+// [holdTxs] is decremented.
+type testClient struct {
+	chainID *big.Int
+
+	lock     sync.Mutex
+	sendErrs int
+	holdTxs  int
+	txs      []*types.Transaction
+	held     []*types.Transaction
+}
+
+func (c *testClient) ChainID(context.Context) (*big.Int, error) { return c.chainID, nil }
+func (c *testClient) AcceptedNonceAt(context.Context, common.Address) (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return uint64(len(c.txs)), nil
+}
+func (c *testClient) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return uint64(len(c.txs) + len(c.held)), nil
+}
+func (c *testClient) SuggestGasTipCap(context.Context) (*big.Int, error) { return common.Big1, nil }
+func (c *testClient) EstimateBaseFee(context.Context) (*big.Int, error)  { return common.Big2, nil }
+func (c *testClient) EstimateGas(context.Context, interfaces.CallMsg) (uint64, error) {
+	return 100_000, nil
+}
+func (c *testClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.sendErrs > 0 {
+		c.sendErrs--
+		return errors.New("send failed")
+	}
+	if c.holdTxs > 0 {
+		c.holdTxs--
+		c.held = append(c.held, tx)
+		return nil
+	}
+	c.txs = append(c.txs, tx)
+	return nil
+}
+func (c *testClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, tx := range c.txs {
+		if tx.Hash() == txHash {
+			return &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful}, nil
+		}
+	}
+	return nil, interfaces.NotFound
+}
+func (c *testClient) Close() {}
+
+func (c *testClient) sent() []*types.Transaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]*types.Transaction(nil), c.txs...)
+}
+
+func newTestMessage(t *testing.T, sourceAddress common.Address, data []byte) *avalancheWarp.UnsignedMessage {
+	addressedCall, err := payload.NewAddressedCall(sourceAddress.Bytes(), data)
+	require.NoError(t, err)
+	msg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, addressedCall.Bytes())
+	require.NoError(t, err)
+	return msg
+}
+
+func newTestRelayer(t *testing.T, config Config, source MessageSource, clients map[string]*testClient, db *memdb.Database) *Relayer {
+	r, err := New(config, source, testAggregator{}, db)
+	require.NoError(t, err)
+	r.dial = func(_ context.Context, endpoint string) (Client, error) {
+		return clients[endpoint], nil
+	}
+	return r
+}
+
+func TestRelayer(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		sender   = common.Address{1}
+		other    = common.Address{2}
+		receiver = common.Address{3}
+		source   = &testSource{}
+		db       = memdb.New()
+		clients  = map[string]*testClient{
+			"filtered": {chainID: big.NewInt(1), sendErrs: 1},
+			"all":      {chainID: big.NewInt(2)},
+		}
+		config = Config{
+			PrivateKey: common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations: []Destination{
+				{RPCEndpoint: "filtered", SourceAddresses: []common.Address{sender}, To: receiver, Calldata: []byte{0xaa}},
+				{RPCEndpoint: "all", To: receiver},
+			},
+			RetryInterval: 10 * time.Millisecond,
+		}
+	)
+	r := newTestRelayer(t, config, source, clients, db)
+	require.NoError(r.Start())
+
+	fromSender := newTestMessage(t, sender, []byte{1})
+	fromOther := newTestMessage(t, other, []byte{2})
+	source.feed.Send(fromSender)
+	source.feed.Send(fromOther)
+
+	// The first send to "filtered" fails and is retried.
+	require.Eventually(func() bool {
+		return len(clients["filtered"].sent()) == 1 && len(clients["all"].sent()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(func() bool {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return len(r.pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	r.Stop()
+
+	tx := clients["filtered"].sent()[0]
+	require.Equal(big.NewInt(1), tx.ChainId())
+	require.Equal(receiver, *tx.To())
+	require.Equal([]byte{0xaa}, tx.Data())
+	signer := types.LatestSignerForChainID(tx.ChainId())
+	from, err := types.Sender(signer, tx)
+	require.NoError(err)
+	require.Equal(crypto.PubkeyToAddress(key.PublicKey), from)
+
+	signed, err := testAggregator{}.AggregateSignatures(context.Background(), fromSender, 0)
+	require.NoError(err)
+	require.Equal(types.AccessList{{
+		Address:     warp.ContractAddress,
+		StorageKeys: utils.BytesToHashSlice(predicate.PackPredicate(signed.Message.Bytes())),
+	}}, tx.AccessList())
+
+	// Nothing is left in the database once delivered.
+	it := db.NewIterator()
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestRelayerResumesPendingDeliveries(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		db      = memdb.New()
+		clients = map[string]*testClient{"dest": {chainID: big.NewInt(1)}}
+		config  = Config{
+			PrivateKey:    common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations:  []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+			RetryInterval: 10 * time.Millisecond,
+		}
+	)
+	// Enqueue a message without starting the relayer, as if the node stopped
+	// before delivering it.
+	r := newTestRelayer(t, config, &testSource{}, clients, db)
+	require.NoError(r.enqueue(newTestMessage(t, common.Address{1}, nil)))
+	require.Empty(clients["dest"].sent())
+
+	r = newTestRelayer(t, config, &testSource{}, clients, db)
+	require.NoError(r.Start())
+	defer r.Stop()
+	require.Eventually(func() bool {
+		return len(clients["dest"].sent()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Deliveries to destinations removed from the config are dropped.
+	db = memdb.New()
+	r = newTestRelayer(t, config, &testSource{}, clients, db)
+	require.NoError(r.enqueue(newTestMessage(t, common.Address{1}, []byte{1})))
+	config.Destinations[0].To = common.Address{4}
+	r = newTestRelayer(t, config, &testSource{}, clients, db)
+	require.NoError(r.loadPending())
+	require.Empty(r.pending)
+}
+
+func TestRelayerQueuesMessagesBeforeStart(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		source  = &testSource{}
+		clients = map[string]*testClient{"dest": {chainID: big.NewInt(1)}}
+		config  = Config{
+			PrivateKey:    common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations:  []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+			RetryInterval: 10 * time.Millisecond,
+		}
+	)
+	r := newTestRelayer(t, config, source, clients, memdb.New())
+	require.NoError(r.Subscribe())
+	defer r.Stop()
+
+	// Messages accepted while bootstrapping are queued, but not delivered
+	// until the relayer is started.
+	source.feed.Send(newTestMessage(t, common.Address{1}, nil))
+	require.Eventually(func() bool {
+		return r.numPending() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(clients["dest"].sent())
+
+	require.NoError(r.Start())
+	require.Eventually(func() bool {
+		return len(clients["dest"].sent()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRelayerAggregationTimeout(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		clients = map[string]*testClient{"dest": {chainID: big.NewInt(1)}}
+		config  = Config{
+			PrivateKey:         common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations:       []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+			RetryInterval:      time.Hour,
+			AggregationTimeout: 10 * time.Millisecond,
+		}
+		unsigned = newTestMessage(t, common.Address{1}, []byte{1})
+		signed   = newTestMessage(t, common.Address{1}, []byte{2})
+	)
+	r := newTestRelayer(t, config, &testSource{}, clients, memdb.New())
+	r.aggregator = blockingAggregator{blocked: unsigned.ID()}
+	require.NoError(r.enqueue(unsigned))
+	require.NoError(r.enqueue(signed))
+	require.NoError(r.Start())
+	defer r.Stop()
+
+	// The message that cannot be signed does not block the other one, and
+	// its attempt fails once the aggregation times out.
+	require.Eventually(func() bool {
+		return len(clients["dest"].sent()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(func() bool {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		d, ok := r.pending[string(deliveryKey(unsigned.ID(), config.Destinations[0].ID()))]
+		return ok && d.Attempts == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRelayerReplacesPendingDelivery(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		db      = memdb.New()
+		clients = map[string]*testClient{"dest": {chainID: big.NewInt(1), holdTxs: 1}}
+		config  = Config{
+			PrivateKey:    common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations:  []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+			RetryInterval: 10 * time.Millisecond,
+		}
+	)
+	r := newTestRelayer(t, config, &testSource{}, clients, db)
+	r.receiptTimeout = 50 * time.Millisecond
+	require.NoError(r.enqueue(newTestMessage(t, common.Address{1}, nil)))
+	require.NoError(r.Start())
+	defer r.Stop()
+
+	// The pending transaction is replaced with the same nonce and higher fees.
+	require.Eventually(func() bool {
+		return len(clients["dest"].sent()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	held, replacement := clients["dest"].held[0], clients["dest"].sent()[0]
+	require.Equal(held.Nonce(), replacement.Nonce())
+	require.Greater(replacement.GasTipCap().Cmp(held.GasTipCap()), 0)
+	require.Greater(replacement.GasFeeCap().Cmp(held.GasFeeCap()), 0)
+}
+
+func TestRelayerAssignsDistinctNonces(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		clients = map[string]*testClient{"dest": {chainID: big.NewInt(1), holdTxs: 1}}
+		config  = Config{
+			PrivateKey:    common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations:  []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+			RetryInterval: time.Hour,
+		}
+	)
+	r := newTestRelayer(t, config, &testSource{}, clients, memdb.New())
+	r.receiptTimeout = 50 * time.Millisecond
+	require.NoError(r.enqueue(newTestMessage(t, common.Address{1}, []byte{1})))
+	require.NoError(r.enqueue(newTestMessage(t, common.Address{1}, []byte{2})))
+	require.NoError(r.Start())
+	defer r.Stop()
+
+	// The first delivery times out with its transaction pending, and the
+	// second one does not reuse its nonce.
+	require.Eventually(func() bool {
+		return len(clients["dest"].sent()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotEqual(clients["dest"].held[0].Nonce(), clients["dest"].sent()[0].Nonce())
+}
+
+func TestNextNonce(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	config := Config{
+		PrivateKey:   common.Bytes2Hex(crypto.FromECDSA(key)),
+		Destinations: []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+	}
+	r := newTestRelayer(t, config, &testSource{}, nil, memdb.New())
+	nonce := uint64(3)
+	r.pending["inflight"] = &delivery{Nonce: &nonce, ChainID: big.NewInt(1)}
+
+	// Nonces assigned to deliveries in flight are skipped, even if their
+	// transactions are not pending on the destination anymore.
+	require.Equal(uint64(4), r.nextNonce(big.NewInt(1), 2))
+	require.Equal(uint64(5), r.nextNonce(big.NewInt(1), 5))
+	// Deliveries to other chains are ignored.
+	require.Equal(uint64(2), r.nextNonce(big.NewInt(2), 2))
+}
+
+func TestRelayerWaitsForReplacedDelivery(t *testing.T) {
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	var (
+		client = &testClient{chainID: big.NewInt(1)}
+		config = Config{
+			PrivateKey:   common.Bytes2Hex(crypto.FromECDSA(key)),
+			Destinations: []Destination{{RPCEndpoint: "dest", To: common.Address{3}}},
+		}
+		signer = types.LatestSignerForChainID(client.chainID)
+		nonce  = uint64(0)
+	)
+	r := newTestRelayer(t, config, &testSource{}, map[string]*testClient{"dest": client}, memdb.New())
+	r.receiptTimeout = 50 * time.Millisecond
+
+	// The first transaction was accepted while its replacement was rejected.
+	first, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{ChainID: client.chainID, Nonce: nonce, GasTipCap: common.Big1, GasFeeCap: common.Big2})
+	require.NoError(err)
+	require.NoError(client.SendTransaction(context.Background(), first))
+	d := &delivery{
+		Nonce:    &nonce,
+		TxHashes: []common.Hash{first.Hash(), {1}},
+		unsigned: newTestMessage(t, common.Address{1}, nil),
+	}
+	require.NoError(r.deliver(context.Background(), r.destinations[config.Destinations[0].ID()], d))
+	require.Len(client.sent(), 1)
+}
+
+func TestConfigValidate(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	privateKey := common.Bytes2Hex(crypto.FromECDSA(key))
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, crypto.SaveECDSA(keyFile, key))
+
+	tests := map[string]struct {
+		config    Config
+		expectErr error
+	}{
+		"valid": {
+			config: Config{PrivateKey: privateKey, Destinations: []Destination{{RPCEndpoint: "http://localhost:9650"}}},
+		},
+		"key file": {
+			config: Config{PrivateKeyFile: keyFile, Destinations: []Destination{{RPCEndpoint: "http://localhost:9650"}}},
+		},
+		"no destinations": {
+			config:    Config{PrivateKey: privateKey},
+			expectErr: errNoDestinations,
+		},
+		"missing endpoint": {
+			config:    Config{PrivateKey: privateKey, Destinations: []Destination{{}}},
+			expectErr: errMissingEndpoint,
+		},
+		"key and key file": {
+			config:    Config{PrivateKey: privateKey, PrivateKeyFile: "key", Destinations: []Destination{{RPCEndpoint: "http://localhost:9650"}}},
+			expectErr: errMultipleKeys,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := test.config.Validate()
+			require.ErrorIs(t, err, test.expectErr)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package relayer
+
+import (
+	"github.com/shubhamdubey02/subnet-evm/metrics"
+)
+
+type relayerStats struct {
+	messagesReceived    metrics.Counter
+	deliveryAttempts    metrics.Counter
+	deliveryErrors      metrics.Counter
+	deliveriesSucceeded metrics.Counter
+	deliveriesDropped   metrics.Counter
+	pendingDeliveries   metrics.Gauge
+	deliveryDuration    metrics.Timer
+}
+
+func newStats() *relayerStats {
+	return &relayerStats{
+		messagesReceived:    metrics.GetOrRegisterCounter("warp_relayer_messages_received", nil),
+		deliveryAttempts:    metrics.GetOrRegisterCounter("warp_relayer_delivery_attempts", nil),
+		deliveryErrors:      metrics.GetOrRegisterCounter("warp_relayer_delivery_errors", nil),
+		deliveriesSucceeded: metrics.GetOrRegisterCounter("warp_relayer_deliveries_succeeded", nil),
+		deliveriesDropped:   metrics.GetOrRegisterCounter("warp_relayer_deliveries_dropped", nil),
+		pendingDeliveries:   metrics.GetOrRegisterGauge("warp_relayer_pending_deliveries", nil),
+		deliveryDuration:    metrics.GetOrRegisterTimer("warp_relayer_delivery_duration", nil),
+	}
+}
@@ -0,0 +1,365 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/ids"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/metrics"
+)
+
+var (
+	// retentionPrefix + block number + message ID -> accepted unix time.
+	// Entries are removed when the message is pruned.
+	retentionPrefix = []byte("retention")
+	// locatorPrefix + message ID -> block number.
+	// Entries are kept when the message is pruned, so that it can be
+	// regenerated from its accepted block and signed again.
+	locatorPrefix = []byte("locator")
+
+	errMessageReaderUnavailable = errors.New("no accepted message reader")
+)
+
+// maxDeliveryChecks is the number of messages whose delivery is checked by a
+// pruning pass.
+const maxDeliveryChecks = 4096
+
+// DeliveryReader reports whether warp messages were delivered.
+type DeliveryReader interface {
+	// GetDelivery returns the delivery of [messageID], or nil if it has not
+	// been observed.
+	GetDelivery(messageID ids.ID) (*MessageDelivery, error)
+}
+
+// RetentionPolicy defines how long messages are kept in the warp database. A
+// message is pruned as soon as any of the enabled conditions holds.
+type RetentionPolicy struct {
+	// Blocks prunes messages accepted at least this many blocks before the
+	// last accepted block. Zero disables block based retention.
+	Blocks uint64
+	// Period prunes messages accepted at least this long ago. Zero disables
+	// time based retention.
+	Period time.Duration
+	// Deliveries, if non-nil, prunes messages once their delivery is observed.
+	Deliveries DeliveryReader
+}
+
+// Enabled returns true if the policy prunes any messages.
+func (p RetentionPolicy) Enabled() bool {
+	return p.Blocks > 0 || p.Period > 0 || p.Deliveries != nil
+}
+
+type backendStats struct {
+	messages            metrics.Gauge
+	messageBytes        metrics.Gauge
+	prunedMessages      metrics.Counter
+	regeneratedMessages metrics.Counter
+}
+
+func newBackendStats() *backendStats {
+	return &backendStats{
+		messages:            metrics.GetOrRegisterGauge("warp_db_messages", nil),
+		messageBytes:        metrics.GetOrRegisterGauge("warp_db_message_bytes", nil),
+		prunedMessages:      metrics.GetOrRegisterCounter("warp_db_pruned_messages", nil),
+		regeneratedMessages: metrics.GetOrRegisterCounter("warp_db_regenerated_messages", nil),
+	}
+}
+
+func retentionKey(blockNumber uint64, messageID ids.ID) []byte {
+	key := make([]byte, 8+len(messageID))
+	binary.BigEndian.PutUint64(key, blockNumber)
+	copy(key[8:], messageID[:])
+	return key
+}
+
+// trackMessage records that [messageID] was accepted in [blockNumber] at [acceptedAt].
+func (b *backend) trackMessage(messageID ids.ID, blockNumber uint64, acceptedAt time.Time) error {
+	blockNumberBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(blockNumberBytes, blockNumber)
+	if err := b.locatorDB.Put(messageID[:], blockNumberBytes); err != nil {
+		return err
+	}
+	acceptedAtBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(acceptedAtBytes, uint64(acceptedAt.Unix()))
+	return b.retentionDB.Put(retentionKey(blockNumber, messageID), acceptedAtBytes)
+}
+
+func (b *backend) PruneMessages(policy RetentionPolicy, lastAcceptedHeight uint64, now time.Time) (int, error) {
+	b.pruneLock.Lock()
+	defer b.pruneLock.Unlock()
+
+	p := &messagePruner{
+		backend:        b,
+		batch:          b.db.NewBatch(),
+		retentionBatch: b.retentionDB.NewBatch(),
+	}
+	if policy.Blocks > 0 || policy.Period > 0 {
+		if err := p.pruneExpired(policy, lastAcceptedHeight, now); err != nil {
+			return p.pruned, err
+		}
+	}
+	if policy.Deliveries != nil {
+		if err := p.pruneDelivered(policy.Deliveries); err != nil {
+			return p.pruned, err
+		}
+	}
+	b.stats.prunedMessages.Inc(int64(p.pruned))
+	return p.pruned, nil
+}
+
+// messagePruner deletes the messages of a pruning pass in batches.
+type messagePruner struct {
+	backend        *backend
+	batch          database.Batch
+	retentionBatch database.Batch
+
+	pruned       int
+	messages     int64 // messages deleted in the pending batches
+	messageBytes int64 // size of the messages deleted in the pending batches
+}
+
+// pruneExpired prunes the messages accepted before the block and time horizons
+// of [policy]. Retention entries are ordered by block number, so the expired
+// messages are the first ones and the pass stops at the first retained message
+// rather than iterating over the whole index.
+func (p *messagePruner) pruneExpired(policy RetentionPolicy, lastAcceptedHeight uint64, now time.Time) error {
+	it := p.backend.retentionDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		blockNumber := binary.BigEndian.Uint64(it.Key())
+		acceptedAt := time.Unix(int64(binary.BigEndian.Uint64(it.Value())), 0)
+		expired := (policy.Blocks > 0 && blockNumber+policy.Blocks <= lastAcceptedHeight) ||
+			(policy.Period > 0 && now.Sub(acceptedAt) >= policy.Period)
+		if !expired {
+			break
+		}
+		if err := p.prune(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return p.write()
+}
+
+// pruneDelivered prunes the delivered messages among the next
+// maxDeliveryChecks retention entries, resuming from where the previous pass
+// stopped and wrapping around at the end of the index.
+func (p *messagePruner) pruneDelivered(deliveries DeliveryReader) error {
+	it := p.backend.retentionDB.NewIteratorWithStart(p.backend.deliveryCursor)
+	defer it.Release()
+
+	p.backend.deliveryCursor = nil
+	for checked := 0; it.Next(); checked++ {
+		if checked == maxDeliveryChecks {
+			p.backend.deliveryCursor = slices.Clone(it.Key())
+			break
+		}
+		messageID, err := ids.ToID(it.Key()[8:])
+		if err != nil {
+			return fmt.Errorf("invalid warp retention key %x: %w", it.Key(), err)
+		}
+		delivery, err := deliveries.GetDelivery(messageID)
+		if err != nil {
+			return fmt.Errorf("failed to get delivery of warp message %s: %w", messageID, err)
+		}
+		if delivery == nil {
+			continue
+		}
+		if err := p.prune(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return p.write()
+}
+
+// prune deletes the message of the retention entry [key] along with its
+// retention entry. The locator of the message is kept, so that the message is
+// regenerated from its accepted block if its signature is requested again.
+func (p *messagePruner) prune(key []byte) error {
+	blockNumber := binary.BigEndian.Uint64(key)
+	messageID, err := ids.ToID(key[8:])
+	if err != nil {
+		return fmt.Errorf("invalid warp retention key %x: %w", key, err)
+	}
+
+	// A message sent again in a later block is located at that block, and is
+	// only pruned along with the retention entry of the later block.
+	locator, err := p.backend.locatorDB.Get(messageID[:])
+	switch {
+	case err == nil && binary.BigEndian.Uint64(locator) != blockNumber:
+		return p.retentionBatch.Delete(key)
+	case err != nil && !errors.Is(err, database.ErrNotFound):
+		return fmt.Errorf("failed to get locator of warp message %s: %w", messageID, err)
+	}
+
+	message, err := p.backend.db.Get(messageID[:])
+	switch {
+	case err == nil:
+		if err := p.batch.Delete(messageID[:]); err != nil {
+			return err
+		}
+		p.messages++
+		p.messageBytes += int64(len(message))
+	case !errors.Is(err, database.ErrNotFound):
+		return fmt.Errorf("failed to get warp message %s from db: %w", messageID, err)
+	}
+	if err := p.retentionBatch.Delete(key); err != nil {
+		return err
+	}
+	p.backend.messageSignatureCache.Evict(messageID)
+	p.backend.messageCache.Evict(messageID)
+	p.pruned++
+	if p.batch.Size()+p.retentionBatch.Size() >= batchSize {
+		return p.write()
+	}
+	return nil
+}
+
+// write writes the pending batches and updates the size of the database. The
+// messages are deleted before their retention entries, so an interrupted pass
+// never leaves an untracked message, and a message deleted without its entry
+// is pruned again by the next pass.
+func (p *messagePruner) write() error {
+	for _, batch := range []database.Batch{p.batch, p.retentionBatch} {
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	p.backend.addSize(-p.messages, -p.messageBytes)
+	p.messages, p.messageBytes = 0, 0
+	return nil
+}
+
+// addSize adds [messages] and [messageBytes] to the number and the total size
+// of the messages stored in the warp database.
+func (b *backend) addSize(messages int64, messageBytes int64) {
+	b.stats.messages.Update(b.messageCount.Add(messages))
+	b.stats.messageBytes.Update(b.messageBytes.Add(messageBytes))
+}
+
+// initSize counts the messages stored in the warp database. The count is then
+// kept up to date as messages are added and pruned.
+func (b *backend) initSize() error {
+	it := b.db.NewIterator()
+	defer it.Release()
+
+	var messages, messageBytes int64
+	for it.Next() {
+		// Message keys are message IDs, every other key belongs to a prefixdb
+		// and is longer.
+		if len(it.Key()) != ids.IDLen {
+			continue
+		}
+		messages++
+		messageBytes += int64(len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	b.messageCount.Store(0)
+	b.messageBytes.Store(0)
+	b.addSize(messages, messageBytes)
+	return nil
+}
+
+// regenerateMessage recovers [messageID] from the logs of the block it was
+// accepted in, if the message was pruned from the database.
+func (b *backend) regenerateMessage(messageID ids.ID) (*avalancheWarp.UnsignedMessage, error) {
+	blockNumberBytes, err := b.locatorDB.Get(messageID[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warp message %s from db: %w", messageID, err)
+	}
+	if b.messageReader == nil {
+		return nil, fmt.Errorf("failed to regenerate warp message %s: %w", messageID, errMessageReaderUnavailable)
+	}
+	blockNumber := binary.BigEndian.Uint64(blockNumberBytes)
+	messages, err := b.messageReader.GetAcceptedWarpMessages(blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate warp message %s from block %d: %w", messageID, blockNumber, err)
+	}
+	for _, message := range messages {
+		if message.ID() == messageID {
+			b.stats.regeneratedMessages.Inc(1)
+			b.messageCache.Put(messageID, message)
+			return message, nil
+		}
+	}
+	return nil, fmt.Errorf("warp message %s not found in block %d: %w", messageID, blockNumber, database.ErrNotFound)
+}
+
+// Pruner periodically prunes the warp database according to a RetentionPolicy.
+type Pruner struct {
+	backend            Backend
+	policy             RetentionPolicy
+	interval           time.Duration
+	lastAcceptedHeight func() uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPruner returns a Pruner applying [policy] to [backend] every [interval].
+// [lastAcceptedHeight] returns the height of the last accepted block.
+func NewPruner(backend Backend, policy RetentionPolicy, interval time.Duration, lastAcceptedHeight func() uint64) *Pruner {
+	return &Pruner{
+		backend:            backend,
+		policy:             policy,
+		interval:           interval,
+		lastAcceptedHeight: lastAcceptedHeight,
+		quit:               make(chan struct{}),
+	}
+}
+
+// Start starts pruning in the background.
+func (p *Pruner) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			p.prune()
+			select {
+			case <-ticker.C:
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops pruning and waits for an in-progress pass to finish.
+func (p *Pruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *Pruner) prune() {
+	start := time.Now()
+	pruned, err := p.backend.PruneMessages(p.policy, p.lastAcceptedHeight(), start)
+	if err != nil {
+		log.Error("Failed to prune warp messages", "pruned", pruned, "err", err)
+		return
+	}
+	if pruned > 0 {
+		log.Info("Pruned warp messages", "pruned", pruned, "elapsed", time.Since(start))
+	}
+}
@@ -0,0 +1,187 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/MetalBlockchain/metalgo/database"
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
+	"github.com/stretchr/testify/require"
+)
+
+// testMessageReader serves the messages accepted at each height.
+type testMessageReader map[uint64][]*avalancheWarp.UnsignedMessage
+
+func (r testMessageReader) GetAcceptedWarpMessages(height uint64) ([]*avalancheWarp.UnsignedMessage, error) {
+	return r[height], nil
+}
+
+// testDeliveryReader reports the messages it contains as delivered.
+type testDeliveryReader map[ids.ID]bool
+
+func (r testDeliveryReader) GetDelivery(messageID ids.ID) (*MessageDelivery, error) {
+	if !r[messageID] {
+		return nil, nil
+	}
+	return &MessageDelivery{}, nil
+}
+
+func TestPruneMessages(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
+
+	// messages[i] is accepted at height i+1, one hour after messages[i-1].
+	var (
+		messages   []*avalancheWarp.UnsignedMessage
+		reader     = testMessageReader{}
+		acceptedAt = time.Unix(1_000_000, 0)
+	)
+	for i := 0; i < 3; i++ {
+		addressedCall, err := payload.NewAddressedCall(testSourceAddress, []byte{byte(i)})
+		require.NoError(t, err)
+		msg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, addressedCall.Bytes())
+		require.NoError(t, err)
+		messages = append(messages, msg)
+		reader[uint64(i+1)] = []*avalancheWarp.UnsignedMessage{msg}
+	}
+
+	tests := map[string]struct {
+		policy         RetentionPolicy
+		expectedPruned []int
+	}{
+		"blocks": {
+			policy:         RetentionPolicy{Blocks: 2},
+			expectedPruned: []int{0},
+		},
+		"period": {
+			policy:         RetentionPolicy{Period: time.Hour},
+			expectedPruned: []int{0, 1},
+		},
+		"delivered": {
+			policy:         RetentionPolicy{Deliveries: testDeliveryReader{messages[1].ID(): true}},
+			expectedPruned: []int{1},
+		},
+		"any condition": {
+			policy:         RetentionPolicy{Blocks: 2, Deliveries: testDeliveryReader{messages[2].ID(): true}},
+			expectedPruned: []int{0, 2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			db := memdb.New()
+			backendIntf, err := NewBackend(networkID, sourceChainID, warpSigner, nil, reader, db, 0, nil, nil)
+			require.NoError(err)
+			b := backendIntf.(*backend)
+			for i, msg := range messages {
+				blockTimestamp := acceptedAt.Add(time.Duration(i) * time.Hour).Unix()
+				require.NoError(b.AddMessage(msg, uint64(i+1), uint64(blockTimestamp)))
+			}
+
+			pruned, err := b.PruneMessages(test.policy, 3, acceptedAt.Add(2*time.Hour))
+			require.NoError(err)
+			require.Equal(len(test.expectedPruned), pruned)
+			regenerated := b.stats.regeneratedMessages.Count()
+
+			for i, msg := range messages {
+				messageID := msg.ID()
+				isPruned := slices.Contains(test.expectedPruned, i)
+				has, err := db.Has(messageID[:])
+				require.NoError(err)
+				require.Equal(!isPruned, has)
+				has, err = b.locatorDB.Has(messageID[:])
+				require.NoError(err)
+				require.True(has)
+
+				// Pruned messages are regenerated from their accepted block and
+				// are still signed.
+				signature, err := b.GetMessageSignature(messageID)
+				require.NoError(err)
+				expectedSignature, err := warpSigner.Sign(msg)
+				require.NoError(err)
+				require.Equal(expectedSignature, signature[:])
+			}
+			require.Equal(int64(len(messages)-pruned), b.messageCount.Load())
+			require.Equal(int64(len(test.expectedPruned)), b.stats.regeneratedMessages.Count()-regenerated)
+
+			// Pruning again is a no-op.
+			pruned, err = b.PruneMessages(test.policy, 3, acceptedAt.Add(2*time.Hour))
+			require.NoError(err)
+			require.Zero(pruned)
+		})
+	}
+}
+
+func TestPruneDeliveredIncrementally(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
+	backendIntf, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, memdb.New(), 0, nil, nil)
+	require.NoError(err)
+	b := backendIntf.(*backend)
+
+	deliveries := testDeliveryReader{}
+	numMessages := maxDeliveryChecks + 1
+	for i := 0; i < numMessages; i++ {
+		addressedCall, err := payload.NewAddressedCall(testSourceAddress, []byte{byte(i), byte(i >> 8)})
+		require.NoError(err)
+		msg, err := avalancheWarp.NewUnsignedMessage(networkID, sourceChainID, addressedCall.Bytes())
+		require.NoError(err)
+		require.NoError(b.AddMessage(msg, uint64(i), 0))
+		deliveries[msg.ID()] = true
+	}
+	require.Equal(int64(numMessages), b.messageCount.Load())
+
+	// The first pass checks maxDeliveryChecks messages, the next one resumes
+	// after them.
+	policy := RetentionPolicy{Deliveries: deliveries}
+	pruned, err := b.PruneMessages(policy, uint64(numMessages), time.Now())
+	require.NoError(err)
+	require.Equal(maxDeliveryChecks, pruned)
+	pruned, err = b.PruneMessages(policy, uint64(numMessages), time.Now())
+	require.NoError(err)
+	require.Equal(1, pruned)
+	require.Zero(b.messageCount.Load())
+	require.Zero(b.messageBytes.Load())
+}
+
+func TestRegenerateMessage(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	warpSigner := avalancheWarp.NewSigner(sk, networkID, sourceChainID)
+	reader := testMessageReader{1: {testUnsignedMessage}}
+	for _, reader := range []AcceptedMessageReader{reader, nil} {
+		db := memdb.New()
+		backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, reader, db, 0, nil, nil)
+		require.NoError(err)
+		require.NoError(backend.AddMessage(testUnsignedMessage, 1, 0))
+
+		// A message deleted from the database, as by a prune, is regenerated
+		// from its accepted block.
+		messageID := testUnsignedMessage.ID()
+		require.NoError(db.Delete(messageID[:]))
+		message, err := backend.GetMessage(messageID)
+		if reader == nil {
+			require.ErrorIs(err, errMessageReaderUnavailable)
+		} else {
+			require.NoError(err)
+			require.Equal(testUnsignedMessage.Bytes(), message.Bytes())
+		}
+		_, err = backend.GetMessage(ids.GenerateTestID())
+		require.ErrorIs(err, database.ErrNotFound)
+	}
+}
@@ -12,13 +12,19 @@ import (
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
 	"github.com/MetalBlockchain/metalgo/vms/platformvm/warp/payload"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/log"
 	"github.com/shubhamdubey02/subnet-evm/peer"
 	"github.com/shubhamdubey02/subnet-evm/warp/aggregator"
 	"github.com/shubhamdubey02/subnet-evm/warp/validators"
 )
 
-var errNoValidators = errors.New("cannot aggregate signatures from subnet with no validators")
+// signatureCacheSize is the number of messages whose validator signatures
+// are cached across aggregation requests.
+const signatureCacheSize = 500
+
+var (
+	errNoValidators    = errors.New("cannot aggregate signatures from subnet with no validators")
+	errIndexerDisabled = errors.New("warp message index is not enabled")
+)
 
 // API introduces snowman specific functionality to the evm
 type API struct {
@@ -27,9 +33,12 @@ type API struct {
 	backend                       Backend
 	state                         *validators.State
 	client                        peer.NetworkClient
+	indexer                       *Indexer
+	signatureCache                *aggregator.SignatureCache
 }
 
-func NewAPI(networkID uint32, sourceSubnetID ids.ID, sourceChainID ids.ID, state *validators.State, backend Backend, client peer.NetworkClient) *API {
+// NewAPI returns the warp API. [indexer] may be nil if the message index is disabled.
+func NewAPI(networkID uint32, sourceSubnetID ids.ID, sourceChainID ids.ID, state *validators.State, backend Backend, client peer.NetworkClient, indexer *Indexer) *API {
 	return &API{
 		networkID:      networkID,
 		sourceSubnetID: sourceSubnetID,
@@ -37,6 +46,8 @@ func NewAPI(networkID uint32, sourceSubnetID ids.ID, sourceChainID ids.ID, state
 		backend:        backend,
 		state:          state,
 		client:         client,
+		indexer:        indexer,
+		signatureCache: aggregator.NewSignatureCache(signatureCacheSize),
 	}
 }
 
@@ -49,6 +60,17 @@ func (a *API) GetMessage(ctx context.Context, messageID ids.ID) (hexutil.Bytes,
 	return hexutil.Bytes(message.Bytes()), nil
 }
 
+// GetMessages returns the warp messages sent from this chain matching [filter],
+// along with their delivery status on this chain. Only deliveries verified by
+// transactions accepted on this chain are tracked: a message delivered to
+// another chain is reported as pending.
+func (a *API) GetMessages(ctx context.Context, filter MessageFilter) (*MessagesPage, error) {
+	if a.indexer == nil {
+		return nil, errIndexerDisabled
+	}
+	return a.indexer.GetMessages(filter)
+}
+
 // GetMessageSignature returns the BLS signature associated with a messageID.
 func (a *API) GetMessageSignature(ctx context.Context, messageID ids.ID) (hexutil.Bytes, error) {
 	signature, err := a.backend.GetMessageSignature(messageID)
@@ -68,6 +90,8 @@ func (a *API) GetBlockSignature(ctx context.Context, blockID ids.ID) (hexutil.By
 }
 
 // GetMessageAggregateSignature fetches the aggregate signature for the requested [messageID]
+// If the quorum is not reached, the data of the returned error is the
+// [PartialSignature] collected so far.
 func (a *API) GetMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetIDStr string) (signedMessageBytes hexutil.Bytes, err error) {
 	unsignedMessage, err := a.backend.GetMessage(messageID)
 	if err != nil {
@@ -77,6 +101,8 @@ func (a *API) GetMessageAggregateSignature(ctx context.Context, messageID ids.ID
 }
 
 // GetBlockAggregateSignature fetches the aggregate signature for the requested [blockID]
+// If the quorum is not reached, the data of the returned error is the
+// [PartialSignature] collected so far.
 func (a *API) GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetIDStr string) (signedMessageBytes hexutil.Bytes, err error) {
 	blockHashPayload, err := payload.NewHash(blockID)
 	if err != nil {
@@ -99,33 +125,42 @@ func (a *API) aggregateSignatures(ctx context.Context, unsignedMessage *warp.Uns
 		}
 		subnetID = sid
 	}
-	pChainHeight, err := a.state.GetCurrentHeight(ctx)
+	signatureResult, err := NewSignatureAggregator(subnetID, a.state, a.client, a.signatureCache).AggregateSignatures(ctx, unsignedMessage, quorumNum)
 	if err != nil {
+		if signatureResult != nil {
+			return nil, &partialSignatureError{err: err, result: signatureResult}
+		}
 		return nil, err
 	}
+	return hexutil.Bytes(signatureResult.Message.Bytes()), nil
+}
 
-	validators, totalWeight, err := warp.GetCanonicalValidatorSet(ctx, a.state, pChainHeight, subnetID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get validator set: %w", err)
-	}
-	if len(validators) == 0 {
-		return nil, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, subnetID, pChainHeight)
-	}
+// PartialSignature is the data of the error returned by the aggregate signature
+// APIs if the validators that signed the message do not reach the quorum.
+type PartialSignature struct {
+	// SignedMessage is the message signed by the validators that responded.
+	SignedMessage   hexutil.Bytes  `json:"signedMessage"`
+	SignatureWeight hexutil.Uint64 `json:"signatureWeight"`
+	TotalWeight     hexutil.Uint64 `json:"totalWeight"`
+}
 
-	log.Debug("Fetching signature",
-		"sourceSubnetID", subnetID,
-		"height", pChainHeight,
-		"numValidators", len(validators),
-		"totalWeight", totalWeight,
-	)
+// partialSignatureError reports that the quorum was not reached, along with the
+// signatures collected so far, so that callers can retry or wait for more
+// validators to sign.
+type partialSignatureError struct {
+	err    error
+	result *aggregator.AggregateSignatureResult
+}
 
-	agg := aggregator.New(aggregator.NewSignatureGetter(a.client), validators, totalWeight)
-	signatureResult, err := agg.AggregateSignatures(ctx, unsignedMessage, quorumNum)
-	if err != nil {
-		return nil, err
+func (e *partialSignatureError) Error() string { return e.err.Error() }
+
+func (e *partialSignatureError) Unwrap() error { return e.err }
+
+// ErrorData returns the partially signed message as a [PartialSignature].
+func (e *partialSignatureError) ErrorData() interface{} {
+	return &PartialSignature{
+		SignedMessage:   e.result.Message.Bytes(),
+		SignatureWeight: hexutil.Uint64(e.result.SignatureWeight),
+		TotalWeight:     hexutil.Uint64(e.result.TotalWeight),
 	}
-	// TODO: return the signature and total weight as well to the caller for more complete details
-	// Need to decide on the best UI for this and write up documentation with the potential
-	// gotchas that could impact signed messages becoming invalid.
-	return hexutil.Bytes(signatureResult.Message.Bytes()), nil
 }
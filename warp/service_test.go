@@ -0,0 +1,136 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/MetalBlockchain/metalgo/database/memdb"
+	"github.com/MetalBlockchain/metalgo/ids"
+	"github.com/MetalBlockchain/metalgo/snow"
+	"github.com/MetalBlockchain/metalgo/snow/validators"
+	"github.com/MetalBlockchain/metalgo/utils/crypto/bls"
+	"github.com/MetalBlockchain/metalgo/version"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shubhamdubey02/subnet-evm/plugin/evm/message"
+	"github.com/shubhamdubey02/subnet-evm/rpc"
+	warpValidators "github.com/shubhamdubey02/subnet-evm/warp/validators"
+	"github.com/stretchr/testify/require"
+)
+
+// testSignatureClient responds to signature requests with the signatures of
+// [signers], and with an empty signature for any other node.
+type testSignatureClient struct {
+	signers map[ids.NodeID]avalancheWarp.Signer
+	msg     *avalancheWarp.UnsignedMessage
+}
+
+func (c *testSignatureClient) SendAppRequest(_ context.Context, nodeID ids.NodeID, _ []byte) ([]byte, error) {
+	var response message.SignatureResponse
+	if signer, ok := c.signers[nodeID]; ok {
+		sig, err := signer.Sign(c.msg)
+		if err != nil {
+			return nil, err
+		}
+		copy(response.Signature[:], sig)
+	}
+	return message.Codec.Marshal(message.Version, &response)
+}
+
+func (*testSignatureClient) SendAppRequestAny(context.Context, *version.Application, []byte) ([]byte, ids.NodeID, error) {
+	return nil, ids.EmptyNodeID, errors.New("not implemented")
+}
+
+func (*testSignatureClient) SendCrossChainRequest(context.Context, ids.ID, []byte) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*testSignatureClient) TrackBandwidth(ids.NodeID, float64) {}
+
+func TestGetMessageAggregateSignaturePartial(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		nodeID1  = ids.GenerateTestNodeID()
+		nodeID2  = ids.GenerateTestNodeID()
+	)
+	sk1, err := bls.NewSecretKey()
+	require.NoError(err)
+	sk2, err := bls.NewSecretKey()
+	require.NoError(err)
+
+	snowCtx := &snow.Context{
+		NetworkID: networkID,
+		SubnetID:  subnetID,
+		ChainID:   sourceChainID,
+		ValidatorState: &validators.TestState{
+			GetCurrentHeightF: func(context.Context) (uint64, error) {
+				return 1, nil
+			},
+			GetSubnetIDF: func(context.Context, ids.ID) (ids.ID, error) {
+				return subnetID, nil
+			},
+			GetValidatorSetF: func(context.Context, uint64, ids.ID) (map[ids.NodeID]*validators.GetValidatorOutput, error) {
+				return map[ids.NodeID]*validators.GetValidatorOutput{
+					nodeID1: {NodeID: nodeID1, PublicKey: bls.PublicFromSecretKey(sk1), Weight: 50},
+					nodeID2: {NodeID: nodeID2, PublicKey: bls.PublicFromSecretKey(sk2), Weight: 50},
+				}, nil
+			},
+		},
+	}
+	warpSigner := avalancheWarp.NewSigner(sk1, networkID, sourceChainID)
+	backend, err := NewBackend(networkID, sourceChainID, warpSigner, nil, nil, memdb.New(), 500, nil, nil)
+	require.NoError(err)
+	require.NoError(backend.AddMessage(testUnsignedMessage, 1, 0))
+
+	// Only the first validator signs, which is short of the requested quorum.
+	client := &testSignatureClient{
+		signers: map[ids.NodeID]avalancheWarp.Signer{nodeID1: warpSigner},
+		msg:     testUnsignedMessage,
+	}
+	api := NewAPI(networkID, subnetID, sourceChainID, warpValidators.NewState(snowCtx), backend, client, nil)
+
+	server := rpc.NewServer(0)
+	defer server.Stop()
+	require.NoError(server.RegisterName("warp", api))
+	rpcClient := rpc.DialInProc(server)
+	defer rpcClient.Close()
+
+	var res hexutil.Bytes
+	err = rpcClient.CallContext(context.Background(), &res, "warp_getMessageAggregateSignature", testUnsignedMessage.ID(), 67, "")
+	require.ErrorContains(err, avalancheWarp.ErrInsufficientWeight.Error())
+	var dataErr rpc.DataError
+	require.ErrorAs(err, &dataErr)
+
+	// The error data holds the message signed by the first validator.
+	data, err := json.Marshal(dataErr.ErrorData())
+	require.NoError(err)
+	var partial PartialSignature
+	require.NoError(json.Unmarshal(data, &partial))
+	require.EqualValues(50, partial.SignatureWeight)
+	require.EqualValues(100, partial.TotalWeight)
+
+	signedMessage, err := avalancheWarp.ParseMessage(partial.SignedMessage)
+	require.NoError(err)
+	require.Equal(testUnsignedMessage.ID(), signedMessage.UnsignedMessage.ID())
+	numSigners, err := signedMessage.Signature.NumSigners()
+	require.NoError(err)
+	require.Equal(1, numSigners)
+
+	// The partial signature verifies once the quorum is lowered to its weight.
+	require.NoError(signedMessage.Signature.Verify(
+		context.Background(),
+		&signedMessage.UnsignedMessage,
+		networkID,
+		warpValidators.NewState(snowCtx),
+		1,
+		50,
+		100,
+	))
+}
@@ -0,0 +1,64 @@
+// (c) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MetalBlockchain/metalgo/ids"
+	avalancheWarp "github.com/MetalBlockchain/metalgo/vms/platformvm/warp"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/shubhamdubey02/subnet-evm/peer"
+	"github.com/shubhamdubey02/subnet-evm/warp/aggregator"
+	"github.com/shubhamdubey02/subnet-evm/warp/validators"
+)
+
+// SignatureAggregator aggregates signatures over warp messages from the
+// current validator set of a subnet.
+type SignatureAggregator struct {
+	subnetID ids.ID
+	state    *validators.State
+	client   peer.NetworkClient
+	cache    *aggregator.SignatureCache
+}
+
+// NewSignatureAggregator returns a SignatureAggregator requesting signatures
+// from the validators of [subnetID] over [client]. Signatures collected are
+// kept in [cache] and reused by later aggregations. [cache] may be nil.
+func NewSignatureAggregator(subnetID ids.ID, state *validators.State, client peer.NetworkClient, cache *aggregator.SignatureCache) *SignatureAggregator {
+	return &SignatureAggregator{
+		subnetID: subnetID,
+		state:    state,
+		client:   client,
+		cache:    cache,
+	}
+}
+
+// AggregateSignatures returns [unsignedMessage] signed by validators holding
+// at least [quorumNum] of the weight of the subnet at the current P-Chain height.
+func (s *SignatureAggregator) AggregateSignatures(ctx context.Context, unsignedMessage *avalancheWarp.UnsignedMessage, quorumNum uint64) (*aggregator.AggregateSignatureResult, error) {
+	pChainHeight, err := s.state.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, totalWeight, err := avalancheWarp.GetCanonicalValidatorSet(ctx, s.state, pChainHeight, s.subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator set: %w", err)
+	}
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("%w (SubnetID: %s, Height: %d)", errNoValidators, s.subnetID, pChainHeight)
+	}
+
+	log.Debug("Fetching signature",
+		"sourceSubnetID", s.subnetID,
+		"height", pChainHeight,
+		"numValidators", len(validators),
+		"totalWeight", totalWeight,
+	)
+
+	agg := aggregator.NewWithCache(aggregator.NewSignatureGetter(s.client), validators, totalWeight, s.cache)
+	return agg.AggregateSignatures(ctx, unsignedMessage, quorumNum)
+}